@@ -0,0 +1,173 @@
+// Command mockmpc is a local mock of smpc nodes for end-to-end signing tests.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc/mockmpc"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	clientIdentifier = "mockmpc"
+	// Git SHA1 commit hash of the release (set via linker flags)
+	gitCommit = ""
+	gitDate   = ""
+	// The app that holds all commands and flags.
+	app = utils.NewApp(clientIdentifier, gitCommit, gitDate, "the mock mpc command line interface")
+
+	portFlag = &cli.IntFlag{
+		Name:  "port",
+		Usage: "listen port",
+		Value: 5871,
+	}
+	apiPrefixFlag = &cli.StringFlag{
+		Name:  "apiPrefix",
+		Usage: "mpc rpc api prefix",
+		Value: "smpc_",
+	}
+	thresholdFlag = &cli.StringFlag{
+		Name:  "threshold",
+		Usage: "mpc threshold in format of 'NeededOracles/TotalOracles'",
+		Value: "2/3",
+	}
+	signGroupsFlag = &cli.StringSliceFlag{
+		Name:  "signGroup",
+		Usage: "comma separated node indexes of sign group (eg. '0,1'), can be specified multiple times",
+	}
+	privateKeysFlag = &cli.StringSliceFlag{
+		Name:  "privateKey",
+		Usage: "hex private key of mpc address, generate one if not specified",
+	}
+	signTimeoutFlag = &cli.Uint64Flag{
+		Name:  "signTimeout",
+		Usage: "sign timeout of seconds",
+		Value: 120,
+	}
+)
+
+func initApp() {
+	app.Action = mockmpcAction
+	app.HideVersion = true
+	app.Copyright = "Copyright 2017-2020 The CrossChain-Router Authors"
+	app.Flags = []cli.Flag{
+		portFlag,
+		apiPrefixFlag,
+		thresholdFlag,
+		signGroupsFlag,
+		privateKeysFlag,
+		signTimeoutFlag,
+		utils.VerbosityFlag,
+		utils.JSONFormatFlag,
+		utils.ColorFormatFlag,
+	}
+}
+
+func main() {
+	initApp()
+	if err := app.Run(os.Args); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+func mockmpcAction(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	var neededOracles, totalOracles int
+	if _, err := fmt.Sscanf(ctx.String(thresholdFlag.Name), "%d/%d", &neededOracles, &totalOracles); err != nil {
+		return fmt.Errorf("wrong threshold: %w", err)
+	}
+	signGroups, err := parseSignGroups(ctx.StringSlice(signGroupsFlag.Name), neededOracles)
+	if err != nil {
+		return err
+	}
+	server, err := mockmpc.NewServer(&mockmpc.Config{
+		APIPrefix:     ctx.String(apiPrefixFlag.Name),
+		NeededOracles: neededOracles,
+		TotalOracles:  totalOracles,
+		SignGroups:    signGroups,
+		SignTimeout:   time.Duration(ctx.Uint64(signTimeoutFlag.Name)) * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+
+	privateKeys := ctx.StringSlice(privateKeysFlag.Name)
+	if len(privateKeys) == 0 {
+		pubkey, errg := server.GenerateKey()
+		if errg != nil {
+			return errg
+		}
+		log.Info("generate mpc key", "pubkey", pubkey, "address", pubkeyToAddress(pubkey))
+	}
+	for _, privateKey := range privateKeys {
+		priv, errh := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+		if errh != nil {
+			return errh
+		}
+		pubkey := server.AddKey(priv)
+		log.Info("import mpc key", "pubkey", pubkey, "address", pubkeyToAddress(pubkey))
+	}
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", ctx.Int(portFlag.Name))
+	log.Info("mock mpc group", "groupID", server.GroupID(), "threshold", ctx.String(thresholdFlag.Name))
+	for i, enode := range server.Enodes() {
+		log.Info("mock mpc node", "index", i, "rpcAddress", mockmpc.NodeURL(baseURL, i), "enode", enode, "signGroups", server.SignGroups(i))
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", ctx.Int(portFlag.Name)),
+		Handler:           server,
+		ReadHeaderTimeout: 60 * time.Second,
+	}
+	go func() {
+		<-utils.CleanupChan
+		_ = httpServer.Close()
+	}()
+	log.Info("mock mpc server started", "port", ctx.Int(portFlag.Name))
+	if err = httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// sign groups default to one group of the first `NeededOracles` nodes
+func parseSignGroups(signGroupStrs []string, neededOracles int) ([][]int, error) {
+	if len(signGroupStrs) == 0 {
+		members := make([]int, neededOracles)
+		for i := range members {
+			members[i] = i
+		}
+		return [][]int{members}, nil
+	}
+	signGroups := make([][]int, 0, len(signGroupStrs))
+	for _, signGroupStr := range signGroupStrs {
+		parts := strings.Split(signGroupStr, ",")
+		members := make([]int, len(parts))
+		for i, part := range parts {
+			member, err := common.GetIntFromStr(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("wrong sign group '%v': %w", signGroupStr, err)
+			}
+			members[i] = member
+		}
+		signGroups = append(signGroups, members)
+	}
+	return signGroups, nil
+}
+
+func pubkeyToAddress(pubkey string) string {
+	pub, err := crypto.UnmarshalPubkey(common.FromHex(pubkey))
+	if err != nil {
+		return ""
+	}
+	return crypto.PubkeyToAddress(*pub).String()
+}
@@ -32,11 +32,11 @@ example:
 			},
 			{
 				Name:   "passbigvalue",
-				Usage:  "pass swap with big value",
+				Usage:  "pass swap with big value or held by outflow quota",
 				Action: passbigvalue,
 				Flags:  swapKeyFlags,
 				Description: `
-pass swap with big value
+pass swap with big value or held by outflow quota
 `,
 			},
 			{
@@ -55,6 +55,44 @@ reswap failed swap
 				Flags:  append(swapKeyFlags, utils.GasPriceFlag),
 				Description: `
 replace pending swap with same nonce and new gas price
+`,
+			},
+			{
+				Name:   "reverify",
+				Usage:  "reverify swap which failed to verify",
+				Action: reverify,
+				Flags:  swapKeyFlags,
+				Description: `
+reverify swap which failed to verify (eg. after fixing config)
+`,
+			},
+			{
+				Name:   "makefail",
+				Usage:  "manually make swap fail",
+				Action: makefail,
+				Flags:  append(swapKeyFlags, utils.ReasonFlag),
+				Description: `
+manually make swap fail with reason if swaptx is not sent
+`,
+			},
+			{
+				Name:   "forcestable",
+				Usage:  "force swap to be stable",
+				Action: forcestable,
+				Flags:  append(swapKeyFlags, utils.SwapTxFlag),
+				Description: `
+force swap to be stable after off-chain confirmation,
+swaptx is optional, and must be one of the sent swaptxs if specified
+`,
+			},
+			{
+				Name:   "releasesign",
+				Usage:  "release signed request which can not be resumed",
+				Action: releasesign,
+				Flags:  swapKeyFlags,
+				Description: `
+release signed request which can not be resumed after restart,
+so that the swap can be signed again (after checking the signed tx is not sent)
 `,
 			},
 		},
@@ -183,3 +221,92 @@ func replaceswap(ctx *cli.Context) error {
 	log.Printf("result is '%v'", result)
 	return err
 }
+
+func reverify(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "reverify"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func makefail(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "makefail"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+	reason := ctx.String(utils.ReasonFlag.Name)
+	if reason == "" {
+		return fmt.Errorf("must specify reason")
+	}
+
+	log.Printf("%v: %v %v %v %v", method, chainID, txid, logIndex, reason)
+
+	params := []string{chainID, txid, logIndex, reason}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func forcestable(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "forcestable"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+	swapTx := ctx.String(utils.SwapTxFlag.Name)
+
+	log.Printf("%v: %v %v %v %v", method, chainID, txid, logIndex, swapTx)
+
+	params := []string{chainID, txid, logIndex, swapTx}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func releasesign(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "releasesign"
+	err := admin.Prepare(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, txid, logIndex, err := getKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v: %v %v %v", method, chainID, txid, logIndex)
+
+	params := []string{chainID, txid, logIndex}
+	result, err := admin.SwapAdmin(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -86,6 +86,13 @@ func swaprouter(ctx *cli.Context) error {
 		rpcserver.StartAPIServer()
 	} else {
 		worker.StartRouterSwapWork(false)
+		oracleCfg := config.Oracle
+		if oracleCfg.APIPort > 0 {
+			rpcserver.StartOracleAPIServer(oracleCfg.APIPort, oracleCfg.APIPort == oracleCfg.MetricsPort)
+		}
+		if oracleCfg.MetricsPort > 0 && oracleCfg.MetricsPort != oracleCfg.APIPort {
+			rpcserver.StartMetricsServer(oracleCfg.MetricsPort)
+		}
 	}
 
 	utils.TopWaitGroup.Wait()
@@ -131,6 +131,16 @@ var (
 		Name:  "gasPrice",
 		Usage: "gas price",
 	}
+	// ReasonFlag --reason
+	ReasonFlag = &cli.StringFlag{
+		Name:  "reason",
+		Usage: "reason of manual operation",
+	}
+	// SwapTxFlag --swaptx
+	SwapTxFlag = &cli.StringFlag{
+		Name:  "swaptx",
+		Usage: "swap tx id",
+	}
 
 	// CommonLogFlags common log flags
 	CommonLogFlags = []cli.Flag{
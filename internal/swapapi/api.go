@@ -1,6 +1,7 @@
 package swapapi
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"time"
@@ -19,9 +20,20 @@ import (
 var (
 	oraclesInfo sync.Map // string -> *OracleInfo // key is enode
 
-	errAlreadyRegistered = newRPCError(-32001, "already registered")
+	errAlreadyRegistered   = newRPCError(-32001, "already registered")
+	errWrongTimeRange      = newRPCError(-32002, "wrong time range")
+	errWrongSwapAmount     = newRPCError(-32003, "wrong swap amount")
+	errNoSwapConfig        = newRPCError(-32004, "swap config not found")
+	errWrongExportRange    = newRPCError(-32005, "export requires time range 'start' and 'end' within 7 days")
+	errWrongFeeTotalsRange = newRPCError(-32006, "swap fee totals requires time range 'start' and 'end' (default now) within 31 days")
 )
 
+// MaxExportTimeSpan max time span (seconds) of one export, so that it can finish within the server write timeout
+const MaxExportTimeSpan = int64(7 * 24 * 3600)
+
+// MaxSwapFeeTotalsTimeSpan max time span (seconds) of summing swap fee totals
+const MaxSwapFeeTotalsTimeSpan = int64(31 * 24 * 3600)
+
 func newRPCError(ec rpcjson.ErrorCode, message string) error {
 	return &rpcjson.Error{
 		Code:    ec,
@@ -42,6 +54,8 @@ func GetServerInfo() *ServerInfo {
 		ExtraConfig:    params.GetExtraConfig(),
 		AllChainIDs:    router.AllChainIDs,
 		PausedChainIDs: router.GetPausedChainIDs(),
+		CircuitBreaker: worker.GetCircuitBreakerTrips(),
+		SignGroups:     mpc.GetSignGroupHealths(),
 	}
 }
 
@@ -114,9 +128,11 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 	if bridge == nil {
 		return nil, newRPCInternalError(tokens.ErrNoBridgeForChainID)
 	}
-	_, registeredOk := mongodb.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
-	if registeredOk {
-		return nil, errAlreadyRegistered
+	if logIndex != 0 {
+		_, registeredOk := mongodb.GetRegisteredRouterSwap(fromChainID, txid, logIndex)
+		if registeredOk {
+			return nil, errAlreadyRegistered
+		}
 	}
 	result := MapIntResult(make(map[int]string))
 	registerArgs := &tokens.RegisterArgs{
@@ -126,6 +142,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 	swapInfos, errs := bridge.RegisterSwap(txid, registerArgs)
 	for i, swapInfo := range swapInfos {
 		var memo string
+		var err error
 		verifyErr := errs[i]
 		if verifyErr != nil {
 			memo = verifyErr.Error()
@@ -152,7 +169,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 			case router.IsBlacklistSwap(swapInfo):
 				result[-1-logIndex] = "verify error: blacklist"
 			}
-			err = addMgoSwap(swapInfo, newStatus, memo)
+			err = worker.AddRouterSwap(swapInfo, newStatus, memo)
 		case verifyErr == nil:
 			switch {
 			case oldSwap.Status == mongodb.TxWithBigValue && router.IsBigValueSwap(swapInfo):
@@ -161,7 +178,7 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 				result[logIndex] = "already registered: blacklist"
 			case newStatus != oldSwap.Status:
 				mgoSwapInfo := mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
-				log.Info("[register] update swap info and status", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "oldStatus", oldSwap.Status, "newStatus", newStatus, "swapinfo", mgoSwapInfo)
+				log.Info("[register] update swap info and status", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "oldStatus", oldSwap.Status, "newStatus", newStatus, "swapinfo", mgoSwapInfo)
 				err = mongodb.UpdateRouterSwapInfoAndStatus(fromChainID, txid, logIndex, &mgoSwapInfo, newStatus, time.Now().Unix(), memo)
 				worker.DeleteCachedVerifyingSwap(oldSwap.Key)
 			}
@@ -169,41 +186,12 @@ func RegisterRouterSwap(fromChainID, txid, logIndexStr string) (*MapIntResult, e
 			result[logIndex] = "already registered: " + memo
 		}
 		if err != nil {
-			log.Info("register swap db error", "chainid", fromChainID, "txid", txid, "logIndex", logIndexStr, "err", err)
+			log.Info("register swap db error", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "err", err)
 		}
 	}
 	return &result, nil
 }
 
-func addMgoSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo string) (err error) {
-	valueStr := "0"
-	if swapInfo.Value != nil {
-		valueStr = swapInfo.Value.String()
-	}
-	swap := &mongodb.MgoSwap{
-		SwapType:    uint32(swapInfo.SwapType),
-		TxID:        swapInfo.Hash,
-		TxTo:        swapInfo.TxTo,
-		From:        swapInfo.From,
-		Bind:        swapInfo.Bind,
-		Value:       valueStr,
-		LogIndex:    swapInfo.LogIndex,
-		FromChainID: swapInfo.FromChainID.String(),
-		ToChainID:   swapInfo.ToChainID.String(),
-		Status:      status,
-		Timestamp:   time.Now().Unix(),
-		Memo:        memo,
-	}
-	swap.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
-	err = mongodb.AddRouterSwap(swap)
-	if err != nil {
-		log.Warn("[api] add router swap", "swap", swap, "err", err)
-	} else {
-		log.Info("[api] add router swap", "swap", swap)
-	}
-	return err
-}
-
 func getLogIndex(logindexStr string) (int, error) {
 	if logindexStr == "" {
 		return 0, nil
@@ -251,3 +239,176 @@ func GetRouterSwapHistory(fromChainID, address string, offset, limit int, status
 	}
 	return ConvertMgoSwapResultsToSwapInfos(result), nil
 }
+
+// GetRouterSwapHistoryWithCursor get swap history with filter and cursor pagination
+func GetRouterSwapHistoryWithCursor(filter *mongodb.SwapHistoryFilter, cursor string, limit int) (*SwapHistoryResult, error) {
+	if err := checkSwapHistoryFilter(filter); err != nil {
+		return nil, err
+	}
+	switch {
+	case limit == 0:
+		limit = 20 // default
+	case limit > 100:
+		limit = 100
+	case limit < -100:
+		limit = -100
+	}
+	result, nextCursor, err := mongodb.FindRouterSwapResultsWithCursor(filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapHistoryResult{
+		Swaps:      ConvertMgoSwapResultsToSwapInfos(result),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// ExportRouterSwapHistory iterate all swap history with filter in ascending order of init time
+func ExportRouterSwapHistory(ctx context.Context, filter *mongodb.SwapHistoryFilter, callback func(*SwapInfo) error) error {
+	if err := CheckExportTimeRange(filter.StartTime, filter.EndTime); err != nil {
+		return err
+	}
+	return mongodb.IterateRouterSwapResults(ctx, filter, func(res *mongodb.MgoSwapResult) error {
+		return callback(ConvertMgoSwapResultToSwapInfo(res))
+	})
+}
+
+// CheckExportTimeRange export must be in time range of unix seconds with a max span
+func CheckExportTimeRange(startTime, endTime int64) error {
+	if startTime <= 0 || endTime <= startTime || endTime-startTime > MaxExportTimeSpan {
+		return errWrongExportRange
+	}
+	return nil
+}
+
+func checkSwapHistoryFilter(filter *mongodb.SwapHistoryFilter) error {
+	if filter.StartTime < 0 || filter.EndTime < 0 || (filter.EndTime > 0 && filter.EndTime <= filter.StartTime) {
+		return errWrongTimeRange
+	}
+	return nil
+}
+
+// GetSwapFeeTotals get swap fee totals in time range of unix seconds
+func GetSwapFeeTotals(tokenID, fromChainID, toChainID string, startTime, endTime int64) ([]*mongodb.SwapFeeTotal, error) {
+	if endTime == 0 {
+		endTime = time.Now().Unix()
+	}
+	if startTime <= 0 || endTime <= startTime || endTime-startTime > MaxSwapFeeTotalsTimeSpan {
+		return nil, errWrongFeeTotalsRange
+	}
+	return mongodb.GetSwapFeeTotals(tokenID, fromChainID, toChainID, startTime, endTime)
+}
+
+// GetSwapQuote get swap quote (expected swap fee and receive amount) by the live swap configs.
+// amount is in unit of from token, from (sender), bind (receiver) and txTo are optional.
+func GetSwapQuote(tokenID, fromChainID, toChainID, amountStr, from, bind, txTo string) (*SwapQuote, error) {
+	if !tokens.IsERC20Router() {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	if fromChainID == toChainID {
+		return nil, tokens.ErrSameFromAndToChainID
+	}
+	biFromChainID, err := common.GetBigIntFromStr(fromChainID)
+	if err != nil {
+		return nil, err
+	}
+	biToChainID, err := common.GetBigIntFromStr(toChainID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := common.GetBigIntFromStr(amountStr)
+	if err != nil || amount.Sign() <= 0 {
+		return nil, errWrongSwapAmount
+	}
+	fromToken, fromDecimals, err := getMultichainTokenDecimals(tokenID, fromChainID)
+	if err != nil {
+		return nil, err
+	}
+	_, toDecimals, err := getMultichainTokenDecimals(tokenID, toChainID)
+	if err != nil {
+		return nil, err
+	}
+	swapCfg := tokens.GetSwapConfig(tokenID, toChainID)
+	if swapCfg == nil {
+		return nil, errNoSwapConfig
+	}
+
+	// check as verifying a swap tx sent now
+	swapInfo := &tokens.SwapTxInfo{
+		SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+			Token:   fromToken,
+			TokenID: tokenID,
+		}},
+		SwapType:    tokens.ERC20SwapType,
+		Timestamp:   uint64(time.Now().Unix()),
+		From:        from,
+		TxTo:        txTo,
+		Bind:        bind,
+		Value:       amount,
+		FromChainID: biFromChainID,
+		ToChainID:   biToChainID,
+	}
+
+	minSwapValue := tokens.ConvertTokenValue(swapCfg.MinimumSwap, 18, fromDecimals)
+	maxSwapValue := tokens.ConvertTokenValue(swapCfg.MaximumSwap, 18, fromDecimals)
+	bigValueThreshold := tokens.GetBigValueThreshold(tokenID, toChainID, fromDecimals)
+
+	quote := &SwapQuote{
+		TokenID:           tokenID,
+		FromChainID:       fromChainID,
+		ToChainID:         toChainID,
+		FromDecimals:      fromDecimals,
+		ToDecimals:        toDecimals,
+		Amount:            amount.String(),
+		MinimumSwap:       minSwapValue.String(),
+		MaximumSwap:       maxSwapValue.String(),
+		BigValueThreshold: bigValueThreshold.String(),
+		IsBigValue:        router.IsBigValueSwap(swapInfo),
+		FromChainPaused:   router.IsChainIDPaused(fromChainID),
+		ToChainPaused:     router.IsChainIDPaused(toChainID),
+		ChainInBlacklist:  params.IsChainIDInBlackList(fromChainID) || params.IsChainIDInBlackList(toChainID),
+		TokenInBlacklist:  params.IsTokenIDInBlackList(tokenID),
+		FromInBlacklist:   from != "" && params.IsAccountInBlackList(from),
+	}
+
+	receiveAmount, feeInfo := tokens.CalcSwapValueWithFee(tokenID, toChainID, amount, fromDecimals, toDecimals, from, txTo, swapInfo.Timestamp)
+	quote.ReceiveAmount = receiveAmount.String()
+	quote.FeeInfo = feeInfo
+	if feeInfo != nil && feeInfo.SwapFee != nil {
+		quote.SwapFee = feeInfo.SwapFee.String()
+	} else {
+		quote.SwapFee = "0"
+	}
+
+	switch {
+	case quote.FromChainPaused || quote.ToChainPaused:
+		quote.InvalidReason = "chain is paused"
+	case router.IsBlacklistSwap(swapInfo):
+		quote.InvalidReason = "swap is in blacklist"
+	case tokens.CheckTokenSwapValue(swapInfo, fromDecimals, toDecimals):
+		quote.IsValid = true
+	case amount.Cmp(minSwapValue) < 0:
+		quote.InvalidReason = "less than minimum swap value"
+	case amount.Cmp(maxSwapValue) > 0:
+		quote.InvalidReason = "greater than maximum swap value"
+	default:
+		quote.InvalidReason = "not enough to pay swap fee"
+	}
+	return quote, nil
+}
+
+func getMultichainTokenDecimals(tokenID, chainID string) (tokenAddr string, decimals uint8, err error) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return "", 0, tokens.ErrNoBridgeForChainID
+	}
+	tokenAddr = router.GetCachedMultichainToken(tokenID, chainID)
+	if tokenAddr == "" {
+		return "", 0, tokens.ErrMissTokenConfig
+	}
+	tokenCfg := bridge.GetTokenConfig(tokenAddr)
+	if tokenCfg == nil {
+		return "", 0, tokens.ErrMissTokenConfig
+	}
+	return tokenAddr, tokenCfg.Decimals, nil
+}
@@ -65,6 +65,7 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		SwapHeight:    mr.SwapHeight,
 		SwapValue:     mr.SwapValue,
 		SwapNonce:     mr.SwapNonce,
+		SwapFee:       mr.SwapFee,
 		Status:        mr.Status,
 		StatusMsg:     mr.Status.String(),
 		InitTime:      mr.InitTime,
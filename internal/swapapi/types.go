@@ -4,7 +4,9 @@ import (
 	"math/big"
 
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
 // MapIntResult type
@@ -17,7 +19,9 @@ type ServerInfo struct {
 	ConfigContract string
 	ExtraConfig    *params.ExtraConfig `json:",omitempty"`
 	AllChainIDs    []*big.Int
-	PausedChainIDs []*big.Int `json:",omitempty"`
+	PausedChainIDs []*big.Int             `json:",omitempty"`
+	CircuitBreaker map[string]string      `json:",omitempty"` // key is chainID, value is trip reason
+	SignGroups     []*mpc.SignGroupHealth `json:",omitempty"` // health of mpc sign groups
 }
 
 // OracleInfo oracle info
@@ -44,6 +48,7 @@ type SwapInfo struct {
 	SwapHeight    uint64             `json:"swapheight"`
 	SwapValue     string             `json:"swapvalue"`
 	SwapNonce     uint64             `json:"swapnonce"`
+	SwapFee       *mongodb.SwapFee   `json:"swapfee,omitempty"`
 	Status        mongodb.SwapStatus `json:"status"`
 	StatusMsg     string             `json:"statusmsg"`
 	InitTime      int64              `json:"inittime"`
@@ -53,6 +58,12 @@ type SwapInfo struct {
 	Confirmations uint64             `json:"confirmations"`
 }
 
+// SwapHistoryResult swap history with cursor of next page
+type SwapHistoryResult struct {
+	Swaps      []*SwapInfo `json:"swaps"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
 // ChainConfig rpc type
 type ChainConfig struct {
 	ChainID        string
@@ -81,3 +92,27 @@ type SwapConfig struct {
 	MaximumSwapFee        string
 	MinimumSwapFee        string
 }
+
+// SwapQuote expected result of swapping amount of token from one chain to another
+type SwapQuote struct {
+	TokenID           string              `json:"tokenID"`
+	FromChainID       string              `json:"fromChainID"`
+	ToChainID         string              `json:"toChainID"`
+	FromDecimals      uint8               `json:"fromDecimals"`
+	ToDecimals        uint8               `json:"toDecimals"`
+	Amount            string              `json:"amount"`        // in unit of from token
+	SwapFee           string              `json:"swapFee"`       // in unit of from token
+	ReceiveAmount     string              `json:"receiveAmount"` // in unit of to token
+	FeeInfo           *tokens.SwapFeeInfo `json:"feeInfo,omitempty"`
+	MinimumSwap       string              `json:"minimumSwap"`       // in unit of from token
+	MaximumSwap       string              `json:"maximumSwap"`       // in unit of from token
+	BigValueThreshold string              `json:"bigValueThreshold"` // in unit of from token
+	IsValid           bool                `json:"isValid"`
+	InvalidReason     string              `json:"invalidReason,omitempty"`
+	IsBigValue        bool                `json:"isBigValue"`
+	FromChainPaused   bool                `json:"fromChainPaused"`
+	ToChainPaused     bool                `json:"toChainPaused"`
+	ChainInBlacklist  bool                `json:"chainInBlacklist"`
+	TokenInBlacklist  bool                `json:"tokenInBlacklist"`
+	FromInBlacklist   bool                `json:"fromInBlacklist"`
+}
@@ -0,0 +1,154 @@
+// Package metrics provides prometheus metrics of the router server and oracle.
+package metrics
+
+import (
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "router"
+
+var (
+	swapCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "swap_count",
+		Help:      "Count of recent swaps by from chain, table and status.",
+	}, []string{"chainid", "table", "status"})
+
+	jobLoopDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_loop_duration_seconds",
+		Help:      "Duration of one loop of worker jobs.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"job", "chainid"})
+
+	swapTaskChanDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "swap_task_chan_depth",
+		Help:      "Count of swap tasks waiting in the channel of dest chain.",
+	}, []string{"chainid"})
+
+	swapTaskChanCapacity = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "swap_task_chan_capacity",
+		Help:      "Capacity of the swap task channel of each dest chain.",
+	})
+
+	mpcSignDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mpc_sign_duration_seconds",
+		Help:      "Duration of mpc sign requests.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"signtype"})
+
+	mpcSignFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mpc_sign_failures_total",
+		Help:      "Count of failed mpc sign requests.",
+	}, []string{"signtype"})
+
+	rpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_request_duration_seconds",
+		Help:      "Duration of rpc requests to gateways.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"url"})
+
+	rpcRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_request_errors_total",
+		Help:      "Count of failed rpc requests to gateways.",
+	}, []string{"url"})
+
+	nonceGap = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "nonce_gap",
+		Help:      "Gap between pending and latest nonce of signer.",
+	}, []string{"chainid", "account"})
+
+	signerBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "signer_balance",
+		Help:      "Native balance of signer in wei unit.",
+	}, []string{"chainid", "account"})
+
+	acceptSignCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "accept_sign_total",
+		Help:      "Count of accept sign results of oracle.",
+	}, []string{"result"})
+)
+
+// Handler metrics http handler
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ResetSwapCounts reset all swap counts
+func ResetSwapCounts() {
+	swapCount.Reset()
+}
+
+// SetSwapCount set swap count of chain, table and status
+func SetSwapCount(chainID, table, status string, count int) {
+	swapCount.WithLabelValues(chainID, table, status).Set(float64(count))
+}
+
+// ObserveJobLoop observe duration of one job loop
+func ObserveJobLoop(job, chainID string, start time.Time) {
+	jobLoopDuration.WithLabelValues(job, chainID).Observe(time.Since(start).Seconds())
+}
+
+// SetSwapTaskChanDepth set swap task channel depth and capacity
+func SetSwapTaskChanDepth(chainID string, depth, capacity int) {
+	swapTaskChanDepth.WithLabelValues(chainID).Set(float64(depth))
+	swapTaskChanCapacity.Set(float64(capacity))
+}
+
+// ObserveMPCSign observe mpc sign duration and failure
+func ObserveMPCSign(signType string, start time.Time, err error) {
+	mpcSignDuration.WithLabelValues(signType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		mpcSignFailures.WithLabelValues(signType).Inc()
+	}
+}
+
+// ObserveRPCRequest observe rpc request duration and error
+func ObserveRPCRequest(rawURL string, start time.Time, err error) {
+	label := urlLabel(rawURL)
+	rpcRequestDuration.WithLabelValues(label).Observe(time.Since(start).Seconds())
+	if err != nil {
+		rpcRequestErrors.WithLabelValues(label).Inc()
+	}
+}
+
+// SetNonceGap set nonce gap of signer
+func SetNonceGap(chainID, account string, gap uint64) {
+	nonceGap.WithLabelValues(chainID, account).Set(float64(gap))
+}
+
+// SetSignerBalance set native balance of signer
+func SetSignerBalance(chainID, account string, balance *big.Int) {
+	value, _ := new(big.Float).SetInt(balance).Float64()
+	signerBalance.WithLabelValues(chainID, account).Set(value)
+}
+
+// AddAcceptSign count accept sign result
+func AddAcceptSign(result string) {
+	acceptSignCount.WithLabelValues(result).Inc()
+}
+
+// urlLabel keep scheme and host only, as path and query may contain api keys
+func urlLabel(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import "testing"
+
+func TestURLLabel(t *testing.T) {
+	cases := map[string]string{
+		"https://mainnet.infura.io/v3/secretkey":    "https://mainnet.infura.io",
+		"http://127.0.0.1:8545":                     "http://127.0.0.1:8545",
+		"https://api.example.com/rpc?apikey=secret": "https://api.example.com",
+		"not a url":                                 "unknown",
+		"":                                          "unknown",
+	}
+	for rawURL, want := range cases {
+		if got := urlLabel(rawURL); got != want {
+			t.Errorf("urlLabel(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
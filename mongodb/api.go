@@ -2,18 +2,25 @@ package mongodb
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -30,6 +37,9 @@ var (
 	updateOldSwapTxsLock sync.Mutex
 
 	maxCountOfResults = int64(1000)
+
+	swapStatusListeners     []func(*SwapNotifyEvent)
+	swapStatusListenersLock sync.RWMutex
 )
 
 // GetRouterSwapKey get router swap key
@@ -45,6 +55,11 @@ func AddRouterSwap(ms *MgoSwap) error {
 	switch {
 	case err == nil:
 		log.Info("mongodb add router swap success", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex)
+		event := NotifyEventRegistered
+		if ms.Status != TxNotStable {
+			event = ms.Status.GetNotifyEvent()
+		}
+		AddSwapNotifyEvent(event, ms.FromChainID, ms.TxID, ms.LogIndex, ms.Status, "", ms.Memo)
 	case !mongo.IsDuplicateKeyError(err):
 		log.Error("mongodb add router swap failed", "chainid", ms.FromChainID, "txid", ms.TxID, "logindex", ms.LogIndex, "err", err)
 	default:
@@ -78,6 +93,7 @@ func PassRouterSwapVerify(fromChainID, txid string, logindex int, timestamp int6
 	_, err = collRouterSwap.UpdateByID(clientCtx, key, bson.M{"$set": updates})
 	if err == nil {
 		log.Info("mongodb pass verify success", "chainid", fromChainID, "txid", txid, "logindex", logindex)
+		AddSwapNotifyEvent(NotifyEventVerified, fromChainID, txid, logindex, TxNotSwapped, "", "")
 	} else {
 		log.Error("mongodb pass verify failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
 	}
@@ -100,6 +116,7 @@ func UpdateRouterSwapStatus(fromChainID, txid string, logindex int, status SwapS
 	if err == nil {
 		logFunc := log.GetPrintFuncOr(func() bool { return status == TxVerifyFailed }, log.Warn, log.Info)
 		logFunc("mongodb update router swap status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
+		AddSwapNotifyEvent(status.GetNotifyEvent(), fromChainID, txid, logindex, status, "", memo)
 	} else {
 		log.Error("mongodb update router swap status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
@@ -233,6 +250,61 @@ func FindRouterSwapsWithChainIDAndStatus(fromChainID string, status SwapStatus,
 	return result, nil
 }
 
+// FindRouterSwapsQueuedForLiquidity find router swaps queued for liquidity (first-in first-out)
+func FindRouterSwapsQueuedForLiquidity() ([]*MgoSwap, error) {
+	query := bson.M{"status": TxQueuedForLiquidity}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "inittime", Value: 1}},
+		Limit: &maxCountOfResults,
+	}
+	cur, err := collRouterSwap.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwap, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// HasEarlierQueuedRouterSwap has router swap of the same tokenID and dest chain queued before init time
+func HasEarlierQueuedRouterSwap(toChainID, tokenID string, initTime int64) (bool, error) {
+	query := bson.M{
+		"status":                          TxQueuedForLiquidity,
+		"toChainID":                       toChainID,
+		"swapinfo.routerSwapInfo.tokenID": tokenID,
+		"inittime":                        bson.M{"$lt": initTime},
+	}
+	limit := int64(1)
+	count, err := collRouterSwap.CountDocuments(clientCtx, query, &options.CountOptions{Limit: &limit})
+	if err != nil {
+		return false, mgoError(err)
+	}
+	return count > 0, nil
+}
+
+// ReleaseQueuedRouterSwap release router swap queued for liquidity (optional pay anyToken)
+func ReleaseQueuedRouterSwap(fromChainID, txid string, logindex int, payAnyToken bool) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"status": TxNotSwapped, "timestamp": common.Now(), "memo": ""}
+	if payAnyToken {
+		updates["payanytoken"] = true
+	}
+	filter := bson.M{"_id": key, "status": TxQueuedForLiquidity}
+	res, err := collRouterSwap.UpdateOne(clientCtx, filter, bson.M{"$set": updates})
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		log.Info("mongodb release queued router swap success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "payAnyToken", payAnyToken)
+	} else {
+		log.Warn("mongodb release queued router swap failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "payAnyToken", payAnyToken, "err", err)
+	}
+	return mgoError(err)
+}
+
 // AddRouterSwapResult add router swap result
 func AddRouterSwapResult(mr *MgoSwapResult) error {
 	mr.Key = GetRouterSwapKey(mr.FromChainID, mr.TxID, mr.LogIndex)
@@ -277,6 +349,9 @@ func AllocateRouterSwapNonce(args *tokens.BuildTxArgs, nonceptr *uint64, isRecyc
 	if args.SwapValue != nil {
 		resUpdates["swapvalue"] = args.SwapValue.String()
 	}
+	if swapFee := ConvertToSwapFee(args.SwapFeeInfo); swapFee != nil {
+		resUpdates["swapfee"] = swapFee
+	}
 	_, err = collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": resUpdates})
 	if err != nil {
 		log.Warn("mongodb allocate swap nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", err)
@@ -317,6 +392,7 @@ func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status
 	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
 	if err == nil {
 		log.Info("mongodb update swap result status success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status)
+		AddSwapNotifyEvent(status.GetNotifyEvent(), fromChainID, txid, logindex, status, "", memo)
 	} else {
 		log.Error("mongodb update swap result status failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "status", status, "err", err)
 	}
@@ -602,6 +678,194 @@ func FindRouterSwapResults(fromChainID, address string, offset, limit int, statu
 	return result, nil
 }
 
+// getSwapHistoryQuery get query of swap history filter, and which collection to query
+func getSwapHistoryQuery(filter *SwapHistoryFilter) (queries []bson.M, isInResultColl bool, err error) {
+	if address := filter.Address; address != "" && address != allAddresses {
+		if common.IsHexAddress(address) {
+			address = strings.ToLower(address)
+		}
+		queries = append(queries, bson.M{"from": address})
+	}
+	if filter.FromChainID != "" && filter.FromChainID != allChainIDs {
+		queries = append(queries, bson.M{"fromChainID": filter.FromChainID})
+	}
+	if filter.ToChainID != "" && filter.ToChainID != allChainIDs {
+		queries = append(queries, bson.M{"toChainID": filter.ToChainID})
+	}
+	if filter.TokenID != "" {
+		queries = append(queries, bson.M{"$or": []bson.M{
+			{"swapinfo.routerSwapInfo.tokenID": filter.TokenID},
+			{"swapinfo.nftSwapInfo.tokenID": filter.TokenID},
+		}})
+	}
+	if filter.SwapType != nil {
+		queries = append(queries, bson.M{"swaptype": *filter.SwapType})
+	}
+
+	registerStatuses, resultStatuses := getStatusesFromStr(filter.Status)
+	filterStatuses, isInResultColl := resultStatuses, true
+	if len(resultStatuses) == 0 && len(registerStatuses) > 0 {
+		filterStatuses = registerStatuses
+		isInResultColl = false
+	}
+	if len(filterStatuses) > 0 {
+		queries = append(queries, bson.M{"status": bson.M{"$in": filterStatuses}})
+	}
+
+	if filter.StartTime > 0 || filter.EndTime > 0 {
+		timeQuery := bson.M{}
+		if filter.StartTime > 0 {
+			timeQuery["$gte"] = filter.StartTime * 1000 // init time is milli seconds
+		}
+		if filter.EndTime > 0 {
+			timeQuery["$lt"] = filter.EndTime * 1000
+		}
+		queries = append(queries, bson.M{"inittime": timeQuery})
+	}
+
+	for _, bound := range []struct {
+		value string
+		op    string
+	}{
+		{filter.MinValue, "$gte"},
+		{filter.MaxValue, "$lte"},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		value, errp := primitive.ParseDecimal128(bound.value)
+		if errp != nil {
+			return nil, false, fmt.Errorf("wrong value filter '%v'", bound.value)
+		}
+		queries = append(queries, bson.M{"$expr": bson.M{bound.op: bson.A{bson.M{"$toDecimal": "$value"}, value}}})
+	}
+	return queries, isInResultColl, nil
+}
+
+// encodeSwapHistoryCursor encode position of the last returned swap
+func encodeSwapHistoryCursor(initTime int64, key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s", initTime, key)))
+}
+
+func decodeSwapHistoryCursor(cursor string) (initTime int64, key string, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrWrongCursor
+	}
+	parts := strings.SplitN(string(data), "|", 2)
+	if len(parts) != 2 {
+		return 0, "", ErrWrongCursor
+	}
+	initTime, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", ErrWrongCursor
+	}
+	return initTime, parts[1], nil
+}
+
+func findSwapHistory(ctx context.Context, filter *SwapHistoryFilter, cursor string, descending bool, opts *options.FindOptions) (cur *mongo.Cursor, isInResultColl bool, err error) {
+	queries, isInResultColl, err := getSwapHistoryQuery(filter)
+	if err != nil {
+		return nil, false, err
+	}
+	sortOrder, cmpOp := 1, "$gt"
+	if descending {
+		sortOrder, cmpOp = -1, "$lt"
+	}
+	if cursor != "" {
+		initTime, key, errd := decodeSwapHistoryCursor(cursor)
+		if errd != nil {
+			return nil, false, errd
+		}
+		queries = append(queries, bson.M{"$or": []bson.M{
+			{"inittime": bson.M{cmpOp: initTime}},
+			{"inittime": initTime, "_id": bson.M{cmpOp: key}},
+		}})
+	}
+	query := bson.M{}
+	if len(queries) > 0 {
+		query = bson.M{"$and": queries}
+	}
+	opts = opts.SetSort(bson.D{{Key: "inittime", Value: sortOrder}, {Key: "_id", Value: sortOrder}})
+
+	coll := collRouterSwapResult
+	if !isInResultColl {
+		coll = collRouterSwap
+	}
+	cur, err = coll.Find(ctx, query, opts)
+	if err != nil {
+		return nil, false, mgoError(err)
+	}
+	return cur, isInResultColl, nil
+}
+
+func decodeSwapHistory(cur *mongo.Cursor, isInResultColl bool) (*MgoSwapResult, error) {
+	if isInResultColl {
+		result := &MgoSwapResult{}
+		err := cur.Decode(result)
+		return result, err
+	}
+	swap := &MgoSwap{}
+	err := cur.Decode(swap)
+	if err != nil {
+		return nil, err
+	}
+	return swap.ToSwapResult(), nil
+}
+
+// FindRouterSwapResultsWithCursor find router swap results with filter and cursor pagination,
+// negative limit means descending order of init time. return empty next cursor if no more results.
+func FindRouterSwapResultsWithCursor(filter *SwapHistoryFilter, cursor string, limit int) (result []*MgoSwapResult, nextCursor string, err error) {
+	descending := limit < 0
+	if descending {
+		limit = -limit
+	}
+	opts := options.Find().SetLimit(int64(limit))
+	cur, isInResultColl, err := findSwapHistory(clientCtx, filter, cursor, descending, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cur.Close(clientCtx)
+
+	result = make([]*MgoSwapResult, 0, limit)
+	for cur.Next(clientCtx) {
+		res, errd := decodeSwapHistory(cur, isInResultColl)
+		if errd != nil {
+			return nil, "", mgoError(errd)
+		}
+		result = append(result, res)
+	}
+	if err = cur.Err(); err != nil {
+		return nil, "", mgoError(err)
+	}
+	if len(result) == limit {
+		last := result[len(result)-1]
+		nextCursor = encodeSwapHistoryCursor(last.InitTime, last.Key)
+	}
+	return result, nextCursor, nil
+}
+
+// IterateRouterSwapResults iterate all router swap results with filter in ascending order of init time
+func IterateRouterSwapResults(ctx context.Context, filter *SwapHistoryFilter, callback func(*MgoSwapResult) error) error {
+	opts := options.Find().SetBatchSize(500)
+	cur, isInResultColl, err := findSwapHistory(ctx, filter, "", false, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		res, errd := decodeSwapHistory(cur, isInResultColl)
+		if errd != nil {
+			return mgoError(errd)
+		}
+		if err = callback(res); err != nil {
+			return err
+		}
+	}
+	return mgoError(cur.Err())
+}
+
 // UpdateRouterSwapResult update router swap result
 func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapResultUpdateItems) error {
 	key := GetRouterSwapKey(fromChainID, txid, logindex)
@@ -626,6 +890,9 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 	if items.SwapValue != "" {
 		updates["swapvalue"] = items.SwapValue
 	}
+	if items.SwapFee != nil {
+		updates["swapfee"] = items.SwapFee
+	}
 	if items.Memo != "" {
 		updates["memo"] = items.Memo
 	} else if items.Status == MatchTxNotStable {
@@ -646,6 +913,9 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
 	if err == nil {
 		log.Info("mongodb update router swap result success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates)
+		if items.Status == MatchTxNotStable && items.SwapTx != "" {
+			AddSwapNotifyEvent(NotifyEventSigned, fromChainID, txid, logindex, items.Status, items.SwapTx, items.Memo)
+		}
 	} else {
 		log.Error("mongodb update router swap result failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "updates", updates, "err", err)
 	}
@@ -700,6 +970,582 @@ func AddUsedRValue(pubkey, r string) error {
 	}
 }
 
+// ----------------------------- reorg watch functions -------------------------------------
+
+// GetReorgWatchKey get reorg watch key
+func GetReorgWatchKey(fromChainID, txid string, logindex int, isSwapTx bool) string {
+	if isSwapTx {
+		return GetRouterSwapKey(fromChainID, txid, logindex) + ":dst"
+	}
+	return GetRouterSwapKey(fromChainID, txid, logindex) + ":src"
+}
+
+// AddOrUpdateReorgWatch add reorg watch, or replace the old one with the same key
+func AddOrUpdateReorgWatch(mw *MgoReorgWatch) error {
+	mw.Key = GetReorgWatchKey(mw.FromChainID, mw.TxID, mw.LogIndex, mw.IsSwapTx)
+	mw.InitTime = common.NowMilli()
+	mw.Timestamp = time.Now().Unix()
+	opts := options.Replace().SetUpsert(true)
+	_, err := collReorgWatch.ReplaceOne(clientCtx, bson.M{"_id": mw.Key}, mw, opts)
+	if err == nil {
+		log.Info("mongodb add reorg watch success", "key", mw.Key, "chainid", mw.ChainID, "txhash", mw.TxHash, "height", mw.BlockHeight, "blockhash", mw.BlockHash)
+	} else {
+		log.Error("mongodb add reorg watch failed", "key", mw.Key, "chainid", mw.ChainID, "txhash", mw.TxHash, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindReorgWatchesToCheck find not finalized reorg watches of chain
+func FindReorgWatchesToCheck(chainID string) ([]*MgoReorgWatch, error) {
+	qchain := bson.E{Key: "chainID", Value: chainID}
+	qfinalized := bson.E{Key: "finalized", Value: false}
+	queries := bson.D{qchain, qfinalized}
+	limit := maxCountOfResults
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "blockheight", Value: 1}},
+		Limit: &limit,
+	}
+	cur, err := collReorgWatch.Find(clientCtx, queries, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoReorgWatch, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// MarkReorgWatchFinalized mark reorg watch finalized
+func MarkReorgWatchFinalized(key string) error {
+	updates := bson.M{"finalized": true, "timestamp": time.Now().Unix()}
+	_, err := collReorgWatch.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if err != nil {
+		log.Error("mongodb mark reorg watch finalized failed", "key", key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateReorgWatchMissCount update miss count of reorg watch
+func UpdateReorgWatchMissCount(key string, missCount int) error {
+	updates := bson.M{"misscount": missCount, "timestamp": time.Now().Unix()}
+	_, err := collReorgWatch.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// DeleteReorgWatch delete reorg watch
+func DeleteReorgWatch(key string) error {
+	_, err := collReorgWatch.DeleteOne(clientCtx, bson.M{"_id": key})
+	if err != nil {
+		log.Error("mongodb delete reorg watch failed", "key", key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RevertRouterSwapForReorg revert router swap to `TxNotStable` to verify it again.
+// only revert if swap result is not processed yet (no swap nonce and no swap tx),
+// return whether the swap is reverted.
+func RevertRouterSwapForReorg(fromChainID, txid string, logindex int, memo string) (bool, error) {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	filter := bson.M{
+		"_id":       key,
+		"status":    MatchTxEmpty,
+		"swapnonce": 0,
+		"swaptx":    "",
+	}
+	res, err := collRouterSwapResult.DeleteOne(clientCtx, filter)
+	if err != nil {
+		log.Error("mongodb delete swap result for reorg failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
+		return false, mgoError(err)
+	}
+	if res.DeletedCount == 0 {
+		return false, nil
+	}
+	err = UpdateRouterSwapStatus(fromChainID, txid, logindex, TxNotStable, time.Now().Unix(), memo)
+	if err != nil {
+		return false, err
+	}
+	_ = RemoveOutflowUsage(key)
+	return true, nil
+}
+
+// RevertRouterSwapResultForReorg revert router swap result to `MatchTxNotStable`
+// and reset swap height and time to check the swap tx again.
+func RevertRouterSwapResultForReorg(fromChainID, txid string, logindex int, memo string) error {
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	filter := bson.M{
+		"_id":    key,
+		"status": bson.M{"$in": []SwapStatus{MatchTxNotStable, MatchTxStable, MatchTxFailed}},
+	}
+	updates := bson.M{
+		"status":     MatchTxNotStable,
+		"swapheight": 0,
+		"swaptime":   0,
+		"timestamp":  time.Now().Unix(),
+		"memo":       memo,
+	}
+	res, err := collRouterSwapResult.UpdateOne(clientCtx, filter, bson.M{"$set": updates})
+	if err != nil {
+		log.Error("mongodb revert swap result for reorg failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrItemNotFound
+	}
+	log.Info("mongodb revert swap result for reorg success", "chainid", fromChainID, "txid", txid, "logindex", logindex)
+	_ = RemoveOutflowUsage(key)
+	return nil
+}
+
+// ----------------------------- swap fee functions -------------------------------------
+
+// GetSwapFeeTotals get swap fee totals of stable swaps (group by tokenID, from and to chainID)
+// in time range [startTime, endTime) of unix seconds (endTime 0 means now)
+// empty tokenID or chainID means all
+func GetSwapFeeTotals(tokenID, fromChainID, toChainID string, startTime, endTime int64) ([]*SwapFeeTotal, error) {
+	queries := bson.M{
+		"status":  MatchTxStable,
+		"swapfee": bson.M{"$exists": true},
+	}
+	timeQuery := bson.M{"$gte": startTime * 1000}
+	if endTime > 0 {
+		timeQuery["$lt"] = endTime * 1000
+	}
+	queries["inittime"] = timeQuery
+	if tokenID != "" {
+		queries["swapinfo.routerSwapInfo.tokenID"] = tokenID
+	}
+	if fromChainID != "" && fromChainID != allChainIDs {
+		queries["fromChainID"] = fromChainID
+	}
+	if toChainID != "" && toChainID != allChainIDs {
+		queries["toChainID"] = toChainID
+	}
+	opts := options.Find().SetProjection(bson.M{
+		"swapinfo": 1, "fromChainID": 1, "toChainID": 1, "value": 1, "swapfee": 1,
+	})
+	cur, err := collRouterSwapResult.Find(clientCtx, queries, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	defer cur.Close(clientCtx)
+
+	type totalValues struct {
+		*SwapFeeTotal
+		value *big.Int
+		fee   *big.Int
+	}
+	totalsMap := make(map[string]*totalValues)
+	keys := make([]string, 0)
+	for cur.Next(clientCtx) {
+		var res MgoSwapResult
+		if err = cur.Decode(&res); err != nil {
+			return nil, mgoError(err)
+		}
+		if res.SwapFee == nil || res.ERC20SwapInfo == nil {
+			continue
+		}
+		fee, ok := new(big.Int).SetString(res.SwapFee.SwapFee, 10)
+		if !ok {
+			log.Warn("wrong swap fee in swap result", "chainid", res.FromChainID, "txid", res.TxID, "logindex", res.LogIndex, "swapfee", res.SwapFee.SwapFee)
+			continue
+		}
+		value, _ := new(big.Int).SetString(res.Value, 10)
+		key := fmt.Sprintf("%v:%v:%v:%v", res.ERC20SwapInfo.TokenID, res.FromChainID, res.ToChainID, res.SwapFee.FromDecimals)
+		total, exist := totalsMap[key]
+		if !exist {
+			total = &totalValues{
+				SwapFeeTotal: &SwapFeeTotal{
+					TokenID:      res.ERC20SwapInfo.TokenID,
+					FromChainID:  res.FromChainID,
+					ToChainID:    res.ToChainID,
+					FromDecimals: res.SwapFee.FromDecimals,
+				},
+				value: big.NewInt(0),
+				fee:   big.NewInt(0),
+			}
+			totalsMap[key] = total
+			keys = append(keys, key)
+		}
+		total.Count++
+		total.fee.Add(total.fee, fee)
+		if value != nil {
+			total.value.Add(total.value, value)
+		}
+	}
+	if err = cur.Err(); err != nil {
+		return nil, mgoError(err)
+	}
+
+	sort.Strings(keys)
+	result := make([]*SwapFeeTotal, len(keys))
+	for i, key := range keys {
+		total := totalsMap[key]
+		total.TotalValue = total.value.String()
+		total.TotalFee = total.fee.String()
+		result[i] = total.SwapFeeTotal
+	}
+	return result, nil
+}
+
+// ----------------------------- outflow usage functions -------------------------------------
+
+// AddOutflowUsage add outflow usage of swap (ignore if exist)
+func AddOutflowUsage(mu *MgoOutflowUsage) error {
+	_, err := collOutflowUsage.InsertOne(clientCtx, mu)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err == nil {
+		log.Info("mongodb add outflow usage success", "key", mu.Key, "tokenID", mu.TokenID, "toChainID", mu.ToChainID, "value", mu.Value)
+	} else {
+		log.Error("mongodb add outflow usage failed", "key", mu.Key, "tokenID", mu.TokenID, "toChainID", mu.ToChainID, "value", mu.Value, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RemoveOutflowUsage remove outflow usage of swap (ignore if not exist)
+func RemoveOutflowUsage(key string) error {
+	res, err := collOutflowUsage.DeleteOne(clientCtx, bson.M{"_id": key})
+	if err != nil {
+		log.Error("mongodb remove outflow usage failed", "key", key, "err", err)
+		return mgoError(err)
+	}
+	if res.DeletedCount > 0 {
+		log.Info("mongodb remove outflow usage success", "key", key)
+	}
+	return nil
+}
+
+// GetOutflowUsageTotal get total outflow value (in unit of 18 decimals) of tokenID
+// to dest chain since timestamp (unix seconds), excluding the usage of excludeKey
+func GetOutflowUsageTotal(tokenID, toChainID string, since int64, excludeKey string) (*big.Int, error) {
+	query := bson.M{
+		"_id":       bson.M{"$ne": excludeKey},
+		"tokenID":   tokenID,
+		"toChainID": toChainID,
+		"timestamp": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetProjection(bson.M{"value": 1})
+	cur, err := collOutflowUsage.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	defer cur.Close(clientCtx)
+
+	total := big.NewInt(0)
+	for cur.Next(clientCtx) {
+		var mu MgoOutflowUsage
+		if err = cur.Decode(&mu); err != nil {
+			return nil, mgoError(err)
+		}
+		value, ok := new(big.Int).SetString(mu.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("wrong outflow usage value '%v' of %v", mu.Value, mu.Key)
+		}
+		total.Add(total, value)
+	}
+	return total, mgoError(cur.Err())
+}
+
+// ----------------------------- circuit breaker functions -------------------------------------
+
+// AddCircuitBreakerTrip add circuit breaker trip of chain (replace if exist)
+func AddCircuitBreakerTrip(chainID, reason string) error {
+	trip := &MgoCircuitBreakerTrip{
+		Key:       chainID,
+		Reason:    reason,
+		Timestamp: common.Now(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := collCircuitBreaker.ReplaceOne(clientCtx, bson.M{"_id": chainID}, trip, opts)
+	if err == nil {
+		log.Info("mongodb add circuit breaker trip success", "chainID", chainID, "reason", reason)
+	} else {
+		log.Error("mongodb add circuit breaker trip failed", "chainID", chainID, "reason", reason, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindCircuitBreakerTrips find all circuit breaker trips
+func FindCircuitBreakerTrips() ([]*MgoCircuitBreakerTrip, error) {
+	cur, err := collCircuitBreaker.Find(clientCtx, bson.M{})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoCircuitBreakerTrip, 0, 5)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// DeleteCircuitBreakerTrips delete circuit breaker trips of chains
+func DeleteCircuitBreakerTrips(chainIDs []string) error {
+	_, err := collCircuitBreaker.DeleteMany(clientCtx, bson.M{"_id": bson.M{"$in": chainIDs}})
+	if err == nil {
+		log.Info("mongodb delete circuit breaker trips success", "chainIDs", chainIDs)
+	} else {
+		log.Error("mongodb delete circuit breaker trips failed", "chainIDs", chainIDs, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ----------------------------- scan checkpoint functions -------------------------------------
+
+// FindScanCheckpoint find swap scanner checkpoint of chain
+func FindScanCheckpoint(chainID string) (*MgoScanCheckpoint, error) {
+	var result MgoScanCheckpoint
+	err := collScanCheckpoint.FindOne(clientCtx, bson.M{"_id": chainID}).Decode(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// UpdateScanCheckpoint update swap scanner checkpoint of chain
+func UpdateScanCheckpoint(chainID string, blockHeight uint64) error {
+	updates := bson.M{"blockheight": blockHeight, "timestamp": time.Now().Unix()}
+	opts := options.Update().SetUpsert(true)
+	_, err := collScanCheckpoint.UpdateByID(clientCtx, chainID, bson.M{"$set": updates}, opts)
+	if err != nil {
+		log.Error("mongodb update scan checkpoint failed", "chainid", chainID, "height", blockHeight, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ----------------------------- webhook functions -------------------------------------
+
+// AddSwapStatusListener add in-process listener of swap lifecycle events (eg. websocket subscriptions)
+func AddSwapStatusListener(listener func(*SwapNotifyEvent)) {
+	swapStatusListenersLock.Lock()
+	defer swapStatusListenersLock.Unlock()
+	swapStatusListeners = append(swapStatusListeners, listener)
+}
+
+// AddSwapNotifyEvent notify swap lifecycle event to listeners and queue webhook deliveries
+func AddSwapNotifyEvent(event, fromChainID, txid string, logindex int, status SwapStatus, swapTx, memo string) {
+	if event == "" {
+		return
+	}
+	notifyEvent := &SwapNotifyEvent{
+		Event:       event,
+		FromChainID: fromChainID,
+		TxID:        txid,
+		LogIndex:    logindex,
+		Status:      status,
+		StatusMsg:   status.String(),
+		SwapTx:      swapTx,
+		Memo:        memo,
+		Timestamp:   time.Now().Unix(),
+	}
+
+	swapStatusListenersLock.RLock()
+	for _, listener := range swapStatusListeners {
+		listener(notifyEvent)
+	}
+	swapStatusListenersLock.RUnlock()
+
+	addWebhookDeliveries(notifyEvent)
+}
+
+func addWebhookDeliveries(notifyEvent *SwapNotifyEvent) {
+	cfg := params.GetWebhookConfig()
+	if cfg == nil {
+		return
+	}
+	event := notifyEvent.Event
+	fromChainID, txid, logindex := notifyEvent.FromChainID, notifyEvent.TxID, notifyEvent.LogIndex
+	payload, err := json.Marshal(notifyEvent)
+	if err != nil {
+		log.Warn("mongodb marshal swap notify event failed", "event", event, "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
+		return
+	}
+	var urls []string
+	if cfg.IsEventSubscribed(event) {
+		urls = append(urls, cfg.URLs...)
+	}
+	if IsAlertNotifyEvent(event) {
+		urls = append(urls, cfg.AlertURLs...)
+	}
+	swapKey := GetRouterSwapKey(fromChainID, txid, logindex)
+	for _, url := range urls {
+		delivery := &MgoWebhookDelivery{
+			Key:       strings.Join([]string{swapKey, event, notifyEvent.SwapTx, url}, ":"),
+			URL:       url,
+			Event:     event,
+			Payload:   string(payload),
+			Status:    WebhookPending,
+			NextTime:  notifyEvent.Timestamp,
+			Timestamp: notifyEvent.Timestamp,
+		}
+		_, err = collWebhookDelivery.InsertOne(clientCtx, delivery)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			log.Warn("mongodb add webhook delivery failed", "event", event, "chainid", fromChainID, "txid", txid, "logindex", logindex, "url", url, "err", err)
+		}
+	}
+}
+
+// FindWebhookDeliveriesToSend find pending webhook deliveries which reach next send time
+func FindWebhookDeliveriesToSend(limit int64) ([]*MgoWebhookDelivery, error) {
+	query := bson.M{
+		"status":   WebhookPending,
+		"nexttime": bson.M{"$lte": time.Now().Unix()},
+	}
+	opts := &options.FindOptions{
+		Sort:  bson.D{{Key: "timestamp", Value: 1}},
+		Limit: &limit,
+	}
+	cur, err := collWebhookDelivery.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoWebhookDelivery, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// UpdateWebhookDelivery update webhook delivery after sending
+func UpdateWebhookDelivery(key, status string, attempts int, nextTime int64, lastError string) error {
+	updates := bson.M{
+		"status":    status,
+		"attempts":  attempts,
+		"nexttime":  nextTime,
+		"lasterror": lastError,
+	}
+	_, err := collWebhookDelivery.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if err != nil {
+		log.Error("mongodb update webhook delivery failed", "key", key, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ----------------------------- admin audit functions -------------------------------------
+
+// AddAdminAudit add audit log of admin call
+func AddAdminAudit(caller, method string, params []string, result string, callErr error) error {
+	timestamp := time.Now()
+	ma := &MgoAdminAudit{
+		Key:       fmt.Sprintf("%v:%v:%v", strings.ToLower(caller), method, timestamp.UnixNano()),
+		Caller:    caller,
+		Method:    method,
+		Params:    params,
+		Result:    result,
+		Timestamp: timestamp.Unix(),
+	}
+	if callErr != nil {
+		ma.Error = callErr.Error()
+	}
+	_, err := collAdminAudit.InsertOne(clientCtx, ma)
+	if err == nil {
+		log.Info("mongodb add admin audit success", "caller", caller, "method", method, "params", params, "result", result, "err", callErr)
+	} else {
+		log.Error("mongodb add admin audit failed", "caller", caller, "method", method, "params", params, "result", result, "callErr", callErr, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ----------------------------- sign request functions -------------------------------------
+
+// AddSignRequest add in-flight mpc sign request
+func AddSignRequest(req *MgoSignRequest) error {
+	req.Status = SignRequestPending
+	req.Timestamp = time.Now().Unix()
+	req.UpdateTime = req.Timestamp
+	_, err := collSignRequest.InsertOne(clientCtx, req)
+	if err == nil {
+		log.Info("mongodb add sign request success", "keyID", req.Key, "chainid", req.FromChainID, "txid", req.TxID, "logindex", req.LogIndex, "nonce", req.SwapNonce)
+	} else {
+		log.Error("mongodb add sign request failed", "keyID", req.Key, "chainid", req.FromChainID, "txid", req.TxID, "logindex", req.LogIndex, "nonce", req.SwapNonce, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateSignRequestStatus update sign request status (and rsvs if signed)
+func UpdateSignRequestStatus(keyID, status string, rsvs []string, memo string) error {
+	updates := bson.M{
+		"status":     status,
+		"updatetime": time.Now().Unix(),
+	}
+	if len(rsvs) > 0 {
+		updates["rsvs"] = rsvs
+	}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	_, err := collSignRequest.UpdateByID(clientCtx, keyID, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update sign request status success", "keyID", keyID, "status", status, "memo", memo)
+	} else {
+		log.Error("mongodb update sign request status failed", "keyID", keyID, "status", status, "memo", memo, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateSignRequestsProcessed mark signed requests of swap as processed
+func UpdateSignRequestsProcessed(fromChainID, txid string, logindex int) error {
+	query := bson.M{
+		"txid":        txid,
+		"fromChainID": fromChainID,
+		"logIndex":    logindex,
+		"status":      SignRequestSigned,
+	}
+	updates := bson.M{
+		"status":     SignRequestProcessed,
+		"updatetime": time.Now().Unix(),
+	}
+	_, err := collSignRequest.UpdateMany(clientCtx, query, bson.M{"$set": updates})
+	if err != nil {
+		log.Error("mongodb update sign requests processed failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindUnfinishedSignRequests find pending or signed requests created before septime
+func FindUnfinishedSignRequests(septime int64) ([]*MgoSignRequest, error) {
+	query := bson.M{
+		"status":    bson.M{"$in": []string{SignRequestPending, SignRequestSigned}},
+		"timestamp": bson.M{"$lte": septime},
+	}
+	opts := &options.FindOptions{
+		Sort: bson.D{{Key: "timestamp", Value: 1}},
+	}
+	cur, err := collSignRequest.Find(clientCtx, query, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSignRequest, 0, 20)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// HasUnfinishedSignRequest whether swap has pending, signed or manual requests
+func HasUnfinishedSignRequest(fromChainID, txid string, logindex int) (bool, error) {
+	query := bson.M{
+		"txid":        txid,
+		"fromChainID": fromChainID,
+		"logIndex":    logindex,
+		"status":      bson.M{"$in": []string{SignRequestPending, SignRequestSigned, SignRequestManual}},
+	}
+	limit := int64(1)
+	count, err := collSignRequest.CountDocuments(clientCtx, query, &options.CountOptions{Limit: &limit})
+	if err != nil {
+		return false, mgoError(err)
+	}
+	return count > 0, nil
+}
+
 // ----------------------------- admin functions -------------------------------------
 
 // RouterAdminPassBigValue pass big value
@@ -708,8 +1554,8 @@ func RouterAdminPassBigValue(fromChainID, txid string, logIndex int) error {
 	if err != nil {
 		return err
 	}
-	if swap.Status != TxWithBigValue {
-		return fmt.Errorf("swap status is %v, not big value status %v", swap.Status.String(), TxWithBigValue.String())
+	if swap.Status != TxWithBigValue && swap.Status != TxHeldByOutflowQuota {
+		return fmt.Errorf("swap status is %v, not big value status %v or %v", swap.Status.String(), TxWithBigValue.String(), TxHeldByOutflowQuota.String())
 	}
 
 	_, err = FindRouterSwapResult(fromChainID, txid, logIndex)
@@ -775,6 +1621,134 @@ func RouterAdminReswap(fromChainID, txid string, logIndex int) error {
 	return UpdateRouterSwapStatus(fromChainID, txid, logIndex, TxNotSwapped, time.Now().Unix(), "")
 }
 
+// RouterAdminReverify reset verify failed swap to TxNotStable to verify it again
+func RouterAdminReverify(fromChainID, txid string, logIndex int, swapInfo *SwapInfo) error {
+	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	switch {
+	case swap.Status == ManualMakeFail:
+		return errors.New("swap is manual make fail, can not reverify")
+	case swap.Status == TxWithBigValue, swap.Status == TxHeldByOutflowQuota:
+		return fmt.Errorf("swap status is %v, please use passbigvalue instead", swap.Status.String())
+	case !swap.Status.IsVerifyFailed():
+		return fmt.Errorf("swap status is %v, can not reverify", swap.Status.String())
+	}
+	_, err = FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err == nil {
+		return errors.New("swap result exist, can not reverify")
+	}
+	if !errors.Is(err, ErrItemNotFound) {
+		return err
+	}
+	return UpdateRouterSwapInfoAndStatus(fromChainID, txid, logIndex, swapInfo, TxNotStable, time.Now().Unix(), "")
+}
+
+// RouterAdminMakeFail manually make swap fail if it has not been sent
+func RouterAdminMakeFail(fromChainID, txid string, logIndex int, reason string) error {
+	swap, err := FindRouterSwap(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if swap.Status == ManualMakeFail {
+		return errors.New("swap is already manual make fail")
+	}
+
+	res, err := FindRouterSwapResult(fromChainID, txid, logIndex)
+	hasResult := err == nil
+	if hasResult {
+		if res.SwapTx != "" || res.SwapNonce != 0 {
+			return fmt.Errorf("can not make fail swap with swaptx '%v' and swapnonce %v", res.SwapTx, res.SwapNonce)
+		}
+		if res.Status != MatchTxEmpty && res.Status != Reswapping {
+			return fmt.Errorf("swap result status is %v, can not make fail", res.Status.String())
+		}
+	} else if swap.Status == TxProcessed {
+		return fmt.Errorf("swap status is %v, but swap result not found", swap.Status.String())
+	}
+
+	log.Info("[makefail] update status to ManualMakeFail", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "oldStatus", swap.Status, "reason", reason)
+
+	timestamp := time.Now().Unix()
+	if hasResult {
+		err = UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, ManualMakeFail, timestamp, reason)
+		if err != nil {
+			return err
+		}
+	}
+	err = UpdateRouterSwapStatus(fromChainID, txid, logIndex, ManualMakeFail, timestamp, reason)
+	if err != nil {
+		return err
+	}
+	return RemoveOutflowUsage(GetRouterSwapKey(fromChainID, txid, logIndex))
+}
+
+// RouterAdminReleaseSign release sign requests which need manual check,
+// so that the swap can be signed again
+func RouterAdminReleaseSign(fromChainID, txid string, logIndex int) error {
+	query := bson.M{
+		"txid":        txid,
+		"fromChainID": fromChainID,
+		"logIndex":    logIndex,
+		"status":      SignRequestManual,
+	}
+	updates := bson.M{
+		"status":     SignRequestFailed,
+		"memo":       "released by admin",
+		"updatetime": time.Now().Unix(),
+	}
+	res, err := collSignRequest.UpdateMany(clientCtx, query, bson.M{"$set": updates})
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.ModifiedCount == 0 {
+		return fmt.Errorf("swap has no sign request need manual check")
+	}
+	log.Info("[releasesign] release sign requests", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "count", res.ModifiedCount)
+	return nil
+}
+
+// RouterAdminForceStable force swap result to be stable after off-chain confirmation.
+// if `swapTx` is not empty, it must be the swaptx or one of the old swaptxs.
+func RouterAdminForceStable(fromChainID, txid string, logIndex int, swapTx string) error {
+	res, err := FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if res.Status != MatchTxNotStable && res.Status != MatchTxFailed {
+		return fmt.Errorf("swap result status is %v, can not force stable", res.Status.String())
+	}
+	if swapTx == "" {
+		swapTx = res.SwapTx
+	}
+	if swapTx == "" {
+		return errors.New("swap without swaptx")
+	}
+	isKnownSwapTx := strings.EqualFold(swapTx, res.SwapTx)
+	for _, oldSwapTx := range res.OldSwapTxs {
+		if isKnownSwapTx {
+			break
+		}
+		isKnownSwapTx = strings.EqualFold(swapTx, oldSwapTx)
+	}
+	if !isKnownSwapTx {
+		return fmt.Errorf("swaptx %v is not sent for this swap", swapTx)
+	}
+
+	log.Info("[forcestable] update status to MatchTxStable", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "oldStatus", res.Status, "swaptx", swapTx)
+
+	key := GetRouterSwapKey(fromChainID, txid, logIndex)
+	if !strings.EqualFold(swapTx, res.SwapTx) {
+		_, err = collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": bson.M{"swaptx": swapTx}})
+		if err != nil {
+			log.Error("[forcestable] update swaptx failed", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "swaptx", swapTx, "err", err)
+			return mgoError(err)
+		}
+	}
+	return UpdateRouterSwapResultStatus(fromChainID, txid, logIndex, MatchTxStable, time.Now().Unix(), "force stable by admin")
+}
+
 func getSwapResultsTxStatus(bridge tokens.IBridge, res *MgoSwapResult) (status *tokens.TxStatus, txHash string) {
 	var err error
 	if status, err = bridge.GetTransactionStatus(res.SwapTx); err == nil {
@@ -856,6 +1830,49 @@ func getStatusInfo(coll *mongo.Collection, filterStatuses []SwapStatus) (result
 	return result, nil
 }
 
+// SwapStatusCount swap count of from chain and status
+type SwapStatusCount struct {
+	FromChainID string     `bson:"fromChainID"`
+	Status      SwapStatus `bson:"status"`
+	Count       int        `bson:"count"`
+}
+
+// GetSwapStatusCounts get swap counts group by from chain and status since init time (in milli seconds)
+func GetSwapStatusCounts(isResult bool, since int64) ([]*SwapStatusCount, error) {
+	coll := collRouterSwap
+	if isResult {
+		coll = collRouterSwapResult
+	}
+	pipeOption := []bson.M{
+		{"$match": bson.M{"inittime": bson.M{"$gte": since}}},
+		{"$group": bson.M{
+			"_id":   bson.M{"fromChainID": "$fromChainID", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":         0,
+			"fromChainID": "$_id.fromChainID",
+			"status":      "$_id.status",
+			"count":       1,
+		}},
+	}
+
+	ctx, cancel := context.WithDeadline(clientCtx, time.Now().Add(10*time.Second))
+	defer cancel()
+
+	cur, err := coll.Aggregate(ctx, pipeOption)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+
+	result := make([]*SwapStatusCount, 0, 20)
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
 // ----------------------------- helper functions -------------------------------------
 
 // GetRegisteredRouterSwap get registered router swap
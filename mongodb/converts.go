@@ -192,3 +192,32 @@ func toBigIntSlice(slice []string) ([]*big.Int, error) {
 	}
 	return result, nil
 }
+
+func bigIntToString(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+// ConvertToSwapFee convert
+func ConvertToSwapFee(info *tokens.SwapFeeInfo) *SwapFee {
+	if info == nil || info.SwapFee == nil {
+		return nil
+	}
+	return &SwapFee{
+		Policy:            info.Policy,
+		SwapFee:           info.SwapFee.String(),
+		FeeRatePerMillion: info.FeeRatePerMillion,
+		MinimumSwapFee:    bigIntToString(info.MinimumSwapFee),
+		MaximumSwapFee:    bigIntToString(info.MaximumSwapFee),
+		BaseFeePercent:    info.BaseFeePercent,
+		AdjustBaseFee:     bigIntToString(info.AdjustBaseFee),
+		GasFee:            bigIntToString(info.GasFee),
+		GasPrice:          bigIntToString(info.GasPrice),
+		InWhitelist:       info.InWhitelist,
+		InPromoWindow:     info.InPromoWindow,
+		FromDecimals:      info.FromDecimals,
+		ToDecimals:        info.ToDecimals,
+	}
+}
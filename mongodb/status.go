@@ -18,14 +18,23 @@ import (
 //                |- TxWithWrongValue  -> manual
 //                |- SwapInBlacklist   -> manual
 //                |- TxWithBigValue    ---> TxNotSwapped
+//                |- TxHeldByOutflowQuota ---> TxNotSwapped
 //                |- TxNotSwapped -> |- TxProcessed (->MatchTxNotStable)
+//                                   |- TxQueuedForLiquidity -> TxNotSwapped
 // -----------------------------------------------
 // 2. swap result status change graph
 //
 // TxWithBigValue ---> MatchTxEmpty
+// TxHeldByOutflowQuota ---> MatchTxEmpty
 // MatchTxEmpty   -> | MatchTxNotStable -> |- MatchTxStable
 //                                         |- MatchTxFailed -> manual
 // -----------------------------------------------
+// 3. admin manual status change graph
+//
+// TxVerifyFailed (and other verify errors) without swap result ---> TxNotStable (reverify)
+// before swaptx is sent ---> ManualMakeFail (makefail)
+// MatchTxNotStable, MatchTxFailed ---> MatchTxStable (forcestable)
+// -----------------------------------------------
 
 // SwapStatus swap status
 type SwapStatus uint16
@@ -48,10 +57,46 @@ const (
 	MissTokenConfig   SwapStatus = 20
 	NoUnderlyingToken SwapStatus = 21
 
+	TxQueuedForLiquidity SwapStatus = 22
+	TxHeldByOutflowQuota SwapStatus = 23
+
 	KeepStatus SwapStatus = 255
 	Reswapping SwapStatus = 256
 )
 
+// swap lifecycle events notified by webhook
+const (
+	NotifyEventRegistered = "registered"
+	NotifyEventVerified   = "verified"
+	NotifyEventHeld       = "held"
+	NotifyEventSigned     = "signed"
+	NotifyEventSent       = "sent"
+	NotifyEventStable     = "stable"
+	NotifyEventFailed     = "failed"
+)
+
+// IsAlertNotifyEvent is event routed to alert channel
+func IsAlertNotifyEvent(event string) bool {
+	return event == NotifyEventFailed || event == NotifyEventHeld
+}
+
+// GetNotifyEvent get lifecycle event of status transition (empty if not notified)
+func (status SwapStatus) GetNotifyEvent() string {
+	switch status {
+	case TxNotSwapped:
+		return NotifyEventVerified
+	case TxWithBigValue, TxHeldByOutflowQuota:
+		return NotifyEventHeld
+	case MatchTxStable:
+		return NotifyEventStable
+	case TxVerifyFailed, TxWithWrongValue, TxWithWrongPath, SwapInBlacklist,
+		MissTokenConfig, NoUnderlyingToken, MatchTxFailed, ManualMakeFail:
+		return NotifyEventFailed
+	default:
+		return ""
+	}
+}
+
 // IsResultStatus is swap result status
 func (status SwapStatus) IsResultStatus() bool {
 	switch status {
@@ -65,7 +110,17 @@ func (status SwapStatus) IsResultStatus() bool {
 // IsRegisteredOk is successfully registered
 func (status SwapStatus) IsRegisteredOk() bool {
 	switch status {
-	case TxNotStable, TxNotSwapped, TxProcessed, ManualMakeFail:
+	case TxNotStable, TxNotSwapped, TxProcessed, ManualMakeFail, TxQueuedForLiquidity:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsVerifyFailed is failed to verify and can be reverified
+func (status SwapStatus) IsVerifyFailed() bool {
+	switch status {
+	case TxVerifyFailed, TxWithWrongValue, TxWithWrongPath, SwapInBlacklist, MissTokenConfig, NoUnderlyingToken:
 		return true
 	default:
 		return false
@@ -105,6 +160,10 @@ func (status SwapStatus) String() string {
 		return "MissTokenConfig"
 	case NoUnderlyingToken:
 		return "NoUnderlyingToken"
+	case TxQueuedForLiquidity:
+		return "TxQueuedForLiquidity"
+	case TxHeldByOutflowQuota:
+		return "TxHeldByOutflowQuota"
 
 	case KeepStatus:
 		return "KeepStatus"
@@ -11,12 +11,26 @@ const (
 	tbRouterSwaps       string = "RouterSwaps"
 	tbRouterSwapResults string = "RouterSwapResults"
 	tbUsedRValues       string = "UsedRValues"
+	tbReorgWatches      string = "ReorgWatches"
+	tbScanCheckpoints   string = "ScanCheckpoints"
+	tbOutflowUsages     string = "OutflowUsages"
+	tbCircuitBreakers   string = "CircuitBreakerTrips"
+	tbWebhookDeliveries string = "WebhookDeliveries"
+	tbAdminAudits       string = "AdminAudits"
+	tbSignRequests      string = "SignRequests"
 )
 
 var (
 	collRouterSwap       *mongo.Collection
 	collRouterSwapResult *mongo.Collection
 	collUsedRValue       *mongo.Collection
+	collReorgWatch       *mongo.Collection
+	collScanCheckpoint   *mongo.Collection
+	collOutflowUsage     *mongo.Collection
+	collCircuitBreaker   *mongo.Collection
+	collWebhookDelivery  *mongo.Collection
+	collAdminAudit       *mongo.Collection
+	collSignRequest      *mongo.Collection
 )
 
 func initCollections() {
@@ -25,13 +39,35 @@ func initCollections() {
 	collRouterSwap = database.Collection(tbRouterSwaps)
 	collRouterSwapResult = database.Collection(tbRouterSwapResults)
 	collUsedRValue = database.Collection(tbUsedRValues)
+	collReorgWatch = database.Collection(tbReorgWatches)
+	collScanCheckpoint = database.Collection(tbScanCheckpoints)
+	collOutflowUsage = database.Collection(tbOutflowUsages)
+	collCircuitBreaker = database.Collection(tbCircuitBreakers)
+	collWebhookDelivery = database.Collection(tbWebhookDeliveries)
+	collAdminAudit = database.Collection(tbAdminAudits)
+	collSignRequest = database.Collection(tbSignRequests)
 
 	createOneIndex(collRouterSwap, "inittime", "status", "fromChainID")
 	createOneIndex(collRouterSwap, "txid")
+	createOneIndex(collRouterSwap, "inittime", "_id") // sort of swap history
 
 	createOneIndex(collRouterSwapResult, "inittime", "status", "fromChainID")
 	createOneIndex(collRouterSwapResult, "txid")
+	createOneIndex(collRouterSwapResult, "inittime", "_id") // sort of swap history
 	createOneIndex(collRouterSwapResult, "from", "fromChainID")
+	createOneIndex(collRouterSwapResult, "status", "inittime") // swap fee totals
+
+	createOneIndex(collReorgWatch, "chainID", "finalized", "blockheight")
+
+	createOneIndex(collOutflowUsage, "tokenID", "toChainID", "timestamp")
+
+	createOneIndex(collWebhookDelivery, "status", "nexttime")
+
+	createOneIndex(collAdminAudit, "timestamp")
+	createOneIndex(collAdminAudit, "caller", "timestamp")
+
+	createOneIndex(collSignRequest, "status", "timestamp")
+	createOneIndex(collSignRequest, "txid", "fromChainID", "logIndex")
 
 	log.Info("[mongodb] create indexes finished")
 }
@@ -17,6 +17,7 @@ type MgoSwap struct {
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo"`
+	PayAnyToken bool       `bson:"payanytoken,omitempty"`
 }
 
 // ToSwapResult converts
@@ -67,6 +68,49 @@ type MgoSwapResult struct {
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo"`
 	MPC         string     `bson:"mpc"`
+	SwapFee     *SwapFee   `bson:"swapfee,omitempty" json:"swapfee,omitempty"`
+}
+
+// SwapFee charged swap fee and the fee policy inputs
+type SwapFee struct {
+	Policy            string `bson:"policy"                      json:"policy"`
+	SwapFee           string `bson:"swapFee"                     json:"swapFee"` // in unit of from token
+	FeeRatePerMillion uint64 `bson:"feeRatePerMillion,omitempty" json:"feeRatePerMillion,omitempty"`
+	MinimumSwapFee    string `bson:"minimumSwapFee,omitempty"    json:"minimumSwapFee,omitempty"`
+	MaximumSwapFee    string `bson:"maximumSwapFee,omitempty"    json:"maximumSwapFee,omitempty"`
+	BaseFeePercent    int64  `bson:"baseFeePercent,omitempty"    json:"baseFeePercent,omitempty"`
+	AdjustBaseFee     string `bson:"adjustBaseFee,omitempty"     json:"adjustBaseFee,omitempty"`
+	GasFee            string `bson:"gasFee,omitempty"            json:"gasFee,omitempty"`
+	GasPrice          string `bson:"gasPrice,omitempty"          json:"gasPrice,omitempty"`
+	InWhitelist       bool   `bson:"inWhitelist,omitempty"       json:"inWhitelist,omitempty"`
+	InPromoWindow     bool   `bson:"inPromoWindow,omitempty"     json:"inPromoWindow,omitempty"`
+	FromDecimals      uint8  `bson:"fromDecimals"                json:"fromDecimals"`
+	ToDecimals        uint8  `bson:"toDecimals"                  json:"toDecimals"`
+}
+
+// SwapFeeTotal total swap fees of token from chain to chain
+type SwapFeeTotal struct {
+	TokenID      string `json:"tokenID"`
+	FromChainID  string `json:"fromChainID"`
+	ToChainID    string `json:"toChainID"`
+	FromDecimals uint8  `json:"fromDecimals"`
+	Count        int    `json:"count"`
+	TotalValue   string `json:"totalValue"`
+	TotalFee     string `json:"totalFee"`
+}
+
+// SwapHistoryFilter filter of querying swap history (empty field means no filter)
+type SwapHistoryFilter struct {
+	FromChainID string
+	ToChainID   string
+	TokenID     string
+	Address     string // swap sender
+	SwapType    *uint32
+	Status      string // comma separated statuses
+	StartTime   int64  // unix seconds of init time
+	EndTime     int64  // unix seconds of init time
+	MinValue    string // swap value in unit of source token decimals
+	MaxValue    string // swap value in unit of source token decimals
 }
 
 // MgoUsedRValue security enhancement
@@ -75,6 +119,120 @@ type MgoUsedRValue struct {
 	Timestamp int64  `bson:"timestamp"`
 }
 
+// MgoReorgWatch block hash record of swap tx for watching chain reorg
+type MgoReorgWatch struct {
+	Key         string `bson:"_id"` // swap key + ":src" or ":dst"
+	ChainID     string `bson:"chainID"`
+	TxHash      string `bson:"txhash"`
+	BlockHeight uint64 `bson:"blockheight"`
+	BlockHash   string `bson:"blockhash"`
+	IsSwapTx    bool   `bson:"isswaptx"` // false for source tx, true for destination swap tx
+	FromChainID string `bson:"fromChainID"`
+	TxID        string `bson:"txid"`
+	LogIndex    int    `bson:"logIndex"`
+	MissCount   int    `bson:"misscount"`
+	Finalized   bool   `bson:"finalized"`
+	InitTime    int64  `bson:"inittime"`
+	Timestamp   int64  `bson:"timestamp"`
+}
+
+// MgoScanCheckpoint swap scanner checkpoint
+type MgoScanCheckpoint struct {
+	Key         string `bson:"_id"`         // chainID
+	BlockHeight uint64 `bson:"blockheight"` // next block height to scan
+	Timestamp   int64  `bson:"timestamp"`
+}
+
+// MgoOutflowUsage outflow usage of swap (counted in rolling window quotas)
+type MgoOutflowUsage struct {
+	Key       string `bson:"_id"` // fromChainID + txid + logindex
+	TokenID   string `bson:"tokenID"`
+	ToChainID string `bson:"toChainID"`
+	Value     string `bson:"value"` // in unit of 18 decimals
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// MgoCircuitBreakerTrip chain paused by circuit breaker
+type MgoCircuitBreakerTrip struct {
+	Key       string `bson:"_id"` // chainID
+	Reason    string `bson:"reason"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// webhook delivery status
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+// MgoWebhookDelivery persisted webhook delivery of swap lifecycle event
+type MgoWebhookDelivery struct {
+	Key       string `bson:"_id"` // swapkey:event:swaptx:url
+	URL       string `bson:"url"`
+	Event     string `bson:"event"`
+	Payload   string `bson:"payload"`
+	Status    string `bson:"status"`
+	Attempts  int    `bson:"attempts"`
+	NextTime  int64  `bson:"nexttime"`
+	LastError string `bson:"lasterror"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// SwapNotifyEvent swap lifecycle event (also used as webhook payload)
+type SwapNotifyEvent struct {
+	Event       string     `json:"event"`
+	FromChainID string     `json:"fromChainID"`
+	TxID        string     `json:"txid"`
+	LogIndex    int        `json:"logIndex"`
+	Status      SwapStatus `json:"status"`
+	StatusMsg   string     `json:"statusmsg"`
+	SwapTx      string     `json:"swaptx,omitempty"`
+	Memo        string     `json:"memo,omitempty"`
+	Timestamp   int64      `json:"timestamp"`
+}
+
+// MgoAdminAudit audit log of admin call
+type MgoAdminAudit struct {
+	Key       string   `bson:"_id"` // caller:method:nanotime
+	Caller    string   `bson:"caller"`
+	Method    string   `bson:"method"`
+	Params    []string `bson:"params"`
+	Result    string   `bson:"result"`
+	Error     string   `bson:"error,omitempty"`
+	Timestamp int64    `bson:"timestamp"`
+}
+
+// mpc sign request status
+const (
+	SignRequestPending   = "pending"   // waiting for mpc sign result
+	SignRequestSigned    = "signed"    // got rsv, waiting to apply signed tx to swap
+	SignRequestProcessed = "processed" // signed tx is applied to swap
+	SignRequestFailed    = "failed"
+	SignRequestManual    = "manual" // signed but can not be resumed, block the swap until released by admin
+)
+
+// MgoSignRequest in-flight mpc sign request of swap
+type MgoSignRequest struct {
+	Key         string   `bson:"_id"` // keyID
+	MsgHash     []string `bson:"msghash"`
+	MsgContext  []string `bson:"msgcontext"`
+	SignType    string   `bson:"signtype"`
+	SignPubkey  string   `bson:"signpubkey"`
+	SignGroup   string   `bson:"signgroup"`
+	RPCAddress  string   `bson:"rpcaddress,omitempty"` // initiator mpc node
+	FromChainID string   `bson:"fromChainID"`
+	TxID        string   `bson:"txid"`
+	LogIndex    int      `bson:"logIndex"`
+	ToChainID   string   `bson:"toChainID"`
+	SwapNonce   uint64   `bson:"swapnonce"`
+	Status      string   `bson:"status"`
+	Rsvs        []string `bson:"rsvs,omitempty"`
+	Memo        string   `bson:"memo,omitempty"`
+	Timestamp   int64    `bson:"timestamp"`
+	UpdateTime  int64    `bson:"updatetime"`
+}
+
 // SwapResultUpdateItems swap update items
 type SwapResultUpdateItems struct {
 	MPC        string
@@ -83,6 +241,7 @@ type SwapResultUpdateItems struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapFee    *SwapFee
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
@@ -70,6 +70,7 @@ func GetSignNonce(mpcUser, rpcAddr string) (uint64, error) {
 }
 
 // GetSignStatus call getSignStatus
+// the sign status is also returned with the final failure or timeout error
 func GetSignStatus(key, rpcAddr string) (*SignStatus, error) {
 	var result DataResultResp
 	err := httpPostTo(&result, rpcAddr, "getSignStatus", key)
@@ -89,12 +90,12 @@ func GetSignStatus(key, rpcAddr string) (*SignStatus, error) {
 	case "Failure":
 		log.Info("getSignStatus Failure", "keyID", key, "status", data)
 		if signStatus.HasDisagree() {
-			return nil, ErrGetSignStatusHasDisagree
+			return &signStatus, ErrGetSignStatusHasDisagree
 		}
-		return nil, ErrGetSignStatusFailed
+		return &signStatus, ErrGetSignStatusFailed
 	case "Timeout":
 		log.Info("getSignStatus Timeout", "keyID", key, "status", data)
-		return nil, ErrGetSignStatusTimeout
+		return &signStatus, ErrGetSignStatusTimeout
 	case successStatus:
 		return &signStatus, nil
 	default:
@@ -0,0 +1,273 @@
+package mpc
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+const (
+	signGroupHealthWindow = 100              // count of recent sign results used in scoring
+	signLatencyBaseline   = 10 * time.Second // latency which halves the score
+	minSignGroupScore     = 0.01             // every usable group has chance to be picked
+	scoreWeightPrecision  = 1e6
+)
+
+var (
+	signGroupHealths     = make(map[string]*signGroupHealth) // key is groupID
+	signGroupHealthsLock sync.Mutex
+)
+
+type signResult struct {
+	success   bool
+	latency   time.Duration
+	disagrees int
+}
+
+type signGroupHealth struct {
+	results             []signResult // ring buffer of recent sign results
+	next                int
+	totalSigns          uint64
+	totalFailures       uint64
+	totalDisagrees      uint64
+	consecutiveFailures int
+	degraded            bool
+	degradedTime        int64
+	lastProbeTime       int64
+	lastProbeError      string
+}
+
+// SignGroupHealth health info of sign group
+type SignGroupHealth struct {
+	Initiator           string
+	GroupID             string
+	Score               float64
+	SuccessRate         float64
+	MedianLatency       string
+	Disagrees           int
+	Samples             int
+	TotalSigns          uint64
+	TotalFailures       uint64
+	TotalDisagrees      uint64
+	ConsecutiveFailures int
+	Degraded            bool
+	DegradedTime        int64  `json:",omitempty"`
+	LastProbeTime       int64  `json:",omitempty"`
+	LastProbeError      string `json:",omitempty"`
+}
+
+func getSignGroupHealth(signGroup string) *signGroupHealth {
+	health, exist := signGroupHealths[signGroup]
+	if !exist {
+		health = &signGroupHealth{results: make([]signResult, 0, signGroupHealthWindow)}
+		signGroupHealths[signGroup] = health
+	}
+	return health
+}
+
+func (h *signGroupHealth) addResult(res signResult) {
+	if len(h.results) < signGroupHealthWindow {
+		h.results = append(h.results, res)
+	} else {
+		h.results[h.next] = res
+	}
+	h.next = (h.next + 1) % signGroupHealthWindow
+	h.totalSigns++
+	h.totalDisagrees += uint64(res.disagrees)
+	if res.success {
+		h.consecutiveFailures = 0
+	} else {
+		h.totalFailures++
+		h.consecutiveFailures++
+	}
+}
+
+// stats returns success rate, median latency of succeeded signs and disagree count in window
+func (h *signGroupHealth) stats() (successRate float64, medianLatency time.Duration, disagrees int) {
+	if len(h.results) == 0 {
+		return 1, 0, 0
+	}
+	successes := 0
+	latencies := make([]time.Duration, 0, len(h.results))
+	for _, res := range h.results {
+		disagrees += res.disagrees
+		if res.success {
+			successes++
+			latencies = append(latencies, res.latency)
+		}
+	}
+	successRate = float64(successes) / float64(len(h.results))
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		medianLatency = latencies[len(latencies)/2]
+	}
+	return successRate, medianLatency, disagrees
+}
+
+// score in range (0, 1], the higher the healthier
+func (h *signGroupHealth) score() float64 {
+	successRate, medianLatency, disagrees := h.stats()
+	// smoothing to give the group a chance to recover
+	samples := float64(len(h.results))
+	smoothedRate := (successRate*samples + 1) / (samples + 1)
+	latencyFactor := 1 / (1 + float64(medianLatency)/float64(signLatencyBaseline))
+	disagreeFactor := 1 / (1 + float64(disagrees)/(samples+1))
+	score := smoothedRate * latencyFactor * disagreeFactor
+	if score < minSignGroupScore {
+		score = minSignGroupScore
+	}
+	return score
+}
+
+// recordSignResult record sign result of sign group,
+// returns true if the sign group should be degraded because of consecutive failures.
+func recordSignResult(signGroup string, success bool, latency time.Duration, disagrees int) (shouldDegrade bool) {
+	signGroupHealthsLock.Lock()
+	defer signGroupHealthsLock.Unlock()
+
+	health := getSignGroupHealth(signGroup)
+	health.addResult(signResult{
+		success:   success,
+		latency:   latency,
+		disagrees: disagrees,
+	})
+	if success || health.degraded || maxSignGroupFailures == 0 {
+		return false
+	}
+	if health.consecutiveFailures >= maxSignGroupFailures {
+		health.degraded = true
+		health.degradedTime = time.Now().Unix()
+		return true
+	}
+	return false
+}
+
+// shouldProbeSignGroup degraded sign group is probed after `minIntervalToAddSignGroup`
+func shouldProbeSignGroup(signGroup string) bool {
+	signGroupHealthsLock.Lock()
+	defer signGroupHealthsLock.Unlock()
+
+	health := getSignGroupHealth(signGroup)
+	return health.degradedTime+minIntervalToAddSignGroup <= time.Now().Unix()
+}
+
+func recordSignGroupProbe(signGroup string, probeErr error) {
+	signGroupHealthsLock.Lock()
+	defer signGroupHealthsLock.Unlock()
+
+	health := getSignGroupHealth(signGroup)
+	health.lastProbeTime = time.Now().Unix()
+	if probeErr != nil {
+		health.lastProbeError = probeErr.Error()
+		return
+	}
+	health.lastProbeError = ""
+	health.degraded = false
+	health.consecutiveFailures = 0
+}
+
+// probeSignGroup check the sign group is reachable and has the right members
+func (ni *NodeInfo) probeSignGroup(signGroup string) error {
+	if err := pingMPCNode(ni); err != nil {
+		return err
+	}
+	groupInfo, err := GetGroupByID(signGroup, ni.mpcRPCAddress)
+	if err != nil {
+		return err
+	}
+	if uint32(groupInfo.Count) != mpcNeededOracles || uint32(len(groupInfo.Enodes)) != mpcNeededOracles {
+		return fmt.Errorf("sign group member count mismatch, have %v (enodes %v) want %v", groupInfo.Count, len(groupInfo.Enodes), mpcNeededOracles)
+	}
+	for _, enode := range groupInfo.Enodes {
+		if !isEnodeExistIn(enode, allEnodes) {
+			return fmt.Errorf("sign group has unrelated enode %v", enode)
+		}
+	}
+	return nil
+}
+
+// getSignGroupIndexesByScore order sign group indexes by weighted random sampling on health scores
+func getSignGroupIndexesByScore(mpcNode *NodeInfo, signGroupIndexes []int) []int {
+	count := len(signGroupIndexes)
+	weights := make([]int64, count)
+	var totalWeight int64
+
+	signGroupHealthsLock.Lock()
+	for i, groupInd := range signGroupIndexes {
+		health := getSignGroupHealth(mpcNode.originSignGroups[groupInd])
+		weights[i] = int64(health.score() * scoreWeightPrecision)
+		totalWeight += weights[i]
+	}
+	signGroupHealthsLock.Unlock()
+
+	remains := make([]int, count)
+	copy(remains, signGroupIndexes)
+	result := make([]int, 0, count)
+	for len(remains) > 0 {
+		pick := len(remains) - 1
+		if totalWeight > 0 {
+			randValue, _ := rand.Int(rand.Reader, big.NewInt(totalWeight))
+			r := randValue.Int64()
+			for i, weight := range weights {
+				if r < weight {
+					pick = i
+					break
+				}
+				r -= weight
+			}
+		}
+		result = append(result, remains[pick])
+		totalWeight -= weights[pick]
+		remains = append(remains[:pick], remains[pick+1:]...)
+		weights = append(weights[:pick], weights[pick+1:]...)
+	}
+	return result
+}
+
+// GetSignGroupHealths get health info of all sign groups of initiators
+func GetSignGroupHealths() []*SignGroupHealth {
+	signGroupHealthsLock.Lock()
+	defer signGroupHealthsLock.Unlock()
+
+	result := make([]*SignGroupHealth, 0)
+	for _, mpcNode := range allInitiatorNodes {
+		for _, signGroup := range mpcNode.originSignGroups {
+			health := getSignGroupHealth(signGroup)
+			successRate, medianLatency, disagrees := health.stats()
+			result = append(result, &SignGroupHealth{
+				Initiator:           mpcNode.mpcUser.String(),
+				GroupID:             signGroup,
+				Score:               health.score(),
+				SuccessRate:         successRate,
+				MedianLatency:       medianLatency.String(),
+				Disagrees:           disagrees,
+				Samples:             len(health.results),
+				TotalSigns:          health.totalSigns,
+				TotalFailures:       health.totalFailures,
+				TotalDisagrees:      health.totalDisagrees,
+				ConsecutiveFailures: health.consecutiveFailures,
+				Degraded:            health.degraded,
+				DegradedTime:        health.degradedTime,
+				LastProbeTime:       health.lastProbeTime,
+				LastProbeError:      health.lastProbeError,
+			})
+		}
+	}
+	return result
+}
+
+func logSignGroupHealth(signGroup string) {
+	signGroupHealthsLock.Lock()
+	defer signGroupHealthsLock.Unlock()
+
+	health := getSignGroupHealth(signGroup)
+	successRate, medianLatency, disagrees := health.stats()
+	log.Info("sign group health", "signGroup", signGroup, "score", health.score(),
+		"successRate", successRate, "medianLatency", medianLatency.String(), "disagrees", disagrees,
+		"consecutiveFailures", health.consecutiveFailures, "degraded", health.degraded)
+}
@@ -0,0 +1,65 @@
+package mpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignGroupHealthScore(t *testing.T) {
+	fresh := &signGroupHealth{}
+	if score := fresh.score(); score != 1 {
+		t.Errorf("fresh sign group score = %v, want 1", score)
+	}
+
+	fast := &signGroupHealth{}
+	slow := &signGroupHealth{}
+	flaky := &signGroupHealth{}
+	for i := 0; i < 20; i++ {
+		fast.addResult(signResult{success: true, latency: 2 * time.Second})
+		slow.addResult(signResult{success: true, latency: 30 * time.Second})
+		flaky.addResult(signResult{success: i%2 == 0, latency: 2 * time.Second, disagrees: 1})
+	}
+	if fast.score() <= slow.score() {
+		t.Errorf("fast score %v should be greater than slow score %v", fast.score(), slow.score())
+	}
+	if fast.score() <= flaky.score() {
+		t.Errorf("fast score %v should be greater than flaky score %v", fast.score(), flaky.score())
+	}
+	if successRate, medianLatency, disagrees := flaky.stats(); successRate != 0.5 || medianLatency != 2*time.Second || disagrees != 20 {
+		t.Errorf("wrong flaky stats: successRate %v medianLatency %v disagrees %v", successRate, medianLatency, disagrees)
+	}
+}
+
+func TestSignGroupHealthWindow(t *testing.T) {
+	health := &signGroupHealth{}
+	for i := 0; i < signGroupHealthWindow+10; i++ {
+		health.addResult(signResult{success: false})
+	}
+	if len(health.results) != signGroupHealthWindow {
+		t.Errorf("window size = %v, want %v", len(health.results), signGroupHealthWindow)
+	}
+	if health.consecutiveFailures != signGroupHealthWindow+10 {
+		t.Errorf("consecutive failures = %v, want %v", health.consecutiveFailures, signGroupHealthWindow+10)
+	}
+	health.addResult(signResult{success: true, latency: time.Second})
+	if health.consecutiveFailures != 0 {
+		t.Errorf("consecutive failures should be reset after success")
+	}
+	if health.score() < minSignGroupScore {
+		t.Errorf("score %v should not be less than %v", health.score(), minSignGroupScore)
+	}
+}
+
+func TestGetSignGroupIndexesByScore(t *testing.T) {
+	node := &NodeInfo{originSignGroups: []string{"test-group-a", "test-group-b", "test-group-c"}}
+	indexes := []int{0, 2}
+	for i := 0; i < 10; i++ {
+		res := getSignGroupIndexesByScore(node, indexes)
+		if len(res) != 2 || res[0] == res[1] || (res[0] != 0 && res[0] != 2) || (res[1] != 0 && res[1] != 2) {
+			t.Fatalf("wrong sign group indexes %v", res)
+		}
+	}
+	if len(indexes) != 2 || indexes[0] != 0 || indexes[1] != 2 {
+		t.Errorf("input indexes are modified: %v", indexes)
+	}
+}
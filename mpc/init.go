@@ -164,6 +164,11 @@ func GetAllEnodes() []string {
 	return allEnodes
 }
 
+// GetSignTimeout get sign timeout
+func GetSignTimeout() time.Duration {
+	return mpcSignTimeout
+}
+
 // setMPCRPCAddress set mpc node rpc address
 func (ni *NodeInfo) setMPCRPCAddress(url string) {
 	ni.mpcRPCAddress = url
@@ -217,7 +222,20 @@ func (ni *NodeInfo) deleteSignGroup(groupIndex int) {
 	}
 }
 
-// checkAndAddSignGroups add sign group
+// addSignGroup add sign group back to usable sign groups
+func (ni *NodeInfo) addSignGroup(groupIndex int) {
+	ni.signGroupsLock.Lock()
+	defer ni.signGroupsLock.Unlock()
+
+	for _, groupInd := range ni.usableSignGroupIndexes {
+		if groupInd == groupIndex {
+			return
+		}
+	}
+	ni.usableSignGroupIndexes = append(ni.usableSignGroupIndexes, groupIndex)
+}
+
+// checkAndAddSignGroups probe degraded sign groups and add them back if healthy
 func (ni *NodeInfo) checkAndAddSignGroups() {
 	for {
 		usableGroupIndexes := ni.getUsableSignGroupIndexes()
@@ -233,17 +251,18 @@ func (ni *NodeInfo) checkAndAddSignGroups() {
 				continue
 			}
 			signGroup := ni.originSignGroups[i]
-			signFailure := signGroupFailuresMap[signGroup]
-			if signFailure.lastTime+minIntervalToAddSignGroup > time.Now().Unix() {
+			if !shouldProbeSignGroup(signGroup) {
 				continue
 			}
-			log.Info("check and add sign group", "signGroup", signGroup)
-			ni.usableSignGroupIndexes = append(ni.usableSignGroupIndexes, i)
-			// reset when readd
-			signGroupFailuresMap[signGroup] = signFailures{
-				count:    0,
-				lastTime: time.Now().Unix(),
+			err := ni.probeSignGroup(signGroup)
+			recordSignGroupProbe(signGroup, err)
+			if err != nil {
+				log.Warn("probe degraded sign group failed", "signGroup", signGroup, "err", err)
+				continue
 			}
+			log.Info("probe degraded sign group success, add it back", "signGroup", signGroup)
+			ni.addSignGroup(i)
+			logSignGroupHealth(signGroup)
 		}
 		time.Sleep(60 * time.Second)
 	}
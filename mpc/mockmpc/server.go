@@ -0,0 +1,500 @@
+// Package mockmpc is an in memory mock of the smpc node json-rpc server.
+//
+// It simulates all nodes of a mpc group in one process, the nodes are
+// distinguished by url path (eg. `http://127.0.0.1:5871/1` is the node with index 1,
+// and the root path is the node with index 0).
+// Sign requests are queued for the sign group members, and real ECDSA rsvs
+// are returned when all the members agree.
+package mockmpc
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tools/rlp"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+const (
+	mpcWalletServiceID = 30400
+
+	signTypeEC256K1 = "ECDSA"
+
+	statusSuccess = "Success"
+	statusPending = "Pending"
+	statusFailure = "Failure"
+	statusTimeout = "Timeout"
+
+	replyAgree    = "Agree"
+	replyDisagree = "DisAgree"
+
+	acceptAgree = "AGREE"
+
+	defaultAPIPrefix   = "smpc_"
+	defaultSignTimeout = 120 * time.Second
+)
+
+var (
+	mpcSigner = types.MakeSigner("EIP155", big.NewInt(mpcWalletServiceID))
+
+	errUnknownSignKey    = errors.New("unknown sign key")
+	errUnknownSignGroup  = errors.New("unknown sign group")
+	errNotSignGroupNode  = errors.New("node is not member of sign group")
+	errUnknownPublicKey  = errors.New("unknown public key")
+	errWrongTxType       = errors.New("wrong tx type")
+	errAlreadyReplied    = errors.New("already replied")
+	errSignNotPending    = errors.New("sign is not pending")
+	errUnsupportedKey    = errors.New("unsupported key type")
+	errMsgHashMismatch   = errors.New("message hash mismatch")
+	errWrongNodeIndex    = errors.New("wrong node index")
+	errWrongMsgHashCount = errors.New("wrong count of msg hashes")
+)
+
+// Config mock mpc config
+type Config struct {
+	APIPrefix     string
+	NeededOracles int
+	TotalOracles  int
+	// sign group members (node indexes), the group ID is generated
+	SignGroups  [][]int
+	SignTimeout time.Duration
+}
+
+// Server mock mpc server
+type Server struct {
+	apiPrefix   string
+	signTimeout time.Duration
+
+	groupID    string
+	enodes     []string
+	signGroups map[string][]int // key is sign group ID
+
+	keys   map[string]*ecdsa.PrivateKey // key is lower hex public key without 0x prefix
+	nonces map[string]uint64            // key is lower hex account
+	signs  map[string]*signRequest      // key is sign key ID
+	order  []string                     // sign key IDs in request order
+
+	lock sync.Mutex
+}
+
+type signRequest struct {
+	key        string
+	account    string
+	initiator  int // node index
+	data       *SignData
+	members    []int
+	replies    map[int]*SignReply // key is node index
+	status     string
+	rsvs       []string
+	createTime time.Time
+}
+
+// NewServer new mock mpc server
+func NewServer(cfg *Config) (*Server, error) {
+	if cfg.NeededOracles <= 0 || cfg.TotalOracles < cfg.NeededOracles {
+		return nil, fmt.Errorf("wrong threshold %v/%v", cfg.NeededOracles, cfg.TotalOracles)
+	}
+	s := &Server{
+		apiPrefix:   cfg.APIPrefix,
+		signTimeout: cfg.SignTimeout,
+		groupID:     deterministicID("group", cfg.NeededOracles, cfg.TotalOracles),
+		enodes:      make([]string, cfg.TotalOracles),
+		signGroups:  make(map[string][]int, len(cfg.SignGroups)),
+		keys:        make(map[string]*ecdsa.PrivateKey),
+		nonces:      make(map[string]uint64),
+		signs:       make(map[string]*signRequest),
+	}
+	if s.apiPrefix == "" {
+		s.apiPrefix = defaultAPIPrefix
+	}
+	if s.signTimeout == 0 {
+		s.signTimeout = defaultSignTimeout
+	}
+	for i := range s.enodes {
+		s.enodes[i] = fmt.Sprintf("enode://%v@127.0.0.1:%d", deterministicID("node", i), 40000+i)
+	}
+	for _, members := range cfg.SignGroups {
+		if len(members) != cfg.NeededOracles {
+			return nil, fmt.Errorf("sign group members count %v is not %v", len(members), cfg.NeededOracles)
+		}
+		for _, member := range members {
+			if member < 0 || member >= cfg.TotalOracles {
+				return nil, fmt.Errorf("sign group member %v out of range", member)
+			}
+		}
+		signGroupID := deterministicID("signgroup", members)
+		if _, exist := s.signGroups[signGroupID]; exist {
+			return nil, fmt.Errorf("duplicate sign group %v", members)
+		}
+		s.signGroups[signGroupID] = members
+	}
+	return s, nil
+}
+
+// deterministicID generate 64 bytes hex ID (the same as the smpc group ID and enode ID),
+// which is kept unchanged after restart to simplify the router config.
+func deterministicID(kind string, args ...interface{}) string {
+	return common.ToHex(crypto.Keccak512([]byte(fmt.Sprintf("mockmpc:%v:%v", kind, args))))[2:]
+}
+
+// GroupID get mpc group ID
+func (s *Server) GroupID() string {
+	return s.groupID
+}
+
+// Enodes get enodes of all nodes
+func (s *Server) Enodes() []string {
+	return s.enodes
+}
+
+// SignGroups get sign group IDs of which the node is member
+func (s *Server) SignGroups(nodeIndex int) []string {
+	result := make([]string, 0, len(s.signGroups))
+	for groupID, members := range s.signGroups {
+		if containsInt(members, nodeIndex) {
+			result = append(result, groupID)
+		}
+	}
+	return result
+}
+
+// NodeURL get rpc url of node
+func NodeURL(baseURL string, nodeIndex int) string {
+	return fmt.Sprintf("%v/%d", strings.TrimSuffix(baseURL, "/"), nodeIndex)
+}
+
+// AddKey add threshold key, returns hex public key
+func (s *Server) AddKey(priv *ecdsa.PrivateKey) string {
+	pubkey := common.ToHex(crypto.FromECDSAPub(&priv.PublicKey))
+	s.lock.Lock()
+	s.keys[strings.ToLower(pubkey[2:])] = priv
+	s.lock.Unlock()
+	return pubkey
+}
+
+// GenerateKey generate threshold key, returns hex public key
+func (s *Server) GenerateKey() (string, error) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+	return s.AddKey(priv), nil
+}
+
+func (s *Server) getKey(pubkey string) *ecdsa.PrivateKey {
+	pubkey = strings.ToLower(strings.TrimPrefix(pubkey, "0x"))
+	return s.keys[pubkey]
+}
+
+// ServeHTTP handle json-rpc requests, node index is the url path
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req jsonrpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONRPC(w, nil, nil, err)
+		return
+	}
+	nodeIndex := 0
+	if path := strings.Trim(r.URL.Path, "/"); path != "" {
+		index, err := strconv.Atoi(path)
+		if err != nil || index < 0 || index >= len(s.enodes) {
+			writeJSONRPC(w, req.ID, nil, errWrongNodeIndex)
+			return
+		}
+		nodeIndex = index
+	}
+	result, err := s.dispatch(nodeIndex, strings.TrimPrefix(req.Method, s.apiPrefix), req.Params)
+	if err != nil {
+		log.Debug("[mockmpc] call failed", "node", nodeIndex, "method", req.Method, "err", err)
+		result = &Response{Status: "Error", Error: err.Error()}
+	}
+	writeJSONRPC(w, req.ID, result, nil)
+}
+
+func (s *Server) dispatch(nodeIndex int, method string, params []json.RawMessage) (interface{}, error) {
+	var param string
+	if len(params) > 0 {
+		if err := json.Unmarshal(params[0], &param); err != nil {
+			return nil, err
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch method {
+	case "getEnode":
+		return &Response{Status: statusSuccess, Data: map[string]string{"Enode": s.enodes[nodeIndex]}}, nil
+	case "getGroupByID":
+		return s.getGroupByID(param)
+	case "getSignNonce":
+		return newResultResponse(fmt.Sprintf("%d", s.nonces[strings.ToLower(param)])), nil
+	case "sign":
+		return s.sign(nodeIndex, param)
+	case "getSignStatus":
+		return s.getSignStatus(param)
+	case "getCurNodeSignInfo":
+		return &Response{Status: statusSuccess, Data: s.getCurNodeSignInfo(nodeIndex)}, nil
+	case "acceptSign":
+		return s.acceptSign(nodeIndex, param)
+	default:
+		return nil, fmt.Errorf("unknown method %v", method)
+	}
+}
+
+func (s *Server) getGroupByID(groupID string) (interface{}, error) {
+	var enodes []string
+	if groupID == s.groupID {
+		enodes = s.enodes
+	} else {
+		members, exist := s.signGroups[groupID]
+		if !exist {
+			return nil, errUnknownSignGroup
+		}
+		enodes = make([]string, len(members))
+		for i, member := range members {
+			enodes[i] = s.enodes[member]
+		}
+	}
+	groupInfo := &GroupInfo{
+		GID:    groupID,
+		Count:  len(enodes),
+		Enodes: enodes,
+	}
+	return &Response{Status: statusSuccess, Data: groupInfo}, nil
+}
+
+func decodeRawTx(raw string) (tx *types.Transaction, sender string, err error) {
+	tx = new(types.Transaction)
+	if err = rlp.DecodeBytes(common.FromHex(raw), tx); err != nil {
+		return nil, "", err
+	}
+	from, err := types.Sender(mpcSigner, tx)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, strings.ToLower(from.String()), nil
+}
+
+func (s *Server) sign(nodeIndex int, raw string) (interface{}, error) {
+	tx, account, err := decodeRawTx(raw)
+	if err != nil {
+		return nil, err
+	}
+	var data SignData
+	if err = json.Unmarshal(tx.Data(), &data); err != nil {
+		return nil, err
+	}
+	if data.TxType != "SIGN" {
+		return nil, errWrongTxType
+	}
+	if data.Keytype != signTypeEC256K1 {
+		return nil, errUnsupportedKey
+	}
+	if s.getKey(data.PubKey) == nil {
+		return nil, errUnknownPublicKey
+	}
+	members, exist := s.signGroups[data.GroupID]
+	if !exist {
+		return nil, errUnknownSignGroup
+	}
+	if !containsInt(members, nodeIndex) {
+		return nil, errNotSignGroupNode
+	}
+
+	keyID := common.ToHex(crypto.Keccak256(tx.Data(), []byte(account)))
+	if _, exist = s.signs[keyID]; exist {
+		return nil, errors.New("sign request already exist")
+	}
+	req := &signRequest{
+		key:        keyID,
+		account:    account,
+		initiator:  nodeIndex,
+		data:       &data,
+		members:    members,
+		replies:    make(map[int]*SignReply, len(members)),
+		status:     statusPending,
+		createTime: time.Now(),
+	}
+	// the initiator agrees its own sign request
+	req.replies[nodeIndex] = s.newSignReply(nodeIndex, req, replyAgree)
+	s.signs[keyID] = req
+	s.order = append(s.order, keyID)
+	s.nonces[account]++
+	s.checkSignRequest(req)
+	log.Info("[mockmpc] new sign request", "keyID", keyID, "initiator", nodeIndex, "account", account, "groupID", data.GroupID, "msgHash", data.MsgHash)
+	return newResultResponse(keyID), nil
+}
+
+func (s *Server) newSignReply(nodeIndex int, req *signRequest, status string) *SignReply {
+	return &SignReply{
+		Enode:     s.enodes[nodeIndex],
+		Status:    status,
+		TimeStamp: common.NowMilliStr(),
+		Initiator: strconv.FormatBool(nodeIndex == req.initiator),
+	}
+}
+
+// checkSignRequest update status of pending sign request
+func (s *Server) checkSignRequest(req *signRequest) {
+	if req.status != statusPending {
+		return
+	}
+	agrees := 0
+	for _, reply := range req.replies {
+		switch reply.Status {
+		case replyDisagree:
+			req.status = statusFailure
+			return
+		case replyAgree:
+			agrees++
+		}
+	}
+	if agrees < len(req.members) {
+		if time.Since(req.createTime) > s.signTimeout {
+			req.status = statusTimeout
+		}
+		return
+	}
+	rsvs, err := s.signMsgHashes(req.data)
+	if err != nil {
+		log.Warn("[mockmpc] sign message hashes failed", "keyID", req.key, "err", err)
+		req.status = statusFailure
+		return
+	}
+	req.rsvs = rsvs
+	req.status = statusSuccess
+}
+
+func (s *Server) signMsgHashes(data *SignData) ([]string, error) {
+	priv := s.getKey(data.PubKey)
+	if priv == nil {
+		return nil, errUnknownPublicKey
+	}
+	rsvs := make([]string, len(data.MsgHash))
+	for i, msgHash := range data.MsgHash {
+		signature, err := crypto.Sign(common.FromHex(msgHash), priv)
+		if err != nil {
+			return nil, err
+		}
+		rsvs[i] = common.ToHex(signature)
+	}
+	return rsvs, nil
+}
+
+func (s *Server) getSignStatus(keyID string) (interface{}, error) {
+	req, exist := s.signs[keyID]
+	if !exist {
+		return nil, errUnknownSignKey
+	}
+	s.checkSignRequest(req)
+	signStatus := &SignStatus{
+		Status:    req.status,
+		Rsv:       req.rsvs,
+		AllReply:  make([]*SignReply, 0, len(req.members)),
+		TimeStamp: common.NowMilliStr(),
+	}
+	for _, member := range req.members {
+		reply, replied := req.replies[member]
+		if !replied {
+			reply = &SignReply{Enode: s.enodes[member]}
+		}
+		signStatus.AllReply = append(signStatus.AllReply, reply)
+	}
+	result, err := json.Marshal(signStatus)
+	if err != nil {
+		return nil, err
+	}
+	return newResultResponse(string(result)), nil
+}
+
+func (s *Server) getCurNodeSignInfo(nodeIndex int) []*SignInfoData {
+	result := make([]*SignInfoData, 0)
+	for _, keyID := range s.order {
+		req := s.signs[keyID]
+		s.checkSignRequest(req)
+		if req.status != statusPending || !containsInt(req.members, nodeIndex) {
+			continue
+		}
+		if _, replied := req.replies[nodeIndex]; replied {
+			continue
+		}
+		result = append(result, &SignInfoData{
+			Account:    req.account,
+			GroupID:    req.data.GroupID,
+			Key:        req.key,
+			KeyType:    req.data.Keytype,
+			Mode:       req.data.Mode,
+			MsgHash:    req.data.MsgHash,
+			MsgContext: req.data.MsgContext,
+			Nonce:      "0",
+			PubKey:     req.data.PubKey,
+			ThresHold:  req.data.ThresHold,
+			TimeStamp:  req.data.TimeStamp,
+		})
+	}
+	return result
+}
+
+func (s *Server) acceptSign(nodeIndex int, raw string) (interface{}, error) {
+	tx, account, err := decodeRawTx(raw)
+	if err != nil {
+		return nil, err
+	}
+	var data AcceptData
+	if err = json.Unmarshal(tx.Data(), &data); err != nil {
+		return nil, err
+	}
+	if data.TxType != "ACCEPTSIGN" {
+		return nil, errWrongTxType
+	}
+	req, exist := s.signs[data.Key]
+	if !exist {
+		return nil, errUnknownSignKey
+	}
+	if !containsInt(req.members, nodeIndex) {
+		return nil, errNotSignGroupNode
+	}
+	if _, replied := req.replies[nodeIndex]; replied {
+		return nil, errAlreadyReplied
+	}
+	s.checkSignRequest(req)
+	if req.status != statusPending {
+		return nil, errSignNotPending
+	}
+	if len(data.MsgHash) != len(req.data.MsgHash) {
+		return nil, errWrongMsgHashCount
+	}
+	for i, msgHash := range data.MsgHash {
+		if !strings.EqualFold(msgHash, req.data.MsgHash[i]) {
+			return nil, errMsgHashMismatch
+		}
+	}
+	replyStatus := replyDisagree
+	if strings.EqualFold(data.Accept, acceptAgree) {
+		replyStatus = replyAgree
+	}
+	req.replies[nodeIndex] = s.newSignReply(nodeIndex, req, replyStatus)
+	s.checkSignRequest(req)
+	log.Info("[mockmpc] accept sign", "keyID", data.Key, "node", nodeIndex, "account", account, "accept", data.Accept, "status", req.status)
+	return newResultResponse("Success"), nil
+}
+
+func containsInt(items []int, item int) bool {
+	for _, x := range items {
+		if x == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package mockmpc
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+type jsonrpcRequest struct {
+	Version string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+	ID      json.RawMessage   `json:"id"`
+}
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonrpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+func writeJSONRPC(w http.ResponseWriter, id json.RawMessage, result interface{}, err error) {
+	resp := &jsonrpcResponse{
+		Version: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+	if err != nil {
+		resp.Error = &jsonrpcError{Code: -32600, Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if errw := json.NewEncoder(w).Encode(resp); errw != nil {
+		log.Warn("[mockmpc] write response failed", "err", errw)
+	}
+}
+
+// Response smpc api response
+type Response struct {
+	Status string
+	Tip    string
+	Error  string
+	Data   interface{}
+}
+
+// DataResult result
+type DataResult struct {
+	Result string `json:"result"`
+}
+
+func newResultResponse(result string) *Response {
+	return &Response{Status: statusSuccess, Data: &DataResult{Result: result}}
+}
+
+// GroupInfo group info
+type GroupInfo struct {
+	GID    string
+	Count  int
+	Enodes []string
+}
+
+// SignReply sign reply
+type SignReply struct {
+	Enode     string
+	Status    string
+	TimeStamp string
+	Initiator string
+}
+
+// SignStatus sign status
+type SignStatus struct {
+	Status    string
+	Rsv       []string
+	Tip       string
+	Error     string
+	AllReply  []*SignReply
+	TimeStamp string
+}
+
+// SignInfoData sign info
+type SignInfoData struct {
+	Account    string
+	GroupID    string
+	Key        string
+	KeyType    string
+	Mode       string
+	MsgHash    []string
+	MsgContext []string
+	Nonce      string
+	PubKey     string
+	ThresHold  string
+	TimeStamp  string
+}
+
+// SignData sign data
+type SignData struct {
+	TxType     string
+	PubKey     string
+	MsgHash    []string
+	MsgContext []string
+	Keytype    string
+	GroupID    string
+	ThresHold  string
+	Mode       string
+	TimeStamp  string
+}
+
+// AcceptData accept data
+type AcceptData struct {
+	TxType     string
+	Key        string
+	Accept     string
+	MsgHash    []string
+	MsgContext []string
+	TimeStamp  string
+}
@@ -0,0 +1,153 @@
+package mpc
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc/mockmpc"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
+)
+
+func newTestNodeInfo(t *testing.T, rpcAddr string) *NodeInfo {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(priv.PublicKey),
+		PrivateKey: priv,
+	}
+	return &NodeInfo{
+		keyWrapper:    key,
+		mpcUser:       key.Address,
+		mpcRPCAddress: rpcAddr,
+	}
+}
+
+// startTestOracle start oracle node accepting all pending sign infos with agreeResult,
+// the returned stop func waits the oracle to exit and returns the accepted count.
+func startTestOracle(t *testing.T, agreeResult string) (stop func() int) {
+	quit := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		accepted := 0
+		defer func() { done <- accepted }()
+		for {
+			signInfos, err := GetCurNodeSignInfo(0)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, info := range signInfos {
+				if _, err = DoAcceptSign(info.Key, agreeResult, info.MsgHash, info.MsgContext); err != nil {
+					t.Error(err)
+					return
+				}
+				accepted++
+			}
+			select {
+			case <-quit:
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}()
+	return func() int {
+		close(quit)
+		return <-done
+	}
+}
+
+// startMockMPC start mock mpc server, setup an initiator of node 0 and an oracle of node 1
+func startMockMPC(t *testing.T) (signPubkey string, initiator *NodeInfo, cleanup func()) {
+	server, err := mockmpc.NewServer(&mockmpc.Config{
+		NeededOracles: 2,
+		TotalOracles:  3,
+		SignGroups:    [][]int{{0, 1}},
+		SignTimeout:   30 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+
+	signPubkey, err = server.GenerateKey()
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+
+	setMPCGroup(server.GroupID(), 0, 2, 3)
+	initiator = newTestNodeInfo(t, mockmpc.NodeURL(httpServer.URL, 0))
+	initiator.setOriginSignGroups(server.SignGroups(0))
+	allInitiatorNodes = []*NodeInfo{initiator}
+
+	// the default node is the oracle doing the accept job
+	setDefaultMPCNodeInfo(newTestNodeInfo(t, mockmpc.NodeURL(httpServer.URL, 1)))
+	initSelfEnode()
+	initAllEnodes()
+	verifyInitiators([]string{initiator.mpcUser.String()})
+
+	cleanup = func() {
+		allInitiatorNodes = nil
+		httpServer.Close()
+	}
+	return signPubkey, initiator, cleanup
+}
+
+func TestSignWithMockMPC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip mock mpc sign test in short mode")
+	}
+	signPubkey, _, cleanup := startMockMPC(t)
+	defer cleanup()
+	store, restore := useTestSignRequestStore()
+	defer restore()
+
+	msgHash := common.Keccak256Hash([]byte("mock mpc sign test"))
+	msgContext := newTestSwapMsgContext(t)[0]
+
+	// agree
+	stopOracle := startTestOracle(t, "AGREE")
+	keyID, rsvs, err := DoSignOneEC(signPubkey, msgHash.String(), msgContext)
+	if accepted := stopOracle(); accepted != 1 {
+		t.Errorf("want 1 accepted sign info, have %v", accepted)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsvs) != 1 {
+		t.Fatalf("wrong rsvs count %v", len(rsvs))
+	}
+	pub, err := crypto.Ecrecover(msgHash.Bytes(), common.FromHex(rsvs[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pub, common.FromHex(signPubkey)) {
+		t.Errorf("recovered public key mismatch")
+	}
+	if status := store.getStatus(keyID); status != mongodb.SignRequestSigned {
+		t.Errorf("sign request has status '%v', want '%v'", status, mongodb.SignRequestSigned)
+	}
+
+	// disagree, retry until give up
+	stopOracle = startTestOracle(t, "DISAGREE")
+	_, _, err = DoSignOneEC(signPubkey, msgHash.String(), msgContext)
+	if accepted := stopOracle(); accepted != retrySignLoop {
+		t.Errorf("want %v disagreed sign infos, have %v", retrySignLoop, accepted)
+	}
+	if !errors.Is(err, errDoSignFailed) {
+		t.Errorf("sign should fail when oracle disagrees, have error %v", err)
+	}
+	for _, keyID := range store.getKeyIDs() {
+		if status := store.getStatus(keyID); status == mongodb.SignRequestPending {
+			t.Errorf("sign request %v is left pending", keyID)
+		}
+	}
+}
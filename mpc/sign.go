@@ -1,7 +1,6 @@
 package mpc
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"math/big"
@@ -9,7 +8,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
 	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
 	"github.com/anyswap/CrossChain-Router/v3/tools/rlp"
@@ -30,17 +29,11 @@ var (
 	errWrongSignatureLength = errors.New("wrong signature length")
 	errNoUsableSignGroups   = errors.New("no usable sign groups")
 
-	// delete if fail too many times consecutively, 0 means disable checking
+	// degrade if fail too many times consecutively, 0 means disable checking
 	maxSignGroupFailures      = 0
-	minIntervalToAddSignGroup = int64(3600)                   // seconds
-	signGroupFailuresMap      = make(map[string]signFailures) // key is groupID
+	minIntervalToAddSignGroup = int64(3600) // seconds
 )
 
-type signFailures struct {
-	count    int
-	lastTime int64
-}
-
 func pingMPCNode(nodeInfo *NodeInfo) (err error) {
 	rpcAddr := nodeInfo.mpcRPCAddress
 	for j := 0; j < pingCount; j++ {
@@ -72,6 +65,7 @@ func DoSignOne(signType, signPubkey, msgHash, msgContext string) (keyID string,
 // DoSign mpc sign msgHash with context msgContext
 func DoSign(signType, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
 	log.Debug("mpc DoSign", "msgHash", msgHash, "msgContext", msgContext, "signType", signType)
+	defer func(start time.Time) { metrics.ObserveMPCSign(signType, start, err) }(time.Now())
 	if signPubkey == "" {
 		return "", nil, errSignWithoutPublickey
 	}
@@ -81,24 +75,16 @@ func DoSign(signType, signPubkey string, msgHash, msgContext []string) (keyID st
 				continue
 			}
 			signGroupIndexes := mpcNode.getUsableSignGroupIndexes()
-			signGroupsCount := int64(len(signGroupIndexes))
-			if signGroupsCount == 0 {
+			if len(signGroupIndexes) == 0 {
 				err = errNoUsableSignGroups
 				continue
 			}
-			// randomly pick first subgroup to sign
-			randIndex, _ := rand.Int(rand.Reader, big.NewInt(signGroupsCount))
-			startIndex := randIndex.Int64()
-			i := startIndex
-			for {
-				keyID, rsvs, err = doSignImpl(mpcNode, signGroupIndexes[i], signType, signPubkey, msgHash, msgContext)
+			// pick subgroups to sign by weighted health scores
+			for _, signGroupIndex := range getSignGroupIndexesByScore(mpcNode, signGroupIndexes) {
+				keyID, rsvs, err = doSignImpl(mpcNode, signGroupIndex, signType, signPubkey, msgHash, msgContext)
 				if err == nil {
 					return keyID, rsvs, nil
 				}
-				i = (i + 1) % signGroupsCount
-				if i == startIndex {
-					break
-				}
 			}
 		}
 		time.Sleep(2 * time.Second)
@@ -108,11 +94,68 @@ func DoSign(signType, signPubkey string, msgHash, msgContext []string) (keyID st
 }
 
 func doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
-	nonce, err := GetSignNonce(mpcNode.mpcUser.String(), mpcNode.mpcRPCAddress)
+	signGroup := mpcNode.originSignGroups[signGroupIndex]
+	rawTX, err := buildSignRawTx(mpcNode, signGroup, signType, signPubkey, msgHash, msgContext)
 	if err != nil {
 		return "", nil, err
 	}
-	signGroup := mpcNode.originSignGroups[signGroupIndex]
+
+	rpcAddr := mpcNode.mpcRPCAddress
+	keyID, err = Sign(rawTX, rpcAddr)
+	if err != nil {
+		return "", nil, err
+	}
+	// persist to resume getting sign result after restart
+	if addSignRequest(keyID, rpcAddr, signGroup, signType, signPubkey, msgHash, msgContext) {
+		// failure paths return empty keyID, so do not capture the named result
+		signKeyID := keyID
+		defer func() { updateSignRequest(signKeyID, rsvs, err) }()
+	}
+
+	signStart := time.Now()
+	rsvs, signStatus, err := getSignResult(keyID, rpcAddr)
+	var disagrees int
+	if signStatus != nil {
+		disagrees = signStatus.DisagreeCount()
+	}
+	if recordSignResult(signGroup, err == nil, time.Since(signStart), disagrees) {
+		log.Error("degrade sign group as consecutive failures", "signGroup", signGroup)
+		mpcNode.deleteSignGroup(signGroupIndex)
+		logSignGroupHealth(signGroup)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if isEC(signType) {
+		err = addUsedRValues(signPubkey, rsvs)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return keyID, rsvs, nil
+}
+
+// prevent multiple use of same r value
+func addUsedRValues(signPubkey string, rsvs []string) error {
+	for _, rsv := range rsvs {
+		signature := common.FromHex(rsv)
+		if len(signature) != crypto.SignatureLength {
+			return errWrongSignatureLength
+		}
+		r := common.ToHex(signature[:32])
+		err := signDB.AddUsedRValue(signPubkey, r)
+		if err != nil {
+			return errRValueIsUsed
+		}
+	}
+	return nil
+}
+
+func buildSignRawTx(mpcNode *NodeInfo, signGroup, signType, signPubkey string, msgHash, msgContext []string) (string, error) {
+	nonce, err := GetSignNonce(mpcNode.mpcUser.String(), mpcNode.mpcRPCAddress)
+	if err != nil {
+		return "", err
+	}
 	txdata := SignData{
 		TxType:     "SIGN",
 		PubKey:     signPubkey,
@@ -126,76 +169,30 @@ func doSignImpl(mpcNode *NodeInfo, signGroupIndex int, signType, signPubkey stri
 	}
 	payload, err := json.Marshal(txdata)
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 	if verifySignatureInAccept {
 		// append payload signature into the end of message context
 		sighash := common.Keccak256Hash(payload)
 		signature, errf := crypto.Sign(sighash[:], mpcNode.keyWrapper.PrivateKey)
 		if errf != nil {
-			return "", nil, errf
+			return "", errf
 		}
 		txdata.MsgContext = append(txdata.MsgContext, common.ToHex(signature))
 		payload, _ = json.Marshal(txdata)
 	}
-
-	rawTX, err := BuildMPCRawTx(nonce, payload, mpcNode.keyWrapper)
-	if err != nil {
-		return "", nil, err
-	}
-
-	rpcAddr := mpcNode.mpcRPCAddress
-	keyID, err = Sign(rawTX, rpcAddr)
-	if err != nil {
-		return "", nil, err
-	}
-
-	rsvs, err = getSignResult(keyID, rpcAddr)
-	if err != nil {
-		if maxSignGroupFailures > 0 {
-			old := signGroupFailuresMap[signGroup]
-			signGroupFailuresMap[signGroup] = signFailures{
-				count:    old.count + 1,
-				lastTime: time.Now().Unix(),
-			}
-			if old.count+1 >= maxSignGroupFailures {
-				log.Error("delete sign group as consecutive failures", "signGroup", signGroup)
-				mpcNode.deleteSignGroup(signGroupIndex)
-			}
-		}
-		return "", nil, err
-	}
-	if maxSignGroupFailures > 0 {
-		// reset when succeed
-		signGroupFailuresMap[signGroup] = signFailures{
-			count:    0,
-			lastTime: time.Now().Unix(),
-		}
-	}
-	if isEC(signType) { // prevent multiple use of same r value
-		for _, rsv := range rsvs {
-			signature := common.FromHex(rsv)
-			if len(signature) != crypto.SignatureLength {
-				return "", nil, errWrongSignatureLength
-			}
-			r := common.ToHex(signature[:32])
-			err = mongodb.AddUsedRValue(signPubkey, r)
-			if err != nil {
-				return "", nil, errRValueIsUsed
-			}
-		}
-	}
-	return keyID, rsvs, nil
+	return BuildMPCRawTx(nonce, payload, mpcNode.keyWrapper)
 }
 
 // GetSignStatusByKeyID get sign status by keyID
 func GetSignStatusByKeyID(keyID string) (rsvs []string, err error) {
-	return getSignResult(keyID, defaultMPCNode.mpcRPCAddress)
+	rsvs, _, err = getSignResult(keyID, defaultMPCNode.mpcRPCAddress)
+	return rsvs, err
 }
 
-func getSignResult(keyID, rpcAddr string) (rsvs []string, err error) {
+// getSignResult returns the last sign status (if any) to count disagreements
+func getSignResult(keyID, rpcAddr string) (rsvs []string, signStatus *SignStatus, err error) {
 	log.Info("start get sign status", "keyID", keyID)
-	var signStatus *SignStatus
 	i := 0
 	signTimer := time.NewTimer(mpcSignTimeout)
 	defer signTimer.Stop()
@@ -209,7 +206,11 @@ LOOP_GET_SIGN_STATUS:
 			}
 			break LOOP_GET_SIGN_STATUS
 		default:
-			signStatus, err = GetSignStatus(keyID, rpcAddr)
+			var status *SignStatus
+			status, err = GetSignStatus(keyID, rpcAddr)
+			if status != nil {
+				signStatus = status
+			}
 			if err == nil {
 				rsvs = signStatus.Rsv
 				break LOOP_GET_SIGN_STATUS
@@ -225,10 +226,10 @@ LOOP_GET_SIGN_STATUS:
 	}
 	if len(rsvs) == 0 || err != nil {
 		log.Info("get sign status failed", "keyID", keyID, "retryCount", i, "err", err)
-		return nil, errGetSignResultFailed
+		return nil, signStatus, errGetSignResultFailed
 	}
 	log.Info("get sign status success", "keyID", keyID, "retryCount", i)
-	return rsvs, nil
+	return rsvs, signStatus, nil
 }
 
 // BuildMPCRawTx build mpc raw tx
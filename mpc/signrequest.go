@@ -0,0 +1,98 @@
+package mpc
+
+import (
+	"encoding/json"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// SignDB database operations of signing
+type SignDB struct {
+	AddUsedRValue           func(pubkey, r string) error
+	AddSignRequest          func(req *mongodb.MgoSignRequest) error
+	UpdateSignRequestStatus func(keyID, status string, rsvs []string, memo string) error
+}
+
+var signDB = &SignDB{
+	AddUsedRValue:           mongodb.AddUsedRValue,
+	AddSignRequest:          mongodb.AddSignRequest,
+	UpdateSignRequestStatus: mongodb.UpdateSignRequestStatus,
+}
+
+// SetSignDB replace database operations of signing (use for testing),
+// returns the old one to restore.
+func SetSignDB(db *SignDB) (old *SignDB) {
+	old, signDB = signDB, db
+	return old
+}
+
+// addSignRequest persist in-flight sign request of swap,
+// returns false if msg context is not of swap or persisting failed.
+func addSignRequest(keyID, rpcAddr, signGroup, signType, signPubkey string, msgHash, msgContext []string) bool {
+	if len(msgContext) == 0 {
+		return false
+	}
+	var args tokens.BuildTxArgs
+	if err := json.Unmarshal([]byte(msgContext[0]), &args); err != nil {
+		return false
+	}
+	if args.SwapID == "" || args.FromChainID == nil || args.ToChainID == nil {
+		return false
+	}
+	err := signDB.AddSignRequest(&mongodb.MgoSignRequest{
+		Key:         keyID,
+		MsgHash:     msgHash,
+		MsgContext:  msgContext,
+		SignType:    signType,
+		SignPubkey:  signPubkey,
+		SignGroup:   signGroup,
+		RPCAddress:  rpcAddr,
+		FromChainID: args.FromChainID.String(),
+		TxID:        args.SwapID,
+		LogIndex:    args.LogIndex,
+		ToChainID:   args.ToChainID.String(),
+		SwapNonce:   args.GetTxNonce(),
+	})
+	return err == nil
+}
+
+func updateSignRequest(keyID string, rsvs []string, signErr error) {
+	if signErr != nil {
+		_ = signDB.UpdateSignRequestStatus(keyID, mongodb.SignRequestFailed, nil, signErr.Error())
+		return
+	}
+	_ = signDB.UpdateSignRequestStatus(keyID, mongodb.SignRequestSigned, rsvs, "")
+}
+
+// FailSignRequest mark persisted sign request of keyID failed,
+// call it when the sign result of `DoSign` can not make a signed tx,
+// so that the swap is not blocked by the signed request.
+func FailSignRequest(keyID string, err error) {
+	if keyID == "" || err == nil {
+		return
+	}
+	updateSignRequest(keyID, nil, err)
+}
+
+// ResumeSignRequest get sign result of persisted sign request from its initiator
+func ResumeSignRequest(req *mongodb.MgoSignRequest) (rsvs []string, err error) {
+	if req.Status == mongodb.SignRequestSigned {
+		return req.Rsvs, nil
+	}
+	rpcAddr := req.RPCAddress
+	if rpcAddr == "" {
+		rpcAddr = defaultMPCNode.mpcRPCAddress
+	}
+	log.Info("resume mpc sign request", "keyID", req.Key, "rpcAddr", rpcAddr, "fromChainID", req.FromChainID, "txid", req.TxID, "logIndex", req.LogIndex, "nonce", req.SwapNonce)
+	rsvs, _, err = getSignResult(req.Key, rpcAddr)
+	if err == nil && isEC(req.SignType) {
+		err = addUsedRValues(req.SignPubkey, rsvs)
+	}
+	updateSignRequest(req.Key, rsvs, err)
+	if err != nil {
+		return nil, err
+	}
+	return rsvs, nil
+}
@@ -0,0 +1,145 @@
+package mpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+type testSignRequestStore struct {
+	mu       sync.Mutex
+	requests map[string]*mongodb.MgoSignRequest
+	usedRs   map[string]bool
+}
+
+// replace database operations of signing with in memory store
+func useTestSignRequestStore() (store *testSignRequestStore, restore func()) {
+	store = &testSignRequestStore{
+		requests: make(map[string]*mongodb.MgoSignRequest),
+		usedRs:   make(map[string]bool),
+	}
+	old := SetSignDB(&SignDB{
+		AddUsedRValue: func(pubkey, r string) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			if store.usedRs[pubkey+r] {
+				return errRValueIsUsed
+			}
+			store.usedRs[pubkey+r] = true
+			return nil
+		},
+		AddSignRequest: func(req *mongodb.MgoSignRequest) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			req.Status = mongodb.SignRequestPending
+			store.requests[req.Key] = req
+			return nil
+		},
+		UpdateSignRequestStatus: func(keyID, status string, rsvs []string, memo string) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			req, exist := store.requests[keyID]
+			if !exist {
+				return mongodb.ErrItemNotFound
+			}
+			req.Status = status
+			req.Rsvs = rsvs
+			req.Memo = memo
+			return nil
+		},
+	})
+
+	restore = func() { SetSignDB(old) }
+	return store, restore
+}
+
+func (s *testSignRequestStore) getStatus(keyID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req, exist := s.requests[keyID]; exist {
+		return req.Status
+	}
+	return ""
+}
+
+func (s *testSignRequestStore) getKeyIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keyIDs := make([]string, 0, len(s.requests))
+	for keyID := range s.requests {
+		keyIDs = append(keyIDs, keyID)
+	}
+	return keyIDs
+}
+
+func newTestSwapMsgContext(t *testing.T) []string {
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  "test",
+			SwapID:      common.Keccak256Hash([]byte("test swap")).String(),
+			SwapType:    tokens.ERC20SwapType,
+			FromChainID: big.NewInt(1),
+			ToChainID:   big.NewInt(56),
+		},
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []string{string(data)}
+}
+
+func TestSignRequestStatusWithMockMPC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip mock mpc sign test in short mode")
+	}
+	signPubkey, initiator, cleanup := startMockMPC(t)
+	defer cleanup()
+	store, restore := useTestSignRequestStore()
+	defer restore()
+
+	signGroupIndex := 0
+	msgHashes := []string{common.Keccak256Hash([]byte("sign request test")).String()}
+	msgContexts := newTestSwapMsgContext(t)
+
+	signAndCheck := func(agreeResult, wantStatus string) (keyID string) {
+		var err error
+		stopOracle := startTestOracle(t, agreeResult)
+		keyID, _, err = doSignImpl(initiator, signGroupIndex, SignTypeEC256K1, signPubkey, msgHashes, msgContexts)
+		stopOracle()
+		if (err == nil) != (wantStatus == mongodb.SignRequestSigned) {
+			t.Fatalf("%v sign returns unexpected error %v", agreeResult, err)
+		}
+		if keyID != "" {
+			if status := store.getStatus(keyID); status != wantStatus {
+				t.Errorf("%v sign request has status '%v', want '%v'", agreeResult, status, wantStatus)
+			}
+		}
+		return keyID
+	}
+
+	signedKeyID := signAndCheck("AGREE", mongodb.SignRequestSigned)
+	signAndCheck("DISAGREE", mongodb.SignRequestFailed)
+
+	// the signed request fails if its rsvs can not make a signed tx
+	FailSignRequest(signedKeyID, errors.New("wrong signature length"))
+	if status := store.getStatus(signedKeyID); status != mongodb.SignRequestFailed {
+		t.Errorf("sign request failed after signed has status '%v'", status)
+	}
+
+	keyIDs := store.getKeyIDs()
+	if len(keyIDs) != 2 {
+		t.Fatalf("want 2 sign requests, have %v", len(keyIDs))
+	}
+	for _, keyID := range keyIDs {
+		if store.getStatus(keyID) == mongodb.SignRequestPending {
+			t.Errorf("sign request %v is left pending", keyID)
+		}
+	}
+}
@@ -50,12 +50,17 @@ type SignStatus struct {
 
 // HasDisagree has disagree reply
 func (s *SignStatus) HasDisagree() bool {
+	return s.DisagreeCount() > 0
+}
+
+// DisagreeCount count of disagree replies
+func (s *SignStatus) DisagreeCount() (count int) {
 	for _, reply := range s.AllReply {
-		if strings.EqualFold(reply.Status, "DisAgree") {
-			return true
+		if reply != nil && strings.EqualFold(reply.Status, "DisAgree") {
+			count++
 		}
 	}
-	return false
+	return count
 }
 
 // SignInfoData sign info
@@ -87,6 +87,21 @@ func (c *RouterOracleConfig) CheckConfig() (err error) {
 	if c.ServerAPIAddress == "" {
 		return errors.New("oracle must config 'ServerAPIAddress'")
 	}
+	if c.MetricsPort < 0 {
+		return errors.New("oracle 'MetricsPort' must not be negative")
+	}
+	if c.APIPort < 0 {
+		return errors.New("oracle 'APIPort' must not be negative")
+	}
+	if c.PayAnyTokenTimeout < 0 {
+		return errors.New("oracle 'PayAnyTokenTimeout' must not be negative")
+	}
+	if c.AcceptPolicy != nil {
+		err = c.AcceptPolicy.CheckConfig()
+		if err != nil {
+			return err
+		}
+	}
 	if c.NoCheckServerConnection {
 		return nil
 	}
@@ -114,6 +129,9 @@ func (s *RouterServerConfig) CheckConfig() error {
 	if s.APIServer == nil {
 		return errors.New("server must config 'APIServer'")
 	}
+	if ws := s.APIServer.WebSocket; ws != nil && (ws.MaxConnections < 0 || ws.MaxSubscriptionsPerConn < 0) {
+		return errors.New("wrong 'WebSocket' config with negative value")
+	}
 	if s.MongoDB == nil {
 		return errors.New("server must config 'MongoDB'")
 	}
@@ -199,11 +217,41 @@ func (s *RouterServerConfig) CheckConfig() error {
 	if err != nil {
 		return err
 	}
+	if s.LiquidityMonitor != nil && s.LiquidityMonitor.PayAnyTokenTimeout < 0 {
+		return errors.New("wrong 'PayAnyTokenTimeout' in 'LiquidityMonitor'")
+	}
+	if cb := s.CircuitBreaker; cb != nil {
+		if cb.MaxConsecutiveFailedSwaps < 0 || cb.MaxSendTxErrors < 0 || cb.MaxSignDisagrees < 0 ||
+			cb.ErrorWindow < 0 || cb.MaxHeightStallTime < 0 {
+			return errors.New("wrong 'CircuitBreaker' config with negative value")
+		}
+	}
+	if wh := s.Webhook; wh != nil && wh.Enable {
+		if len(wh.URLs) == 0 && len(wh.AlertURLs) == 0 {
+			return errors.New("webhook must config 'URLs' or 'AlertURLs'")
+		}
+		if wh.Secret == "" {
+			return errors.New("webhook must config 'Secret'")
+		}
+		if wh.MaxRetries < 0 || wh.RetryDelay < 0 || wh.Timeout < 0 {
+			return errors.New("wrong 'Webhook' config with negative value")
+		}
+	}
+	for key, quota := range s.OutflowQuotas {
+		if quota == nil ||
+			(quota.HourlyLimit != "" && !isValidTokenValue(quota.HourlyLimit)) ||
+			(quota.DailyLimit != "" && !isValidTokenValue(quota.DailyLimit)) {
+			return fmt.Errorf("wrong outflow quota '%v'", key)
+		}
+	}
 	log.Info("check server config success",
 		"defaultGasLimit", s.DefaultGasLimit,
 		"fixedGasPriceMap", fixedGasPriceMap,
 		"maxGasPriceMap", maxGasPriceMap,
 		"noncePassedConfirmInterval", s.NoncePassedConfirmInterval,
+		"enableReorgWatch", s.EnableReorgWatch,
+		"finalityDepth", s.FinalityDepth,
+		"outflowQuotas", len(s.OutflowQuotas),
 	)
 	return nil
 }
@@ -393,6 +441,12 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 		}
 	}
 
+	for key, feePolicy := range c.FeePolicies {
+		if err = feePolicy.CheckConfig(); err != nil {
+			return fmt.Errorf("wrong fee policy '%v'. %w", key, err)
+		}
+	}
+
 	log.Info("check extra config success",
 		"minReserveFee", c.MinReserveFee,
 		"allowCallByContract", c.AllowCallByContract,
@@ -404,8 +458,106 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 		"enableCheckTxBlockIndexChains", c.EnableCheckTxBlockIndexChains,
 		"initDisableUseFromChainIDInReceiptChains", c.DisableUseFromChainIDInReceiptChains,
 		"baseFeePercent", c.BaseFeePercent,
+		"feePolicies", len(c.FeePolicies),
 		"usePendingBalance", c.UsePendingBalance,
 		"customs", c.Customs,
 	)
 	return nil
 }
+
+// CheckConfig check oracle accept policy config
+func (c *AcceptPolicyConfig) CheckConfig() error {
+	for tokenID, value := range c.DailyCaps {
+		if !isValidTokenValue(value) {
+			return fmt.Errorf("wrong accept policy daily cap of '%v'", tokenID)
+		}
+	}
+	for tokenID, value := range c.ConfirmThresholds {
+		if !isValidTokenValue(value) {
+			return fmt.Errorf("wrong accept policy confirm threshold of '%v'", tokenID)
+		}
+	}
+	for chainID, value := range c.MaxGasPrices {
+		if bi, err := common.GetBigIntFromStr(value); err != nil || bi.Sign() <= 0 {
+			return fmt.Errorf("wrong accept policy max gas price of '%v'", chainID)
+		}
+	}
+	for _, window := range c.AllowedTimeWindows {
+		if _, _, err := ParseTimeWindow(window); err != nil {
+			return fmt.Errorf("wrong accept policy time window '%v': %w", window, err)
+		}
+	}
+	log.Info("check accept policy config success", "rules", c.Rules)
+	return nil
+}
+
+// ParseTimeWindow parse time window of format "HH:MM-HH:MM" into minutes of day
+func ParseTimeWindow(window string) (start, end int, err error) {
+	var startHour, startMinute, endHour, endMinute int
+	_, err = fmt.Sscanf(window, "%d:%d-%d:%d", &startHour, &startMinute, &endHour, &endMinute)
+	if err != nil {
+		return 0, 0, err
+	}
+	if startHour < 0 || startHour > 24 || endHour < 0 || endHour > 24 ||
+		startMinute < 0 || startMinute >= 60 || endMinute < 0 || endMinute >= 60 {
+		return 0, 0, errors.New("hour or minute out of range")
+	}
+	start = startHour*60 + startMinute
+	end = endHour*60 + endMinute
+	if start > 24*60 || end > 24*60 || start == end {
+		return 0, 0, errors.New("wrong time window range")
+	}
+	return start, end, nil
+}
+
+func isValidTokenValue(value string) bool {
+	r, ok := new(big.Rat).SetString(value)
+	return ok && r.Sign() >= 0
+}
+
+// CheckConfig check fee policy config
+func (c *FeePolicyConfig) CheckConfig() error {
+	if c == nil {
+		return errors.New("empty fee policy")
+	}
+	switch strings.ToLower(c.Type) {
+	case "", "default":
+	case "tiered":
+		if len(c.Tiers) == 0 {
+			return errors.New("tiered fee policy must config 'Tiers'")
+		}
+		for _, tier := range c.Tiers {
+			if tier == nil || !isValidTokenValue(tier.MinValue) {
+				return errors.New("tiered fee policy with wrong 'MinValue'")
+			}
+			if tier.SwapFeeRatePerMillion >= 1000000 {
+				return errors.New("tiered fee policy with too large 'SwapFeeRatePerMillion'")
+			}
+		}
+	case "flat":
+		if !isValidTokenValue(c.FlatFee) {
+			return errors.New("flat fee policy with wrong 'FlatFee'")
+		}
+	case "gasscaled":
+		if c.GasLimit == 0 {
+			return errors.New("gas scaled fee policy must config 'GasLimit'")
+		}
+		if gasPrice, ok := new(big.Int).SetString(c.GasPrice, 10); !ok || gasPrice.Sign() < 0 {
+			return errors.New("gas scaled fee policy with wrong 'GasPrice'")
+		}
+		if c.GasPriceTolerance == 0 || c.GasPriceTolerance > 100 {
+			return errors.New("gas scaled fee policy with wrong 'GasPriceTolerance'")
+		}
+		if !isValidTokenValue(c.NativePrice) {
+			return errors.New("gas scaled fee policy with wrong 'NativePrice'")
+		}
+	default:
+		// custom fee policy, checked by its registered factory
+	}
+	for _, window := range c.PromoWindows {
+		if window == nil || window.Start >= window.End {
+			return errors.New("fee policy with wrong 'PromoWindows'")
+		}
+	}
+	return nil
+}
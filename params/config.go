@@ -72,6 +72,7 @@ type RouterServerConfig struct {
 	// extras
 	EnableReplaceSwap          bool
 	EnablePassBigValueSwap     bool
+	EnableReorgWatch           bool
 	ReplacePlusGasPricePercent uint64            `toml:",omitempty" json:",omitempty"`
 	WaitTimeToReplace          int64             `toml:",omitempty" json:",omitempty"` // seconds
 	MaxReplaceCount            int               `toml:",omitempty" json:",omitempty"`
@@ -87,14 +88,49 @@ type RouterServerConfig struct {
 	RetrySendTxLoopCount       map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 	SendTxLoopCount            map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
 	SendTxLoopInterval         map[string]int    `toml:",omitempty" json:",omitempty"` // key is chain ID
+	FinalityDepth              map[string]uint64 `toml:",omitempty" json:",omitempty"` // key is chain ID
 
 	DynamicFeeTx map[string]*DynamicFeeTxConfig `toml:",omitempty" json:",omitempty"` // key is chain ID
+
+	SwapScanner      *SwapScannerConfig      `toml:",omitempty" json:",omitempty"`
+	LiquidityMonitor *LiquidityMonitorConfig `toml:",omitempty" json:",omitempty"`
+
+	OutflowQuotas map[string]*OutflowQuotaConfig `toml:",omitempty" json:",omitempty"` // key is tokenID,toChainID or tokenID
+
+	CircuitBreaker *CircuitBreakerConfig `toml:",omitempty" json:",omitempty"`
+
+	Webhook *WebhookConfig `toml:",omitempty" json:",omitempty"`
 }
 
 // RouterOracleConfig only for oracle
 type RouterOracleConfig struct {
 	ServerAPIAddress        string
 	NoCheckServerConnection bool
+	MetricsPort             int `toml:",omitempty" json:",omitempty"`
+	APIPort                 int `toml:",omitempty" json:",omitempty"` // read-only api of accept decisions
+
+	PayAnyTokenTimeout int64 `toml:",omitempty" json:",omitempty"` // seconds, 0 means never agree paying anyToken
+
+	AcceptPolicy *AcceptPolicyConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// AcceptPolicyConfig oracle accept policy config, rules are evaluated in order of 'Rules'
+type AcceptPolicyConfig struct {
+	Rules              []string            `toml:",omitempty" json:",omitempty"` // rule chain, empty means no policy
+	DailyCaps          map[string]string   `toml:",omitempty" json:",omitempty"` // key is tokenID, value is token amount
+	ConfirmThresholds  map[string]string   `toml:",omitempty" json:",omitempty"` // key is tokenID, value is token amount
+	ConfirmGateways    map[string][]string `toml:",omitempty" json:",omitempty"` // key is source chainID
+	MaxGasPrices       map[string]string   `toml:",omitempty" json:",omitempty"` // key is dest chainID, value in wei
+	AllowedTimeWindows []string            `toml:",omitempty" json:",omitempty"` // UTC "HH:MM-HH:MM"
+}
+
+// GetAcceptPolicyConfig get oracle accept policy config (return nil if no rules)
+func GetAcceptPolicyConfig() *AcceptPolicyConfig {
+	if oracleCfg := GetRouterOracleConfig(); oracleCfg != nil &&
+		oracleCfg.AcceptPolicy != nil && len(oracleCfg.AcceptPolicy.Rules) > 0 {
+		return oracleCfg.AcceptPolicy
+	}
+	return nil
 }
 
 // RouterConfig config
@@ -144,10 +180,47 @@ type ExtraConfig struct {
 	DontCheckReceivedTokenIDs            []string `toml:",omitempty" json:",omitempty"`
 
 	RPCClientTimeout map[string]int `toml:",omitempty" json:",omitempty"` // key is chainID
+
+	// key is 'tokenID,toChainID' or 'tokenID' (for all dest chains)
+	FeePolicies map[string]*FeePolicyConfig `toml:",omitempty" json:",omitempty"`
+
 	// chainID,customKey => customValue
 	Customs map[string]map[string]string `toml:",omitempty" json:",omitempty"`
 }
 
+// FeePolicyConfig swap fee policy config
+// token values are decimal strings in token unit, eg. "1.5"
+type FeePolicyConfig struct {
+	Type string // default, tiered, flat, gasscaled
+
+	// tiered fee rates by swap value
+	Tiers []*FeeTierConfig `toml:",omitempty" json:",omitempty"`
+
+	// flat fee
+	FlatFee string `toml:",omitempty" json:",omitempty"`
+
+	// gas cost of dest swap tx scaled by dest gas price (added to the default fee)
+	GasLimit          uint64 `toml:",omitempty" json:",omitempty"`
+	GasPrice          string `toml:",omitempty" json:",omitempty"` // reference gas price (wei) if dest gas price is unknown
+	GasPriceTolerance uint64 `toml:",omitempty" json:",omitempty"` // percent, oracle tolerance of server's dest gas price
+	NativePrice       string `toml:",omitempty" json:",omitempty"` // price of dest native coin in this token
+
+	// promotional zero fee windows
+	PromoWindows []*PromoWindowConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// FeeTierConfig fee tier config
+type FeeTierConfig struct {
+	MinValue              string
+	SwapFeeRatePerMillion uint64
+}
+
+// PromoWindowConfig promotional zero fee window (unix seconds, [Start, End))
+type PromoWindowConfig struct {
+	Start int64
+	End   int64
+}
+
 // OnchainConfig struct
 type OnchainConfig struct {
 	Contract    string
@@ -191,6 +264,14 @@ type APIServerConfig struct {
 	Port             int
 	AllowedOrigins   []string
 	MaxRequestsLimit int
+	WebSocket        *WebSocketConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// WebSocketConfig websocket subscription config
+type WebSocketConfig struct {
+	Enable                  bool
+	MaxConnections          int `toml:",omitempty" json:",omitempty"`
+	MaxSubscriptionsPerConn int `toml:",omitempty" json:",omitempty"`
 }
 
 // MongoDBConfig mongodb config
@@ -202,6 +283,135 @@ type MongoDBConfig struct {
 	Password string `json:"-"`
 }
 
+// SwapScannerConfig swap scanner config
+type SwapScannerConfig struct {
+	Enable            bool
+	Chains            []string `toml:",omitempty" json:",omitempty"` // empty means all chains
+	MaxBlocksPerQuery uint64   `toml:",omitempty" json:",omitempty"`
+	QueryInterval     int64    `toml:",omitempty" json:",omitempty"` // milliseconds
+}
+
+// IsChainEnabled is swap scanner enabled on chain
+func (c *SwapScannerConfig) IsChainEnabled(chainID string) bool {
+	if c == nil || !c.Enable {
+		return false
+	}
+	if len(c.Chains) == 0 {
+		return true
+	}
+	for _, id := range c.Chains {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// LiquidityMonitorConfig liquidity monitor config
+type LiquidityMonitorConfig struct {
+	Enable             bool
+	Chains             []string `toml:",omitempty" json:",omitempty"` // dest chainIDs, empty means all chains
+	PayAnyTokenTimeout int64    `toml:",omitempty" json:",omitempty"` // seconds, 0 means never pay anyToken
+}
+
+// IsChainEnabled is liquidity monitor enabled on dest chain
+func (c *LiquidityMonitorConfig) IsChainEnabled(chainID string) bool {
+	if c == nil || !c.Enable {
+		return false
+	}
+	if len(c.Chains) == 0 {
+		return true
+	}
+	for _, id := range c.Chains {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLiquidityMonitorConfig get liquidity monitor config
+func GetLiquidityMonitorConfig() *LiquidityMonitorConfig {
+	if serverCfg := GetRouterServerConfig(); serverCfg != nil {
+		return serverCfg.LiquidityMonitor
+	}
+	return nil
+}
+
+// OutflowQuotaConfig rolling window outflow quota config (in token units, empty means no limit)
+type OutflowQuotaConfig struct {
+	HourlyLimit string `toml:",omitempty" json:",omitempty"`
+	DailyLimit  string `toml:",omitempty" json:",omitempty"`
+}
+
+// GetOutflowQuotaConfig get outflow quota config of tokenID and dest chain
+func GetOutflowQuotaConfig(tokenID, toChainID string) *OutflowQuotaConfig {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil || len(serverCfg.OutflowQuotas) == 0 {
+		return nil
+	}
+	if cfg, exist := serverCfg.OutflowQuotas[tokenID+","+toChainID]; exist {
+		return cfg
+	}
+	if cfg, exist := serverCfg.OutflowQuotas[tokenID]; exist {
+		return cfg
+	}
+	return nil
+}
+
+// CircuitBreakerConfig circuit breaker config (zero threshold disables the rule)
+type CircuitBreakerConfig struct {
+	Enable                    bool
+	MaxConsecutiveFailedSwaps int   `toml:",omitempty" json:",omitempty"`
+	MaxSendTxErrors           int   `toml:",omitempty" json:",omitempty"`
+	MaxSignDisagrees          int   `toml:",omitempty" json:",omitempty"`
+	ErrorWindow               int64 `toml:",omitempty" json:",omitempty"` // seconds
+	MaxHeightStallTime        int64 `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// GetCircuitBreakerConfig get circuit breaker config (return nil if disabled)
+func GetCircuitBreakerConfig() *CircuitBreakerConfig {
+	if serverCfg := GetRouterServerConfig(); serverCfg != nil &&
+		serverCfg.CircuitBreaker != nil && serverCfg.CircuitBreaker.Enable {
+		return serverCfg.CircuitBreaker
+	}
+	return nil
+}
+
+// WebhookConfig swap lifecycle events webhook config
+type WebhookConfig struct {
+	Enable     bool
+	URLs       []string `toml:",omitempty" json:",omitempty"`
+	AlertURLs  []string `toml:",omitempty" json:",omitempty"` // also receive failed and held events
+	Secret     string   `toml:",omitempty" json:"-"`          // hmac-sha256 key of signing payload
+	Events     []string `toml:",omitempty" json:",omitempty"` // subscribed events of 'URLs', empty means all
+	MaxRetries int      `toml:",omitempty" json:",omitempty"`
+	RetryDelay int64    `toml:",omitempty" json:",omitempty"` // seconds, doubled after each retry
+	Timeout    int      `toml:",omitempty" json:",omitempty"` // seconds
+}
+
+// IsEventSubscribed is event subscribed by 'URLs'
+func (c *WebhookConfig) IsEventSubscribed(event string) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWebhookConfig get webhook config (return nil if disabled)
+func GetWebhookConfig() *WebhookConfig {
+	if serverCfg := GetRouterServerConfig(); serverCfg != nil &&
+		serverCfg.Webhook != nil && serverCfg.Webhook.Enable {
+		return serverCfg.Webhook
+	}
+	return nil
+}
+
 // DynamicFeeTxConfig dynamic fee tx config
 type DynamicFeeTxConfig struct {
 	PlusGasTipCapPercent uint64
@@ -307,6 +517,18 @@ func GetNoncePassedConfirmInterval(chainID string) int64 {
 	return 0
 }
 
+// GetFinalityDepth get finality depth (in blocks) of reorg watching
+func GetFinalityDepth(chainID string) uint64 {
+	serverCfg := GetRouterServerConfig()
+	if serverCfg == nil {
+		return 0
+	}
+	if depth, exist := serverCfg.FinalityDepth[chainID]; exist {
+		return depth
+	}
+	return 0
+}
+
 // GetCalcGasPriceMethod get calc gas price method eg. median (default), first, max, etc.
 func GetCalcGasPriceMethod(chainID string) string {
 	serverCfg := GetRouterServerConfig()
@@ -357,6 +579,21 @@ func GetBaseFeePercent(chainID string) int64 {
 	return 0
 }
 
+// GetFeePolicyConfig get fee policy config of tokenID and dest chain
+func GetFeePolicyConfig(tokenID, toChainID string) *FeePolicyConfig {
+	extraCfg := GetExtraConfig()
+	if extraCfg == nil || len(extraCfg.FeePolicies) == 0 {
+		return nil
+	}
+	if cfg, exist := extraCfg.FeePolicies[tokenID+","+toChainID]; exist {
+		return cfg
+	}
+	if cfg, exist := extraCfg.FeePolicies[tokenID]; exist {
+		return cfg
+	}
+	return nil
+}
+
 // GetRPCClientTimeout get rpc client timeout
 func GetRPCClientTimeout(chainID string) int {
 	extraCfg := GetExtraConfig()
@@ -590,6 +827,14 @@ func GetRouterOracleConfig() *RouterOracleConfig {
 	return routerConfig.Oracle
 }
 
+// GetOraclePayAnyTokenTimeout get the liquidity timeout after which oracle agrees paying anyToken
+func GetOraclePayAnyTokenTimeout() int64 {
+	if oracleCfg := GetRouterOracleConfig(); oracleCfg != nil {
+		return oracleCfg.PayAnyTokenTimeout
+	}
+	return 0
+}
+
 // GetMPCConfig get mpc config
 func GetMPCConfig() *MPCConfig {
 	return routerConfig.MPC
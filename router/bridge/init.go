@@ -4,6 +4,7 @@ package bridge
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
@@ -277,7 +278,7 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 			return
 		}
 	}
-	if common.HexToAddress(tokenAddr) == (common.Address{}) {
+	if isEmptyTokenAddress(tokenAddr) {
 		log.Debugf("[%5v] '%v' token address is empty", chainID, tokenID)
 		return
 	}
@@ -292,7 +293,7 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 		log.Debug("token config not found", "tokenID", tokenID, "chainID", chainID, "tokenAddr", tokenAddr)
 		return
 	}
-	if common.HexToAddress(tokenAddr) != common.HexToAddress(tokenCfg.ContractAddress) {
+	if !isSameTokenAddress(tokenAddr, tokenCfg.ContractAddress) {
 		logErrFunc("verify token address mismach", "tokenID", tokenID, "chainID", chainID, "inconfig", tokenCfg.ContractAddress, "inmultichain", tokenAddr)
 		if isReload {
 			return
@@ -337,3 +338,18 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 		}
 	}
 }
+
+// isEmptyTokenAddress non-hex token address (eg. 'native' of utxo chains) is not empty
+func isEmptyTokenAddress(tokenAddr string) bool {
+	if common.IsHexAddress(tokenAddr) {
+		return common.HexToAddress(tokenAddr) == (common.Address{})
+	}
+	return tokenAddr == ""
+}
+
+func isSameTokenAddress(addr1, addr2 string) bool {
+	if common.IsHexAddress(addr1) && common.IsHexAddress(addr2) {
+		return common.HexToAddress(addr1) == common.HexToAddress(addr2)
+	}
+	return strings.EqualFold(addr1, addr2)
+}
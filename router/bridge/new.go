@@ -4,17 +4,38 @@ import (
 	"math/big"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+
+	// register bridge implementations
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/btc"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/cosmos"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/solana"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/substrate"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/tron"
 )
 
-// NewCrossChainBridge new bridge
+// NewCrossChainBridge new bridge of the registered chain family
+// selected by 'BlockChain' in chain config or by chain id range
 func NewCrossChainBridge(chainID *big.Int) tokens.IBridge {
-	switch {
-	case chainID.Sign() <= 0:
+	if chainID.Sign() <= 0 {
 		log.Fatal("wrong chainID", "chainID", chainID)
-	default:
-		return eth.NewCrossChainBridge()
 	}
-	return nil
+	blockChain := ""
+	chainCfg, err := router.GetChainConfig(chainID)
+	if err != nil || chainCfg == nil {
+		log.Warn("get chain config failed, select bridge by chain id", "chainID", chainID, "err", err)
+	} else {
+		blockChain = chainCfg.BlockChain
+	}
+	family := tokens.GetBridgeFamily(chainID, blockChain)
+	bridge, err := tokens.NewBridgeOfFamily(family)
+	if err != nil {
+		logErrFunc := log.GetLogFuncOr(router.IsReloading, log.Error, log.Fatal)
+		logErrFunc("new cross chain bridge failed", "chainID", chainID, "blockChain", blockChain, "err", err)
+		return nil
+	}
+	log.Info("new cross chain bridge", "chainID", chainID, "blockChain", blockChain, "family", family)
+	return bridge
 }
@@ -133,6 +133,9 @@ func ReloadRouterConfig() (success bool) {
 			if bridge == nil {
 				log.Info("[reload] add new bridge", "chainID", chainID)
 				bridge = NewCrossChainBridge(chainID)
+				if bridge == nil {
+					return
+				}
 				isNewBridge = true
 			}
 
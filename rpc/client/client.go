@@ -144,7 +144,9 @@ func addRawPostBody(req *http.Request, body string) (err error) {
 	if body == "" {
 		return nil
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 	req.ContentLength = int64(len(body))
 	req.GetBody = func() (io.ReadCloser, error) {
 		return ioutil.NopCloser(strings.NewReader(body)), nil
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 )
 
 // RPCGet rpc get
@@ -20,7 +22,8 @@ func RPCGetWithTimeout(result interface{}, url string, timeout int) error {
 }
 
 // RPCGetRequest rpc get request
-func RPCGetRequest(result interface{}, url string, params, headers map[string]string, timeout int) error {
+func RPCGetRequest(result interface{}, url string, params, headers map[string]string, timeout int) (err error) {
+	defer func(start time.Time) { metrics.ObserveRPCRequest(url, start, err) }(time.Now())
 	resp, err := HTTPGet(url, params, headers, timeout)
 	if err != nil {
 		return fmt.Errorf("GET request error: %w (url: %v, params: %v)", err, url, params)
@@ -58,7 +61,8 @@ func RPCRawGetWithTimeout(url string, timeout int) (string, error) {
 }
 
 // RPCRawGetRequest rpc raw get request
-func RPCRawGetRequest(url string, params, headers map[string]string, timeout int) (string, error) {
+func RPCRawGetRequest(url string, params, headers map[string]string, timeout int) (res string, err error) {
+	defer func(start time.Time) { metrics.ObserveRPCRequest(url, start, err) }(time.Now())
 	resp, err := HTTPGet(url, params, headers, timeout)
 	if err != nil {
 		return "", fmt.Errorf("GET request error: %w (url: %v, params: %v)", err, url, params)
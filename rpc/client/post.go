@@ -7,8 +7,10 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 )
 
 const (
@@ -109,7 +111,8 @@ func RPCPostRequest(url string, req *Request, result interface{}) error {
 }
 
 // RPCPostRequestWithContext rpc post request with context
-func RPCPostRequestWithContext(ctx context.Context, url string, req *Request, result interface{}) error {
+func RPCPostRequestWithContext(ctx context.Context, url string, req *Request, result interface{}) (err error) {
+	defer func(start time.Time) { metrics.ObserveRPCRequest(url, start, err) }(time.Now())
 	reqBody := &RequestBody{
 		Version: "2.0",
 		Method:  req.Method,
@@ -165,7 +168,8 @@ func RPCRawPost(url, body string) (string, error) {
 }
 
 // RPCRawPostWithTimeout rpc raw post with timeout
-func RPCRawPostWithTimeout(url, reqBody string, timeout int) (string, error) {
+func RPCRawPostWithTimeout(url, reqBody string, timeout int) (res string, err error) {
+	defer func(start time.Time) { metrics.ObserveRPCRequest(url, start, err) }(time.Now())
 	resp, err := HTTPRawPost(url, reqBody, nil, nil, timeout)
 	if err != nil {
 		return "", err
@@ -164,6 +164,38 @@ func GetRouterSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetSwapFeeTotalsHandler handler
+func GetSwapFeeTotalsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tokenID := vars["tokenid"]
+	vals := r.URL.Query()
+	var startTime, endTime uint64
+	var err error
+	if startStr, exist := vals["start"]; exist {
+		startTime, err = common.GetUint64FromStr(startStr[0])
+	}
+	if endStr, exist := vals["end"]; exist && err == nil {
+		endTime, err = common.GetUint64FromStr(endStr[0])
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.GetSwapFeeTotals(tokenID, vals.Get("fromchainid"), vals.Get("tochainid"), int64(startTime), int64(endTime))
+	writeResponse(w, res, err)
+}
+
+// GetSwapQuoteHandler handler
+func GetSwapQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tokenID := vars["tokenid"]
+	fromChainID := vars["fromchainid"]
+	toChainID := vars["tochainid"]
+	vals := r.URL.Query()
+	res, err := swapapi.GetSwapQuote(tokenID, fromChainID, toChainID, vals.Get("amount"), vals.Get("from"), vals.Get("bind"), vals.Get("txto"))
+	writeResponse(w, res, err)
+}
+
 // GetAllChainIDsHandler handler
 func GetAllChainIDsHandler(w http.ResponseWriter, r *http.Request) {
 	allChainIDs := router.AllChainIDs
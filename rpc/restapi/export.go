@@ -0,0 +1,213 @@
+package restapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	exportFlushInterval = 100 // rows
+)
+
+var exportCSVHeader = []string{
+	"fromChainID", "toChainID", "txid", "logIndex", "swaptype", "tokenID",
+	"from", "to", "bind", "value", "swaptx", "swapheight", "swapvalue", "swapnonce",
+	"status", "statusmsg", "inittime", "timestamp", "memo",
+}
+
+func getSwapHistoryFilter(vals url.Values) (filter *mongodb.SwapHistoryFilter, err error) {
+	filter = &mongodb.SwapHistoryFilter{
+		FromChainID: vals.Get("fromchainid"),
+		ToChainID:   vals.Get("tochainid"),
+		TokenID:     vals.Get("tokenid"),
+		Address:     vals.Get("address"),
+		Status:      vals.Get("status"),
+		MinValue:    vals.Get("minvalue"),
+		MaxValue:    vals.Get("maxvalue"),
+	}
+	if str := vals.Get("swaptype"); str != "" {
+		swapType, errp := common.GetUint64FromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		swapType32 := uint32(swapType)
+		filter.SwapType = &swapType32
+	}
+	if str := vals.Get("start"); str != "" {
+		startTime, errp := common.GetUint64FromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		filter.StartTime = int64(startTime)
+	}
+	if str := vals.Get("end"); str != "" {
+		endTime, errp := common.GetUint64FromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		filter.EndTime = int64(endTime)
+	}
+	return filter, nil
+}
+
+// SearchRouterSwapHistoryHandler handler
+func SearchRouterSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	filter, err := getSwapHistoryFilter(vals)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	var limit int
+	if str := vals.Get("limit"); str != "" {
+		limit, err = common.GetIntFromStr(str)
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+	}
+	res, err := swapapi.GetRouterSwapHistoryWithCursor(filter, vals.Get("cursor"), limit)
+	writeResponse(w, res, err)
+}
+
+// ExportRouterSwapHistoryHandler handler (stream all matched swaps in csv or ndjson format)
+func ExportRouterSwapHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	filter, err := getSwapHistoryFilter(vals)
+	if err == nil {
+		err = swapapi.CheckExportTimeRange(filter.StartTime, filter.EndTime)
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	format := vals.Get("format")
+	var contentType string
+	switch format {
+	case exportFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case "", exportFormatNDJSON:
+		format = exportFormatNDJSON
+		contentType = "application/x-ndjson"
+	default:
+		writeResponse(w, nil, fmt.Errorf("unknown export format '%v'", format))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=swaps.%v", format))
+	out, finish := startStreamResponse(w)
+
+	var (
+		count     int
+		csvWriter *csv.Writer
+		encoder   *json.Encoder
+	)
+	if format == exportFormatCSV {
+		csvWriter = csv.NewWriter(out)
+		_ = csvWriter.Write(exportCSVHeader)
+	} else {
+		encoder = json.NewEncoder(out)
+	}
+	err = swapapi.ExportRouterSwapHistory(r.Context(), filter, func(swap *swapapi.SwapInfo) error {
+		var errw error
+		if csvWriter != nil {
+			errw = csvWriter.Write(toExportCSVRecord(swap))
+		} else {
+			errw = encoder.Encode(swap)
+		}
+		if errw != nil {
+			return errw
+		}
+		count++
+		if count%exportFlushInterval == 0 {
+			if csvWriter != nil {
+				csvWriter.Flush()
+			}
+			return out.Flush()
+		}
+		return nil
+	})
+	if csvWriter != nil {
+		csvWriter.Flush()
+	}
+	if err != nil {
+		log.Warn("export swap history failed", "count", count, "err", err)
+	} else {
+		log.Info("export swap history success", "format", format, "count", count)
+	}
+	finish(err == nil)
+}
+
+func toExportCSVRecord(swap *swapapi.SwapInfo) []string {
+	return []string{
+		swap.FromChainID,
+		swap.ToChainID,
+		swap.TxID,
+		strconv.Itoa(swap.LogIndex),
+		strconv.FormatUint(uint64(swap.SwapType), 10),
+		swap.SwapInfo.GetTokenID(),
+		swap.From,
+		swap.To,
+		swap.Bind,
+		swap.Value,
+		swap.SwapTx,
+		strconv.FormatUint(swap.SwapHeight, 10),
+		swap.SwapValue,
+		strconv.FormatUint(swap.SwapNonce, 10),
+		strconv.FormatUint(uint64(swap.Status), 10),
+		swap.StatusMsg,
+		strconv.FormatInt(swap.InitTime, 10),
+		strconv.FormatInt(swap.Timestamp, 10),
+		swap.Memo,
+	}
+}
+
+// streamWriter writer which can be flushed to client
+type streamWriter interface {
+	io.Writer
+	Flush() error
+}
+
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	return fw.w.Write(p)
+}
+
+func (fw *flushWriter) Flush() error {
+	if flusher, ok := fw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// startStreamResponse write header and return a writer to stream the response body.
+// finish must be called to end the response, an incomplete response is aborted
+// (without terminating the chunked body) so that clients can detect the truncation.
+func startStreamResponse(w http.ResponseWriter) (out streamWriter, finish func(complete bool)) {
+	w.WriteHeader(http.StatusOK)
+	out = &flushWriter{w: w}
+	finish = func(complete bool) {
+		if !complete {
+			panic(http.ErrAbortHandler)
+		}
+		_ = out.Flush()
+	}
+	return out, finish
+}
@@ -0,0 +1,182 @@
+package restapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/worker"
+)
+
+var acceptDecisionCSVHeader = []string{
+	"timestamp", "keyID", "decision", "rule", "fromChainID", "toChainID", "txid", "logIndex",
+	"tokenID", "value", "nonce", "msgHash", "error", "acceptError", "initiator", "enode",
+}
+
+func getAcceptDecisionFilter(vals url.Values) (filter *worker.AcceptDecisionFilter, err error) {
+	filter = &worker.AcceptDecisionFilter{
+		KeyID:       vals.Get("keyid"),
+		TxID:        vals.Get("txid"),
+		FromChainID: vals.Get("fromchainid"),
+		Decision:    vals.Get("decision"),
+	}
+	if str := vals.Get("logindex"); str != "" {
+		logIndex, errp := common.GetIntFromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		filter.LogIndex = &logIndex
+	}
+	if str := vals.Get("start"); str != "" {
+		startTime, errp := common.GetUint64FromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		filter.StartTime = int64(startTime)
+	}
+	if str := vals.Get("end"); str != "" {
+		endTime, errp := common.GetUint64FromStr(str)
+		if errp != nil {
+			return nil, errp
+		}
+		filter.EndTime = int64(endTime)
+	}
+	return filter, nil
+}
+
+// SearchAcceptDecisionsHandler handler
+func SearchAcceptDecisionsHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	filter, err := getAcceptDecisionFilter(vals)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	var limit int
+	if str := vals.Get("limit"); str != "" {
+		limit, err = common.GetIntFromStr(str)
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+	}
+	res, err := worker.FindAcceptDecisions(filter, vals.Get("cursor"), limit)
+	writeResponse(w, res, err)
+}
+
+// ExportAcceptDecisionsHandler handler (stream all matched decisions in csv or ndjson format)
+func ExportAcceptDecisionsHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	filter, err := getAcceptDecisionFilter(vals)
+	if err == nil {
+		err = swapapi.CheckExportTimeRange(filter.StartTime, filter.EndTime)
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	format := vals.Get("format")
+	var contentType string
+	switch format {
+	case exportFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case "", exportFormatNDJSON:
+		format = exportFormatNDJSON
+		contentType = "application/x-ndjson"
+	default:
+		writeResponse(w, nil, fmt.Errorf("unknown export format '%v'", format))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=decisions.%v", format))
+	out, finish := startStreamResponse(w)
+
+	var (
+		count     int
+		csvWriter *csv.Writer
+		encoder   *json.Encoder
+	)
+	if format == exportFormatCSV {
+		csvWriter = csv.NewWriter(out)
+		_ = csvWriter.Write(acceptDecisionCSVHeader)
+	} else {
+		encoder = json.NewEncoder(out)
+	}
+	err = worker.ExportAcceptDecisions(r.Context(), filter, func(d *worker.AcceptDecision) error {
+		var errw error
+		if csvWriter != nil {
+			errw = csvWriter.Write(toAcceptDecisionCSVRecord(d))
+		} else {
+			errw = encoder.Encode(d)
+		}
+		if errw != nil {
+			return errw
+		}
+		count++
+		if count%exportFlushInterval == 0 {
+			if csvWriter != nil {
+				csvWriter.Flush()
+			}
+			return out.Flush()
+		}
+		return nil
+	})
+	if csvWriter != nil {
+		csvWriter.Flush()
+	}
+	if err != nil {
+		log.Warn("export accept decisions failed", "count", count, "err", err)
+	} else {
+		log.Info("export accept decisions success", "format", format, "count", count)
+	}
+	finish(err == nil)
+}
+
+func toAcceptDecisionCSVRecord(d *worker.AcceptDecision) []string {
+	var fromChainID, toChainID, txid, logIndex, tokenID, value, nonce string
+	if args := d.Args; args != nil {
+		if args.FromChainID != nil {
+			fromChainID = args.FromChainID.String()
+		}
+		if args.ToChainID != nil {
+			toChainID = args.ToChainID.String()
+		}
+		txid = args.SwapID
+		logIndex = strconv.Itoa(args.LogIndex)
+		if args.ERC20SwapInfo != nil {
+			tokenID = args.ERC20SwapInfo.TokenID
+		}
+		if args.OriginValue != nil {
+			value = args.OriginValue.String()
+		}
+		if args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.Nonce != nil {
+			nonce = strconv.FormatUint(*args.Extra.EthExtra.Nonce, 10)
+		}
+	}
+	return []string{
+		strconv.FormatInt(d.Timestamp, 10),
+		d.KeyID,
+		d.Decision,
+		d.Rule,
+		fromChainID,
+		toChainID,
+		txid,
+		logIndex,
+		tokenID,
+		value,
+		nonce,
+		strings.Join(d.MsgHash, ";"),
+		d.Error,
+		d.AcceptError,
+		d.Initiator,
+		d.Enode,
+	}
+}
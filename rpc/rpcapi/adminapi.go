@@ -21,11 +21,15 @@ const (
 	passbigvalueCmd = "passbigvalue"
 	reswapCmd       = "reswap"
 	replaceswapCmd  = "replaceswap"
+	reverifyCmd     = "reverify"
+	makefailCmd     = "makefail"
+	forcestableCmd  = "forcestable"
+	releasesignCmd  = "releasesign"
 
 	successReuslt = "Success"
 )
 
-// AdminCall admin call
+// AdminCall admin call (every verified call is recorded in the admin audit logs)
 func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err error) {
 	if !params.HasRouterAdmin() {
 		return fmt.Errorf("no admin is configed")
@@ -39,11 +43,14 @@ func (s *RouterSwapAPI) AdminCall(r *http.Request, rawTx, result *string) (err e
 		return err
 	}
 	senderAddress := sender.String()
+	defer func() {
+		_ = mongodb.AddAdminAudit(senderAddress, args.Method, args.Params, *result, err)
+	}()
 	if !params.IsRouterAdmin(senderAddress) {
 		switch args.Method {
-		case maintainCmd, reswapCmd:
+		case maintainCmd, reswapCmd, makefailCmd, forcestableCmd, releasesignCmd:
 			return fmt.Errorf("sender %v is not admin", senderAddress)
-		case passbigvalueCmd, replaceswapCmd:
+		case passbigvalueCmd, replaceswapCmd, reverifyCmd:
 			if !params.IsRouterAssistant(senderAddress) {
 				return fmt.Errorf("sender %v is not assistant", senderAddress)
 			}
@@ -65,6 +72,14 @@ func doRouterAdminCall(args *admin.CallArgs, result *string) error {
 		return routerReswap(args, result)
 	case replaceswapCmd:
 		return routerReplaceSwap(args, result)
+	case reverifyCmd:
+		return routerReverify(args, result)
+	case makefailCmd:
+		return routerMakeFail(args, result)
+	case forcestableCmd:
+		return routerForceStable(args, result)
+	case releasesignCmd:
+		return routerReleaseSign(args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -110,6 +125,9 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 			router.AddPausedChainIDs(chainIDs)
 		} else {
 			router.RemovePausedChainIDs(chainIDs)
+			if err = worker.ResetCircuitBreakers(chainIDs); err != nil {
+				log.Warn("reset circuit breakers failed", "chainIDs", chainIDs, "err", err)
+			}
 		}
 		log.Infof("after action %v, the paused chainIDs are %v", action, router.GetPausedChainIDs())
 	default:
@@ -195,3 +213,83 @@ func routerReplaceSwap(args *admin.CallArgs, result *string) (err error) {
 	*result = successReuslt
 	return nil
 }
+
+func routerReverify(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	verifyArgs := &tokens.VerifyArgs{
+		SwapType:      tokens.GetRouterSwapType(),
+		LogIndex:      logIndex,
+		AllowUnstable: false,
+	}
+	swapInfo, err := bridge.VerifyTransaction(txid, verifyArgs)
+	if err != nil {
+		return err
+	}
+	mgoSwapInfo := mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
+	err = mongodb.RouterAdminReverify(chainID, txid, logIndex, &mgoSwapInfo)
+	if err != nil {
+		return err
+	}
+	worker.DeleteCachedVerifyingSwap(mongodb.GetRouterSwapKey(chainID, txid, logIndex))
+	*result = successReuslt
+	return nil
+}
+
+func routerMakeFail(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	if len(args.Params) < 4 || args.Params[3] == "" {
+		return fmt.Errorf("must specify the reason of make fail")
+	}
+	reason := args.Params[3]
+	err = worker.CheckSwapNotInProcess(chainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	err = mongodb.RouterAdminMakeFail(chainID, txid, logIndex, reason)
+	if err != nil {
+		return err
+	}
+	worker.DeleteCachedSwap(chainID, txid, logIndex)
+	*result = successReuslt
+	return nil
+}
+
+func routerForceStable(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	var swapTx string
+	if len(args.Params) > 3 {
+		swapTx = args.Params[3]
+	}
+	err = mongodb.RouterAdminForceStable(chainID, txid, logIndex, swapTx)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func routerReleaseSign(args *admin.CallArgs, result *string) (err error) {
+	chainID, txid, logIndex, err := getKeys(args, 0)
+	if err != nil {
+		return err
+	}
+	err = mongodb.RouterAdminReleaseSign(chainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -119,6 +120,81 @@ func (s *RouterSwapAPI) GetRouterSwapHistory(r *http.Request, args *RouterGetSwa
 	return err
 }
 
+// SearchRouterSwapHistoryArgs args
+type SearchRouterSwapHistoryArgs struct {
+	FromChainID string  `json:"fromchainid"`
+	ToChainID   string  `json:"tochainid"`
+	TokenID     string  `json:"tokenid"`
+	Address     string  `json:"address"`
+	SwapType    *uint32 `json:"swaptype"`
+	Status      string  `json:"status"`
+	StartTime   int64   `json:"starttime"`
+	EndTime     int64   `json:"endtime"`
+	MinValue    string  `json:"minvalue"`
+	MaxValue    string  `json:"maxvalue"`
+	Cursor      string  `json:"cursor"`
+	Limit       int     `json:"limit"`
+}
+
+// SearchRouterSwapHistory api (cursor pagination)
+func (s *RouterSwapAPI) SearchRouterSwapHistory(r *http.Request, args *SearchRouterSwapHistoryArgs, result *swapapi.SwapHistoryResult) error {
+	filter := &mongodb.SwapHistoryFilter{
+		FromChainID: args.FromChainID,
+		ToChainID:   args.ToChainID,
+		TokenID:     args.TokenID,
+		Address:     args.Address,
+		SwapType:    args.SwapType,
+		Status:      args.Status,
+		StartTime:   args.StartTime,
+		EndTime:     args.EndTime,
+		MinValue:    args.MinValue,
+		MaxValue:    args.MaxValue,
+	}
+	res, err := swapapi.GetRouterSwapHistoryWithCursor(filter, args.Cursor, args.Limit)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetSwapFeeTotalsArgs args
+type GetSwapFeeTotalsArgs struct {
+	TokenID     string `json:"tokenid"`
+	FromChainID string `json:"fromchainid"`
+	ToChainID   string `json:"tochainid"`
+	StartTime   int64  `json:"starttime"`
+	EndTime     int64  `json:"endtime"`
+}
+
+// GetSwapFeeTotals api
+func (s *RouterSwapAPI) GetSwapFeeTotals(r *http.Request, args *GetSwapFeeTotalsArgs, result *[]*mongodb.SwapFeeTotal) error {
+	res, err := swapapi.GetSwapFeeTotals(args.TokenID, args.FromChainID, args.ToChainID, args.StartTime, args.EndTime)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapQuoteArgs args
+type GetSwapQuoteArgs struct {
+	TokenID     string `json:"tokenid"`
+	FromChainID string `json:"fromchainid"`
+	ToChainID   string `json:"tochainid"`
+	Amount      string `json:"amount"`
+	From        string `json:"from"`
+	Bind        string `json:"bind"`
+	TxTo        string `json:"txto"`
+}
+
+// GetSwapQuote api
+func (s *RouterSwapAPI) GetSwapQuote(r *http.Request, args *GetSwapQuoteArgs, result *swapapi.SwapQuote) error {
+	res, err := swapapi.GetSwapQuote(args.TokenID, args.FromChainID, args.ToChainID, args.Amount, args.From, args.Bind, args.TxTo)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetAllChainIDs api
 func (s *RouterSwapAPI) GetAllChainIDs(r *http.Request, args *RPCNullArgs, result *[]*big.Int) error {
 	*result = router.AllChainIDs
@@ -17,9 +17,11 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/restapi"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/rpcapi"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/wsapi"
 )
 
 // StartAPIServer start api server
@@ -101,6 +103,60 @@ func StartTestServer(apiPort int) {
 	go utils.WaitAndCleanup(func() { doCleanup(&svr) })
 }
 
+// StartMetricsServer start metrics server (used by oracle)
+func StartMetricsServer(port int) {
+	router := mux.NewRouter()
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	log.Info("metrics service listen and serving", "port", port)
+	svr := http.Server{
+		Addr:         fmt.Sprintf(":%v", port),
+		ReadTimeout:  60 * time.Second,
+		WriteTimeout: 300 * time.Second,
+		Handler:      router,
+	}
+	go func() {
+		if err := svr.ListenAndServe(); err != nil {
+			if errors.Is(err, http.ErrServerClosed) && utils.IsCleanuping() {
+				return
+			}
+			log.Fatal("ListenAndServe error", "err", err)
+		}
+	}()
+
+	utils.TopWaitGroup.Add(1)
+	go utils.WaitAndCleanup(func() { doCleanup(&svr) })
+}
+
+// StartOracleAPIServer start read-only oracle api server (also serve metrics if required)
+func StartOracleAPIServer(port int, withMetrics bool) {
+	router := mux.NewRouter()
+	router.HandleFunc("/oracle/decisions", restapi.SearchAcceptDecisionsHandler).Methods("GET")
+	router.HandleFunc("/oracle/decisions/export", restapi.ExportAcceptDecisionsHandler).Methods("GET")
+	if withMetrics {
+		router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	}
+
+	log.Info("oracle api service listen and serving", "port", port, "withMetrics", withMetrics)
+	svr := http.Server{
+		Addr:         fmt.Sprintf(":%v", port),
+		ReadTimeout:  60 * time.Second,
+		WriteTimeout: 300 * time.Second,
+		Handler:      router,
+	}
+	go func() {
+		if err := svr.ListenAndServe(); err != nil {
+			if errors.Is(err, http.ErrServerClosed) && utils.IsCleanuping() {
+				return
+			}
+			log.Fatal("ListenAndServe error", "err", err)
+		}
+	}()
+
+	utils.TopWaitGroup.Add(1)
+	go utils.WaitAndCleanup(func() { doCleanup(&svr) })
+}
+
 func doCleanup(svr *http.Server) {
 	defer utils.TopWaitGroup.Done()
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -120,6 +176,10 @@ func initRouterSwapRouter(r *mux.Router) {
 	}
 
 	r.Handle("/rpc", rpcserver)
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+	if wsapi.IsEnabled() {
+		r.HandleFunc("/ws", wsapi.Handler).Methods("GET")
+	}
 
 	r.HandleFunc("/versioninfo", restapi.VersionInfoHandler).Methods("GET")
 	r.HandleFunc("/serverinfo", restapi.ServerInfoHandler).Methods("GET")
@@ -128,6 +188,10 @@ func initRouterSwapRouter(r *mux.Router) {
 	r.HandleFunc("/swap/register/{chainid}/{txid}", restapi.RegisterRouterSwapHandler).Methods("POST")
 	r.HandleFunc("/swap/status/{chainid}/{txid}", restapi.GetRouterSwapHandler).Methods("GET")
 	r.HandleFunc("/swap/history/{chainid}/{address}", restapi.GetRouterSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/swap/search", restapi.SearchRouterSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/swap/export", restapi.ExportRouterSwapHistoryHandler).Methods("GET")
+	r.HandleFunc("/swapfee/totals/{tokenid}", restapi.GetSwapFeeTotalsHandler).Methods("GET")
+	r.HandleFunc("/swap/quote/{tokenid}/{fromchainid}/{tochainid}", restapi.GetSwapQuoteHandler).Methods("GET")
 
 	r.HandleFunc("/allchainids", restapi.GetAllChainIDsHandler).Methods("GET")
 	r.HandleFunc("/alltokenids", restapi.GetAllTokenIDsHandler).Methods("GET")
@@ -0,0 +1,444 @@
+// Package wsapi provides websocket subscriptions of swap status updates.
+package wsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/internal/swapapi"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	methodSubscribe    = "swap_subscribe"
+	methodUnsubscribe  = "swap_unsubscribe"
+	methodSubscription = "swap_subscription"
+
+	defaultMaxConnections          = 1000
+	defaultMaxSubscriptionsPerConn = 100
+
+	maxMessageSize = 4096
+	sendChanSize   = 64
+	eventChanSize  = 1000
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = 50 * time.Second
+)
+
+var (
+	errTooManySubscriptions = errors.New("too many subscriptions")
+	errSubscriptionNotFound = errors.New("subscription not found")
+	errWrongSubscribeParams = errors.New("subscribe by 'fromChainID' and 'txid', or by 'bind'")
+
+	initOnce sync.Once
+	eventCh  = make(chan *mongodb.SwapNotifyEvent, eventChanSize)
+
+	conns     = make(map[*wsConn]struct{})
+	connsLock sync.RWMutex
+
+	lastSubscriptionID uint64
+)
+
+type jsonRequest struct {
+	Version string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type jsonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+}
+
+type jsonNotification struct {
+	Version string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  subscriptionResult `json:"params"`
+}
+
+type subscriptionResult struct {
+	Subscription string            `json:"subscription"`
+	Result       *swapapi.SwapInfo `json:"result"`
+}
+
+// SubscribeArgs subscribe by swap (fromChainID, txid, logIndex) or by bind address
+type SubscribeArgs struct {
+	FromChainID string `json:"fromChainID"`
+	TxID        string `json:"txid"`
+	LogIndex    int    `json:"logIndex"`
+	Bind        string `json:"bind"`
+}
+
+// UnsubscribeArgs unsubscribe args
+type UnsubscribeArgs struct {
+	Subscription string `json:"subscription"`
+}
+
+type subscription struct {
+	id          string
+	fromChainID string
+	txid        string
+	logIndex    int // zero means all swaps of tx
+	bind        string
+}
+
+func (s *subscription) match(swap *swapapi.SwapInfo) bool {
+	if s.bind != "" {
+		return strings.EqualFold(s.bind, swap.Bind)
+	}
+	return s.fromChainID == swap.FromChainID &&
+		strings.EqualFold(s.txid, swap.TxID) &&
+		(s.logIndex == 0 || s.logIndex == swap.LogIndex)
+}
+
+type wsConn struct {
+	ws        *websocket.Conn
+	send      chan []byte
+	closeOnce sync.Once
+	done      chan struct{}
+
+	subs     map[string]*subscription
+	subsLock sync.RWMutex
+}
+
+func getWebSocketConfig() *params.WebSocketConfig {
+	if serverCfg := params.GetRouterServerConfig(); serverCfg != nil && serverCfg.APIServer != nil {
+		return serverCfg.APIServer.WebSocket
+	}
+	return nil
+}
+
+// IsEnabled is websocket subscription enabled
+func IsEnabled() bool {
+	cfg := getWebSocketConfig()
+	return cfg != nil && cfg.Enable
+}
+
+func getMaxConnections() int {
+	if cfg := getWebSocketConfig(); cfg != nil && cfg.MaxConnections > 0 {
+		return cfg.MaxConnections
+	}
+	return defaultMaxConnections
+}
+
+func getMaxSubscriptionsPerConn() int {
+	if cfg := getWebSocketConfig(); cfg != nil && cfg.MaxSubscriptionsPerConn > 0 {
+		return cfg.MaxSubscriptionsPerConn
+	}
+	return defaultMaxSubscriptionsPerConn
+}
+
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	allowedOrigins := params.GetRouterServerConfig().APIServer.AllowedOrigins
+	if origin == "" || len(allowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+func initSubscriptions() {
+	mongodb.AddSwapStatusListener(func(event *mongodb.SwapNotifyEvent) {
+		select {
+		case eventCh <- event:
+		default:
+			log.Warn("[wsapi] event channel is full, drop swap event", "event", event.Event, "chainid", event.FromChainID, "txid", event.TxID, "logindex", event.LogIndex)
+		}
+	})
+	go dispatchEvents()
+	go func() {
+		<-utils.CleanupChan
+		for _, c := range getConns() {
+			c.close()
+		}
+	}()
+}
+
+// Handler websocket handler of '/ws'
+func Handler(w http.ResponseWriter, r *http.Request) {
+	initOnce.Do(initSubscriptions)
+
+	connsLock.RLock()
+	connCount := len(conns)
+	connsLock.RUnlock()
+	if connCount >= getMaxConnections() {
+		http.Error(w, "too many websocket connections", http.StatusServiceUnavailable)
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("[wsapi] upgrade websocket failed", "err", err)
+		return
+	}
+	c := &wsConn{
+		ws:   ws,
+		send: make(chan []byte, sendChanSize),
+		done: make(chan struct{}),
+		subs: make(map[string]*subscription),
+	}
+	connsLock.Lock()
+	conns[c] = struct{}{}
+	connsLock.Unlock()
+
+	go c.writeLoop()
+	c.readLoop()
+}
+
+func (c *wsConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.ws.Close()
+		connsLock.Lock()
+		delete(conns, c)
+		connsLock.Unlock()
+	})
+}
+
+// queue message to send, close slow connection if send channel is full
+func (c *wsConn) queue(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Warn("[wsapi] marshal message failed", "err", err)
+		return
+	}
+	select {
+	case c.send <- data:
+	case <-c.done:
+	default:
+		log.Warn("[wsapi] close slow websocket connection", "remote", c.ws.RemoteAddr())
+		c.close()
+	}
+}
+
+func (c *wsConn) readLoop() {
+	defer c.close()
+	c.ws.SetReadLimit(maxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req jsonRequest
+		if err = json.Unmarshal(data, &req); err != nil {
+			c.queue(&jsonResponse{Version: "2.0", Error: &jsonError{Code: -32700, Message: err.Error()}})
+			continue
+		}
+		c.handleRequest(&req)
+	}
+}
+
+func (c *wsConn) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+	for {
+		select {
+		case <-c.done:
+			return
+		case data := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConn) handleRequest(req *jsonRequest) {
+	resp := &jsonResponse{Version: "2.0", ID: req.ID}
+	var (
+		result interface{}
+		err    error
+	)
+	switch req.Method {
+	case methodSubscribe:
+		var args SubscribeArgs
+		if err = unmarshalParams(req.Params, &args); err == nil {
+			result, err = c.subscribe(&args)
+		}
+	case methodUnsubscribe:
+		var args UnsubscribeArgs
+		if err = unmarshalParams(req.Params, &args); err == nil {
+			result, err = c.unsubscribe(args.Subscription)
+		}
+	default:
+		resp.Error = &jsonError{Code: -32601, Message: fmt.Sprintf("method %v not found", req.Method)}
+		c.queue(resp)
+		return
+	}
+	if err != nil {
+		resp.Error = &jsonError{Code: -32602, Message: err.Error()}
+	} else {
+		resp.Result = result
+	}
+	c.queue(resp)
+
+	if id, ok := result.(string); ok && req.Method == methodSubscribe {
+		c.pushCurrentStatus(id)
+	}
+}
+
+func unmarshalParams(rawParams []json.RawMessage, args interface{}) error {
+	if len(rawParams) != 1 {
+		return errors.New("wrong number of params")
+	}
+	return json.Unmarshal(rawParams[0], args)
+}
+
+func (c *wsConn) subscribe(args *SubscribeArgs) (string, error) {
+	sub := &subscription{}
+	switch {
+	case args.Bind != "":
+		sub.bind = args.Bind
+	case args.FromChainID != "" && args.TxID != "":
+		sub.fromChainID = args.FromChainID
+		sub.txid = args.TxID
+		sub.logIndex = args.LogIndex
+	default:
+		return "", errWrongSubscribeParams
+	}
+
+	c.subsLock.Lock()
+	defer c.subsLock.Unlock()
+	if len(c.subs) >= getMaxSubscriptionsPerConn() {
+		return "", errTooManySubscriptions
+	}
+	sub.id = fmt.Sprintf("0x%x", atomic.AddUint64(&lastSubscriptionID, 1))
+	c.subs[sub.id] = sub
+	return sub.id, nil
+}
+
+func (c *wsConn) unsubscribe(id string) (bool, error) {
+	c.subsLock.Lock()
+	defer c.subsLock.Unlock()
+	if _, exist := c.subs[id]; !exist {
+		return false, errSubscriptionNotFound
+	}
+	delete(c.subs, id)
+	return true, nil
+}
+
+// pushCurrentStatus push current status when subscribe a swap
+func (c *wsConn) pushCurrentStatus(id string) {
+	c.subsLock.RLock()
+	sub, exist := c.subs[id]
+	c.subsLock.RUnlock()
+	if !exist || sub.txid == "" {
+		return
+	}
+	swap, err := swapapi.GetRouterSwap(sub.fromChainID, sub.txid, strconv.Itoa(sub.logIndex))
+	if err != nil {
+		return
+	}
+	c.queue(&jsonNotification{
+		Version: "2.0",
+		Method:  methodSubscription,
+		Params:  subscriptionResult{Subscription: sub.id, Result: swap},
+	})
+}
+
+func dispatchEvents() {
+	for {
+		select {
+		case <-utils.CleanupChan:
+			return
+		case event := <-eventCh:
+			connsLock.RLock()
+			connCount := len(conns)
+			connsLock.RUnlock()
+			if connCount == 0 {
+				continue
+			}
+			swap, err := getSwapInfo(event)
+			if err != nil {
+				log.Debug("[wsapi] get swap of event failed", "chainid", event.FromChainID, "txid", event.TxID, "logindex", event.LogIndex, "err", err)
+				continue
+			}
+			dispatchSwap(swap)
+		}
+	}
+}
+
+func getSwapInfo(event *mongodb.SwapNotifyEvent) (*swapapi.SwapInfo, error) {
+	result, err := mongodb.FindRouterSwapResult(event.FromChainID, event.TxID, event.LogIndex)
+	if err == nil {
+		return swapapi.ConvertMgoSwapResultToSwapInfo(result), nil
+	}
+	swap, err := mongodb.FindRouterSwap(event.FromChainID, event.TxID, event.LogIndex)
+	if err != nil {
+		return nil, err
+	}
+	return swapapi.ConvertMgoSwapToSwapInfo(swap), nil
+}
+
+func getConns() []*wsConn {
+	connsLock.RLock()
+	defer connsLock.RUnlock()
+	result := make([]*wsConn, 0, len(conns))
+	for c := range conns {
+		result = append(result, c)
+	}
+	return result
+}
+
+func dispatchSwap(swap *swapapi.SwapInfo) {
+	for _, c := range getConns() {
+		c.subsLock.RLock()
+		var matched []string
+		for id, sub := range c.subs {
+			if sub.match(swap) {
+				matched = append(matched, id)
+			}
+		}
+		c.subsLock.RUnlock()
+		for _, id := range matched {
+			c.queue(&jsonNotification{
+				Version: "2.0",
+				Method:  methodSubscription,
+				Params:  subscriptionResult{Subscription: id, Result: swap},
+			})
+		}
+	}
+}
@@ -191,62 +191,75 @@ func CheckTokenSwapValue(swapInfo *SwapTxInfo, fromDecimals, toDecimals uint8) b
 		!params.IsInBigValueWhitelist(tokenID, swapInfo.TxTo) {
 		return false
 	}
-	return CalcSwapValue(tokenID, toChainID, value, fromDecimals, toDecimals, swapInfo.From, swapInfo.TxTo).Sign() > 0
+	return CalcSwapValue(tokenID, toChainID, value, fromDecimals, toDecimals, swapInfo.From, swapInfo.TxTo, swapInfo.Timestamp).Sign() > 0
 }
 
-// CalcSwapValue calc swap value (get rid of fee and convert by decimals)
-func CalcSwapValue(tokenID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string) *big.Int {
+// CalcSwapValue calc swap value (get rid of fee by fee policy and convert by decimals)
+func CalcSwapValue(tokenID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string, originTime uint64) *big.Int {
+	swapValue, _ := CalcSwapValueWithFee(tokenID, toChainID, value, fromDecimals, toDecimals, originFrom, originTxTo, originTime)
+	return swapValue
+}
+
+// CalcSwapValueWithFee calc swap value, and return the charged swap fee info.
+// originTime is the timestamp (unix seconds) of source tx, 0 means now.
+func CalcSwapValueWithFee(tokenID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string, originTime uint64) (*big.Int, *SwapFeeInfo) {
+	return CalcSwapValueWithFeeGasPrice(tokenID, toChainID, value, fromDecimals, toDecimals, originFrom, originTxTo, originTime, nil)
+}
+
+// CalcSwapValueWithFeeGasPrice calc swap value with the dest gas price
+// (used by gas scaled fee policy, nil means unknown), and return the charged swap fee info.
+func CalcSwapValueWithFeeGasPrice(tokenID, toChainID string, value *big.Int, fromDecimals, toDecimals uint8, originFrom, originTxTo string, originTime uint64, gasPrice *big.Int) (*big.Int, *SwapFeeInfo) {
 	if !IsERC20Router() {
-		return value
+		return value, nil
 	}
 	swapCfg := GetSwapConfig(tokenID, toChainID)
 	if swapCfg == nil {
-		return big.NewInt(0)
+		return big.NewInt(0), nil
 	}
 
-	valueLeft := value
-	if swapCfg.SwapFeeRatePerMillion > 0 {
-		var swapFee, adjustBaseFee *big.Int
-		minSwapFee := ConvertTokenValue(swapCfg.MinimumSwapFee, 18, fromDecimals)
-		if params.IsInBigValueWhitelist(tokenID, originFrom) ||
-			params.IsInBigValueWhitelist(tokenID, originTxTo) {
-			swapFee = minSwapFee
-		} else {
-			swapFee = new(big.Int).Mul(value, new(big.Int).SetUint64(swapCfg.SwapFeeRatePerMillion))
-			swapFee.Div(swapFee, big.NewInt(1000000))
-
-			if swapFee.Cmp(minSwapFee) < 0 {
-				swapFee = minSwapFee
-			} else {
-				maxSwapFee := ConvertTokenValue(swapCfg.MaximumSwapFee, 18, fromDecimals)
-				if swapFee.Cmp(maxSwapFee) > 0 {
-					swapFee = maxSwapFee
-				}
-			}
-
-			baseFeePercent := params.GetBaseFeePercent(toChainID)
-			if baseFeePercent != 0 && minSwapFee.Sign() > 0 {
-				adjustBaseFee = new(big.Int).Set(minSwapFee)
-				adjustBaseFee.Mul(adjustBaseFee, big.NewInt(baseFeePercent))
-				adjustBaseFee.Div(adjustBaseFee, big.NewInt(100))
-				swapFee = new(big.Int).Add(swapFee, adjustBaseFee)
-				if swapFee.Sign() < 0 {
-					swapFee = big.NewInt(0)
-				}
-			}
-		}
+	policyCfg := params.GetFeePolicyConfig(tokenID, toChainID)
+	policy, err := NewFeePolicy(policyCfg)
+	if err != nil {
+		log.Error("get fee policy failed", "tokenID", tokenID, "toChainID", toChainID, "err", err)
+		return big.NewInt(0), nil
+	}
+	feeInfo := &SwapFeeInfo{
+		Policy:       DefaultFeePolicyType,
+		FromDecimals: fromDecimals,
+		ToDecimals:   toDecimals,
+	}
+	if policyCfg != nil && policyCfg.Type != "" {
+		feeInfo.Policy = strings.ToLower(policyCfg.Type)
+	}
+	swapFee, err := policy.CalcSwapFee(&FeeContext{
+		TokenID:      tokenID,
+		ToChainID:    toChainID,
+		Value:        value,
+		FromDecimals: fromDecimals,
+		OriginFrom:   originFrom,
+		OriginTxTo:   originTxTo,
+		OriginTime:   originTime,
+		GasPrice:     gasPrice,
+		SwapConfig:   swapCfg,
+		FeeInfo:      feeInfo,
+	})
+	if err != nil {
+		log.Error("calc swap fee failed", "tokenID", tokenID, "toChainID", toChainID, "value", value, "err", err)
+		return big.NewInt(0), nil
+	}
+	feeInfo.SwapFee = swapFee
 
+	valueLeft := value
+	if swapFee.Sign() > 0 {
 		if value.Cmp(swapFee) <= 0 {
 			log.Warn("check swap value failed",
-				"value", value, "tokenID", tokenID, "toChainID", toChainID,
-				"minSwapFee", minSwapFee, "adjustBaseFee", adjustBaseFee, "swapFee", swapFee)
-			return big.NewInt(0)
+				"value", value, "tokenID", tokenID, "toChainID", toChainID, "swapFee", swapFee)
+			return big.NewInt(0), feeInfo
 		}
-
 		valueLeft = new(big.Int).Sub(value, swapFee)
 	}
 
-	return ConvertTokenValue(valueLeft, fromDecimals, toDecimals)
+	return ConvertTokenValue(valueLeft, fromDecimals, toDecimals), feeInfo
 }
 
 // ToBits calc
@@ -0,0 +1,62 @@
+package btc
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// DecodeAddress decode address of this chain
+func (b *Bridge) DecodeAddress(address string) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(address, b.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IsForNet(b.ChainParams) {
+		return nil, fmt.Errorf("address '%v' is not for network '%v'", address, b.ChainParams.Name)
+	}
+	return addr, nil
+}
+
+// IsValidAddress check address
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := b.DecodeAddress(address)
+	return err == nil
+}
+
+// GetPayToAddrScript get pay to address script
+func (b *Bridge) GetPayToAddrScript(address string) ([]byte, error) {
+	addr, err := b.DecodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(addr)
+}
+
+// PublicKeyToAddress returns p2pkh address of compressed public key
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	pubKey, err := btcec.ParsePubKey(common.FromHex(pubKeyHex), btcec.S256())
+	if err != nil {
+		return "", err
+	}
+	address, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), b.ChainParams)
+	if err != nil {
+		return "", err
+	}
+	return address.EncodeAddress(), nil
+}
+
+// VerifyMPCPubKey verify mpc address and public key is matching
+func (b *Bridge) VerifyMPCPubKey(mpcAddress, mpcPubkey string) error {
+	address, err := b.PublicKeyToAddress(mpcPubkey)
+	if err != nil {
+		return err
+	}
+	if address != mpcAddress {
+		return fmt.Errorf("mpc address %v and public key address %v is not match", mpcAddress, address)
+	}
+	return nil
+}
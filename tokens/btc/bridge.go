@@ -0,0 +1,129 @@
+// Package btc implements the bridge interfaces for bitcoin-family (UTXO) chains.
+package btc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+var (
+	// ensure Bridge impl tokens.CrossChainBridge
+	_ tokens.IBridge = &Bridge{}
+)
+
+// NativeToken token address of the native coin in token config
+const NativeToken = "native"
+
+func init() {
+	tokens.RegisterBridge("btc", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	}, "bitcoin", "litecoin", "ltc", "dogecoin", "doge")
+}
+
+// Bridge btc bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	ChainParams      *chaincfg.Params
+	RPCClientTimeout int
+}
+
+// NewCrossChainBridge new bridge
+func NewCrossChainBridge() *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(),
+		RPCClientTimeout:     client.GetDefaultTimeout(false),
+	}
+}
+
+// InitAfterConfig init variables (ie. extra members) after loading config
+func (b *Bridge) InitAfterConfig() {
+	isReload := router.IsReloading
+	logErrFunc := log.GetLogFuncOr(isReload, log.Error, log.Fatal)
+	chainID := b.ChainConfig.ChainID
+	network := params.GetCustom(chainID, "network")
+	chainParams, err := GetChainParams(b.ChainConfig.BlockChain, network)
+	if err != nil {
+		logErrFunc("init chain params failed",
+			"chainID", chainID,
+			"blockChain", b.ChainConfig.BlockChain,
+			"network", network,
+			"err", err)
+		if isReload {
+			return
+		}
+	}
+	b.ChainParams = chainParams
+	if timeout := params.GetRPCClientTimeout(chainID); timeout != 0 {
+		b.RPCClientTimeout = timeout
+	}
+	log.Info("init btc bridge success", "chainID", chainID, "blockChain", b.ChainConfig.BlockChain, "network", chainParams.Name)
+}
+
+// InitRouterInfo init router info.
+// the router contract of utxo chain is the mpc deposit address.
+func (b *Bridge) InitRouterInfo(routerContract string) (err error) {
+	if routerContract == "" {
+		return nil
+	}
+	chainID := b.ChainConfig.ChainID
+	log.Info(fmt.Sprintf("[%5v] start init router info", chainID), "routerContract", routerContract)
+	if !b.IsValidAddress(routerContract) {
+		return fmt.Errorf("wrong router mpc address '%v'", routerContract)
+	}
+	routerMPC := routerContract
+	routerMPCPubkey, err := router.GetMPCPubkey(routerMPC)
+	if err != nil {
+		log.Warn("get mpc public key failed", "mpc", routerMPC, "err", err)
+		return err
+	}
+	if err = b.VerifyMPCPubKey(routerMPC, routerMPCPubkey); err != nil {
+		log.Warn("verify mpc public key failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
+		return err
+	}
+	router.SetRouterInfo(
+		routerContract,
+		&router.SwapRouterInfo{
+			RouterMPC: routerMPC,
+		},
+	)
+	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+
+	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
+		"routerContract", routerContract, "routerMPC", routerMPC)
+	return nil
+}
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.GetTransactionByHash(txHash)
+}
+
+// GetLatestBlockNumber impl
+func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
+	return b.GetBlockCount()
+}
+
+// GetLatestBlockNumberOf impl
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	return b.GetBlockCountOf(url)
+}
+
+// GetBalance get balance of all unspent outputs
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	utxos, err := b.ListUnspent(account)
+	if err != nil {
+		return nil, err
+	}
+	balance := big.NewInt(0)
+	for _, utxo := range utxos {
+		balance.Add(balance, big.NewInt(utxo.Value))
+	}
+	return balance, nil
+}
@@ -0,0 +1,339 @@
+package btc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	tTokenID    = "BTC"
+	tBlockHash  = "0000000000000000000000000000000000000000000000000000000000000abc"
+	tFromChain  = "1000"
+	tToChain    = "2000"
+	tBlockCount = 200
+)
+
+// stubRPC returns canned json results keyed by method (and first param)
+type stubRPC struct {
+	results map[string]interface{}
+}
+
+func (s *stubRPC) set(key string, result interface{}) {
+	s.results[key] = result
+}
+
+func (s *stubRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	var params []interface{}
+	_ = json.Unmarshal(req.Params, &params)
+	key := req.Method
+	if len(params) > 0 {
+		if first, ok := params[0].(string); ok {
+			if _, exist := s.results[key+"/"+first]; exist {
+				key += "/" + first
+			}
+		}
+	}
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if result, exist := s.results[key]; exist {
+		resp["result"] = result
+	} else {
+		resp["error"] = map[string]interface{}{"code": -5, "message": "not found " + key}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+type testEnv struct {
+	rpc        *stubRPC
+	server     *httptest.Server
+	srcBridge  *Bridge
+	dstBridge  *Bridge
+	mpcKey     *btcec.PrivateKey
+	mpcAddress string
+	userKey    *btcec.PrivateKey
+	userAddr   string
+}
+
+func newTestBridge(chainID, url, routerContract string) *Bridge {
+	b := NewCrossChainBridge()
+	b.ChainParams = &chaincfg.RegressionNetParams
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{url}})
+	chainCfg := &tokens.ChainConfig{
+		ChainID:        chainID,
+		BlockChain:     "bitcoin",
+		RouterContract: routerContract,
+		Confirmations:  3,
+	}
+	_ = chainCfg.CheckConfig()
+	b.SetChainConfig(chainCfg)
+	b.SetTokenConfig(NativeToken, &tokens.TokenConfig{
+		TokenID:         tTokenID,
+		Decimals:        8,
+		ContractAddress: NativeToken,
+	})
+	return b
+}
+
+func p2pkhAddress(key *btcec.PrivateKey) string {
+	addr, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	return addr.EncodeAddress()
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{rpc: &stubRPC{results: make(map[string]interface{})}}
+	env.server = httptest.NewServer(env.rpc)
+	env.mpcKey, _ = btcec.NewPrivateKey(btcec.S256())
+	env.userKey, _ = btcec.NewPrivateKey(btcec.S256())
+	env.mpcAddress = p2pkhAddress(env.mpcKey)
+	env.userAddr = p2pkhAddress(env.userKey)
+
+	env.srcBridge = newTestBridge(tFromChain, env.server.URL, env.mpcAddress)
+	env.dstBridge = newTestBridge(tToChain, env.server.URL, env.mpcAddress)
+	router.SetBridge(tFromChain, env.srcBridge)
+	router.SetBridge(tToChain, env.dstBridge)
+	router.SetMultichainToken(tTokenID, tFromChain, NativeToken)
+	router.SetMultichainToken(tTokenID, tToChain, NativeToken)
+	router.SetRouterInfo(env.mpcAddress, &router.SwapRouterInfo{RouterMPC: env.mpcAddress})
+
+	tokens.InitRouterSwapType("erc20swap")
+	oneBTC := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	swapCfg := &tokens.SwapConfig{
+		MaximumSwap:           new(big.Int).Mul(oneBTC, big.NewInt(100)),
+		MinimumSwap:           new(big.Int).Div(oneBTC, big.NewInt(10000)),
+		BigValueThreshold:     new(big.Int).Mul(oneBTC, big.NewInt(10)),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        new(big.Int).Div(oneBTC, big.NewInt(100)),
+		MinimumSwapFee:        new(big.Int).Div(oneBTC, big.NewInt(100000)),
+	}
+	swapConfig := new(sync.Map)
+	swapConfig.Store(tFromChain, swapCfg)
+	swapConfig.Store(tToChain, swapCfg)
+	swapConfigs := new(sync.Map)
+	swapConfigs.Store(tTokenID, swapConfig)
+	tokens.SetSwapConfigs(swapConfigs)
+
+	env.rpc.set("getblockcount", tBlockCount)
+	env.rpc.set("estimatesmartfee", map[string]interface{}{"feerate": 0.00002, "blocks": 6})
+	env.rpc.set("getblockheader/"+tBlockHash, map[string]interface{}{"hash": tBlockHash, "height": 100, "time": 1600000000, "confirmations": 101})
+	return env
+}
+
+func (env *testEnv) addTx(t *testing.T, tx *wire.MsgTx, confirmations uint64) string {
+	rawHex, err := EncodeRawTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txid := tx.TxHash().String()
+	env.rpc.set("getrawtransaction/"+txid, map[string]interface{}{
+		"txid":          txid,
+		"hex":           rawHex,
+		"blockhash":     tBlockHash,
+		"confirmations": confirmations,
+		"blocktime":     1600000000,
+	})
+	return txid
+}
+
+func payToScript(t *testing.T, address string) []byte {
+	addr, err := btcutil.DecodeAddress(address, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, _ := txscript.PayToAddrScript(addr)
+	return script
+}
+
+func (env *testEnv) newSwapoutTx(t *testing.T, value int64, memo string) string {
+	fundTx := wire.NewMsgTx(wire.TxVersion)
+	fundTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	fundTx.AddTxOut(wire.NewTxOut(value*2, payToScript(t, env.userAddr)))
+	fundHash := fundTx.TxHash()
+	env.addTx(t, fundTx, 10)
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&fundHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value, payToScript(t, env.mpcAddress)))
+	if memo != "" {
+		memoScript, _ := NullDataScript(memo)
+		tx.AddTxOut(wire.NewTxOut(0, memoScript))
+	}
+	tx.AddTxOut(wire.NewTxOut(value-1000, payToScript(t, env.userAddr)))
+	return env.addTx(t, tx, 10)
+}
+
+func TestRegisterAndVerifySwap(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.server.Close()
+
+	bind := p2pkhAddress(env.userKey)
+	txid := env.newSwapoutTx(t, 1000000, tokens.EncodeSwapoutMemo(bind, big.NewInt(2000)))
+
+	swapInfos, errs := env.srcBridge.RegisterSwap(txid, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if len(swapInfos) != 1 || len(errs) != 1 || errs[0] != nil {
+		t.Fatalf("register swap failed. %v", errs)
+	}
+	swapInfo := swapInfos[0]
+	if swapInfo.Bind != bind || swapInfo.ToChainID.String() != tToChain ||
+		swapInfo.Value.Int64() != 1000000 || swapInfo.From != env.userAddr ||
+		swapInfo.To != env.mpcAddress || swapInfo.Height != 100 {
+		t.Fatalf("register swap with wrong info %+v", swapInfo)
+	}
+
+	_, err := env.srcBridge.VerifyTransaction(txid, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType})
+	if err != nil {
+		t.Fatalf("verify swap failed. %v", err)
+	}
+
+	_, err = env.srcBridge.VerifyTransaction(txid, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: 1})
+	if err != tokens.ErrLogIndexOutOfRange {
+		t.Errorf("verify with log index 1 want error %v but got %v", tokens.ErrLogIndexOutOfRange, err)
+	}
+
+	noMemoTxid := env.newSwapoutTx(t, 1000000, "")
+	_, errs = env.srcBridge.RegisterSwap(noMemoTxid, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if errs[0] != tokens.ErrTxWithoutMemo {
+		t.Errorf("register swap without memo want error %v but got %v", tokens.ErrTxWithoutMemo, errs[0])
+	}
+
+	wrongMemoTxid := env.newSwapoutTx(t, 1000000, bind)
+	_, errs = env.srcBridge.RegisterSwap(wrongMemoTxid, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if errs[0] != tokens.ErrTxWithWrongMemo {
+		t.Errorf("register swap with wrong memo want error %v but got %v", tokens.ErrTxWithWrongMemo, errs[0])
+	}
+}
+
+func TestBuildAndSignTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.server.Close()
+
+	// mpc owns three utxos
+	mpcScript := payToScript(t, env.mpcAddress)
+	utxos := make([]map[string]interface{}, 0)
+	for i, value := range []int64{300000, 500000, 200000} {
+		fundTx := wire.NewMsgTx(wire.TxVersion)
+		fundTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(i + 10)}, 0), nil, nil))
+		fundTx.AddTxOut(wire.NewTxOut(value, mpcScript))
+		txid := env.addTx(t, fundTx, 10)
+		utxos = append(utxos, map[string]interface{}{
+			"txid":          txid,
+			"vout":          0,
+			"address":       env.mpcAddress,
+			"scriptPubKey":  hex.EncodeToString(mpcScript),
+			"amount":        btcutil.Amount(value).ToBTC(),
+			"confirmations": 10,
+		})
+	}
+	env.rpc.set("listunspent", utxos)
+
+	balance, err := env.dstBridge.GetBalance(env.mpcAddress)
+	if err != nil || balance.Int64() != 1000000 {
+		t.Fatalf("get balance failed. balance %v err %v", balance, err)
+	}
+
+	bind := p2pkhAddress(env.userKey)
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: NativeToken, TokenID: tTokenID}},
+			SwapID:      "0x" + hex.EncodeToString(make([]byte, 32)),
+			SwapType:    tokens.ERC20SwapType,
+			Bind:        bind,
+			FromChainID: big.NewInt(1000),
+			ToChainID:   big.NewInt(2000),
+		},
+		From:        env.mpcAddress,
+		OriginValue: big.NewInt(600000),
+	}
+	rawTx, err := env.dstBridge.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatalf("build tx failed. %v", err)
+	}
+	authoredTx := rawTx.(*AuthoredTx)
+	tx := authoredTx.Tx
+
+	// largest first: 500000 + 300000
+	if len(tx.TxIn) != 2 || authoredTx.TotalInput != 800000 {
+		t.Fatalf("coin selection failed. inputs %v total %v", len(tx.TxIn), authoredTx.TotalInput)
+	}
+	if len(args.Extra.BtcExtra.PreviousOutPoints) != 2 || *args.Extra.BtcExtra.RelayFeePerKb != 2000 {
+		t.Fatalf("build tx with wrong extra %+v", args.Extra.BtcExtra)
+	}
+	// fee is 600000 * 0.001 = 600, raised to min swap fee 1000
+	if tx.TxOut[0].Value != 599000 || args.SwapValue.Int64() != 599000 {
+		t.Fatalf("build tx with wrong swap value %v", tx.TxOut[0].Value)
+	}
+	if authoredTx.ChangeIndex != 2 ||
+		tx.TxOut[2].Value != authoredTx.TotalInput-599000-authoredTx.Fee {
+		t.Fatalf("build tx with wrong change output")
+	}
+
+	sigHashes, err := authoredTx.SigHashes()
+	if err != nil || len(sigHashes) != len(tx.TxIn) {
+		t.Fatalf("calc sig hashes failed. %v", err)
+	}
+	msgHashes := make([]string, len(sigHashes))
+	for i, sigHash := range sigHashes {
+		msgHashes[i] = common.ToHex(sigHash)
+	}
+
+	// rebuild with the same extra args (as oracles do) must have the same msg hashes
+	rebuildArgs := &tokens.BuildTxArgs{
+		SwapArgs:    args.SwapArgs,
+		From:        args.From,
+		OriginValue: args.OriginValue,
+		Extra:       args.Extra,
+	}
+	rebuildTx, err := env.dstBridge.BuildRawTransaction(rebuildArgs)
+	if err != nil {
+		t.Fatalf("rebuild tx failed. %v", err)
+	}
+	if err = env.dstBridge.VerifyMsgHash(rebuildTx, msgHashes); err != nil {
+		t.Fatalf("verify msg hash failed. %v", err)
+	}
+	if err = env.dstBridge.VerifyMsgHash(rebuildTx, msgHashes[:1]); err != tokens.ErrWrongCountOfMsgHashes {
+		t.Errorf("verify msg hash with wrong count want error %v but got %v", tokens.ErrWrongCountOfMsgHashes, err)
+	}
+
+	signedTx, txHash, err := env.dstBridge.SignTransactionWithPrivateKey(rawTx, hex.EncodeToString(env.mpcKey.Serialize()))
+	if err != nil {
+		t.Fatalf("sign tx failed. %v", err)
+	}
+	msgTx := signedTx.(*wire.MsgTx)
+	if msgTx.TxHash().String() != txHash {
+		t.Fatalf("signed tx hash mismatch")
+	}
+	for i := range msgTx.TxIn {
+		vm, err := txscript.NewEngine(authoredTx.PrevScripts[i], msgTx, i, txscript.StandardVerifyFlags, nil, nil, authoredTx.PrevValues[i])
+		if err != nil {
+			t.Fatalf("new script engine failed. %v", err)
+		}
+		if err = vm.Execute(); err != nil {
+			t.Fatalf("execute script of input %v failed. %v", i, err)
+		}
+	}
+
+	env.rpc.set("sendrawtransaction", txHash)
+	sentHash, err := env.dstBridge.SendTransaction(signedTx)
+	if err != nil || sentHash != txHash {
+		t.Fatalf("send tx failed. hash %v err %v", sentHash, err)
+	}
+}
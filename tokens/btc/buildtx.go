@@ -0,0 +1,336 @@
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// DefaultRelayFeePerKb default relay fee per kb (in satoshi)
+	DefaultRelayFeePerKb int64 = 2000
+	// MinRelayFeePerKb min relay fee per kb (in satoshi)
+	MinRelayFeePerKb int64 = 1000
+	// MaxRelayFeePerKb max relay fee per kb (in satoshi)
+	MaxRelayFeePerKb int64 = 500000
+	// DustThreshold outputs below this value are not relayed
+	DustThreshold int64 = 546
+	// MaxTxInputs max number of inputs in one tx
+	MaxTxInputs = 100
+
+	// sizes of p2pkh tx parts (in bytes)
+	txOverheadSize   = 10
+	p2pkhInputSize   = 148
+	p2pkhOutputSize  = 34
+	nullDataBaseSize = 11
+
+	errInsufficientFunds = errors.New("insufficient unspent outputs")
+)
+
+// AuthoredTx tx with its previous output scripts and values
+type AuthoredTx struct {
+	Tx          *wire.MsgTx
+	PrevScripts [][]byte
+	PrevValues  []int64
+	TotalInput  int64
+	Fee         int64
+	ChangeIndex int // -1 if there is no change output
+}
+
+// SigHashes calc signature hash of every input
+func (t *AuthoredTx) SigHashes() ([][]byte, error) {
+	if t.Tx == nil || len(t.Tx.TxIn) != len(t.PrevScripts) {
+		return nil, tokens.ErrWrongRawTx
+	}
+	sigHashes := make([][]byte, len(t.Tx.TxIn))
+	for i, prevScript := range t.PrevScripts {
+		sigHash, err := txscript.CalcSignatureHash(prevScript, txscript.SigHashAll, t.Tx, i)
+		if err != nil {
+			return nil, err
+		}
+		sigHashes[i] = sigHash
+	}
+	return sigHashes, nil
+}
+
+type prevOutput struct {
+	outPoint *tokens.BtcOutPoint
+	pkScript []byte
+	value    int64
+}
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
+		return nil, tokens.ErrToChainIDMismatch
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if args.From != routerMPC {
+		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
+		return nil, tokens.ErrSenderMismatch
+	}
+
+	amount, err := b.getSwapAmount(args)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 || !amount.IsInt64() || amount.Int64() < DustThreshold {
+		return nil, fmt.Errorf("wrong swap value %v", amount)
+	}
+	args.To = args.Bind     // to
+	args.Value = amount     // value
+	args.SwapValue = amount // swapValue
+	args.Memo = args.SwapID // memo
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args)
+}
+
+func (b *Bridge) getSwapAmount(args *tokens.BuildTxArgs) (*big.Int, error) {
+	erc20SwapInfo := args.ERC20SwapInfo
+	if erc20SwapInfo == nil || erc20SwapInfo.TokenID == "" {
+		return nil, errors.New("build router swaptx without tokenID")
+	}
+	if !b.IsValidAddress(args.Bind) {
+		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
+		return nil, errors.New("can not swapout to empty or invalid receiver")
+	}
+	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
+	if fromBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := fromBridge.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", args.FromChainID, "token", erc20SwapInfo.Token)
+		return nil, tokens.ErrMissTokenConfig
+	}
+	toTokenCfg := b.GetTokenConfig(NativeToken)
+	if toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	amount, swapFeeInfo := tokens.CalcSwapValueWithFee(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo, args.OriginTime)
+	args.SwapFeeInfo = swapFeeInfo
+	return amount, nil
+}
+
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) error {
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	if args.Extra.BtcExtra == nil {
+		args.Extra.BtcExtra = &tokens.BtcExtraArgs{}
+	}
+	extra := args.Extra.BtcExtra
+	if extra.RelayFeePerKb == nil {
+		relayFee := b.getRelayFeePerKb()
+		extra.RelayFeePerKb = &relayFee
+	}
+	if *extra.RelayFeePerKb < MinRelayFeePerKb || *extra.RelayFeePerKb > MaxRelayFeePerKb {
+		return fmt.Errorf("relay fee per kb %v is out of range [%v, %v]", *extra.RelayFeePerKb, MinRelayFeePerKb, MaxRelayFeePerKb)
+	}
+	if extra.ChangeAddress == nil {
+		changeAddress := args.From
+		extra.ChangeAddress = &changeAddress
+	}
+	if *extra.ChangeAddress != args.From {
+		return fmt.Errorf("change address %v is not the sender %v", *extra.ChangeAddress, args.From)
+	}
+	return nil
+}
+
+func (b *Bridge) getRelayFeePerKb() int64 {
+	if custom := params.GetCustom(b.ChainConfig.ChainID, "RelayFeePerKb"); custom != "" {
+		if relayFee, err := strconv.ParseInt(custom, 10, 64); err == nil {
+			return relayFee
+		}
+		log.Warn("wrong custom 'RelayFeePerKb' config", "chainID", b.ChainConfig.ChainID, "value", custom)
+	}
+	relayFee, err := b.EstimateFeePerKb(6)
+	if err != nil {
+		log.Warn("estimate relay fee failed, use default value", "chainID", b.ChainConfig.ChainID, "default", DefaultRelayFeePerKb, "err", err)
+		return DefaultRelayFeePerKb
+	}
+	if relayFee < MinRelayFeePerKb {
+		return MinRelayFeePerKb
+	}
+	if relayFee > MaxRelayFeePerKb {
+		return MaxRelayFeePerKb
+	}
+	return relayFee
+}
+
+func estimateFee(relayFeePerKb int64, numInputs, numOutputs, memoLen int) int64 {
+	size := txOverheadSize + numInputs*p2pkhInputSize + numOutputs*p2pkhOutputSize
+	if memoLen > 0 {
+		size += nullDataBaseSize + memoLen
+	}
+	return relayFeePerKb * int64(size) / 1000
+}
+
+func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	extra := args.Extra.BtcExtra
+	relayFeePerKb := *extra.RelayFeePerKb
+	amount := args.Value.Int64()
+
+	toScript, err := b.GetPayToAddrScript(args.To)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := b.GetPayToAddrScript(*extra.ChangeAddress)
+	if err != nil {
+		return nil, err
+	}
+	memoScript, err := NullDataScript(args.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevOutputs []*prevOutput
+	if len(extra.PreviousOutPoints) == 0 {
+		prevOutputs, err = b.selectUtxos(args.From, amount, relayFeePerKb, len(args.Memo))
+	} else {
+		prevOutputs, err = b.loadPrevOutputs(args.From, extra.PreviousOutPoints)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	authoredTx := &AuthoredTx{Tx: tx, ChangeIndex: -1}
+	outPoints := make([]*tokens.BtcOutPoint, 0, len(prevOutputs))
+	for _, prevOut := range prevOutputs {
+		hash, errt := chainhash.NewHashFromStr(prevOut.outPoint.Hash)
+		if errt != nil {
+			return nil, errt
+		}
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(hash, prevOut.outPoint.Index), nil, nil))
+		authoredTx.PrevScripts = append(authoredTx.PrevScripts, prevOut.pkScript)
+		authoredTx.PrevValues = append(authoredTx.PrevValues, prevOut.value)
+		authoredTx.TotalInput += prevOut.value
+		outPoints = append(outPoints, prevOut.outPoint)
+	}
+	extra.PreviousOutPoints = outPoints
+
+	tx.AddTxOut(wire.NewTxOut(amount, toScript))
+	tx.AddTxOut(wire.NewTxOut(0, memoScript))
+
+	fee := estimateFee(relayFeePerKb, len(tx.TxIn), 2, len(args.Memo))
+	change := authoredTx.TotalInput - amount - fee
+	if change < 0 {
+		return nil, errInsufficientFunds
+	}
+	if change >= DustThreshold {
+		authoredTx.ChangeIndex = len(tx.TxOut)
+		tx.AddTxOut(wire.NewTxOut(change, changeScript))
+	} else {
+		fee += change
+	}
+	authoredTx.Fee = fee
+
+	log.Info("build tx success",
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "to", args.To, "bind", args.Bind,
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"inputs", len(tx.TxIn), "totalInput", authoredTx.TotalInput,
+		"fee", fee, "change", change, "relayFeePerKb", relayFeePerKb,
+		"replaceNum", args.GetReplaceNum())
+
+	return authoredTx, nil
+}
+
+// selectUtxos select unspent outputs of address with largest value first
+func (b *Bridge) selectUtxos(address string, amount, relayFeePerKb int64, memoLen int) ([]*prevOutput, error) {
+	utxos, err := b.ListUnspent(address)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := selectUtxos(utxos, amount, relayFeePerKb, memoLen)
+	if err != nil {
+		log.Warn("select utxos failed", "address", address, "amount", amount, "utxos", len(utxos), "err", err)
+	}
+	return selected, err
+}
+
+// selectUtxos select p2pkh unspent outputs in order (sorted by value descending)
+// until they cover amount and fee, at most `MaxTxInputs` outputs are selected
+func selectUtxos(utxos []*UnspentOutput, amount, relayFeePerKb int64, memoLen int) ([]*prevOutput, error) {
+	var total int64
+	selected := make([]*prevOutput, 0)
+	for _, utxo := range utxos {
+		if len(selected) >= MaxTxInputs {
+			break
+		}
+		pkScript := common.FromHex(utxo.ScriptPubKey)
+		if txscript.GetScriptClass(pkScript) != txscript.PubKeyHashTy {
+			continue
+		}
+		selected = append(selected, &prevOutput{
+			outPoint: &tokens.BtcOutPoint{Hash: utxo.TxID, Index: utxo.Vout},
+			pkScript: pkScript,
+			value:    utxo.Value,
+		})
+		total += utxo.Value
+		if total >= amount+estimateFee(relayFeePerKb, len(selected), 2, memoLen) {
+			return selected, nil
+		}
+	}
+	return nil, errInsufficientFunds
+}
+
+// loadPrevOutputs load previous outputs (rebuild tx with specified inputs)
+func (b *Bridge) loadPrevOutputs(address string, outPoints []*tokens.BtcOutPoint) ([]*prevOutput, error) {
+	if len(outPoints) > MaxTxInputs {
+		return nil, fmt.Errorf("too many inputs %v", len(outPoints))
+	}
+	addrScript, err := b.GetPayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+	prevOutputs := make([]*prevOutput, 0, len(outPoints))
+	for _, outPoint := range outPoints {
+		prevTx, err := b.GetMsgTx(outPoint.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if int(outPoint.Index) >= len(prevTx.TxOut) {
+			return nil, fmt.Errorf("previous output index out of range. %v:%v", outPoint.Hash, outPoint.Index)
+		}
+		txOut := prevTx.TxOut[outPoint.Index]
+		if string(txOut.PkScript) != string(addrScript) {
+			return nil, fmt.Errorf("previous output %v:%v is not owned by %v", outPoint.Hash, outPoint.Index, address)
+		}
+		prevOutputs = append(prevOutputs, &prevOutput{
+			outPoint: outPoint,
+			pkScript: txOut.PkScript,
+			value:    txOut.Value,
+		})
+	}
+	return prevOutputs, nil
+}
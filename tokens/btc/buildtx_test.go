@@ -0,0 +1,95 @@
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestEstimateFee(t *testing.T) {
+	testCases := []struct {
+		relayFeePerKb                  int64
+		numInputs, numOutputs, memoLen int
+		want                           int64
+	}{
+		{2000, 1, 2, 0, 452},  // 10 + 148 + 2*34 = 226 bytes
+		{2000, 2, 2, 0, 748},  // 10 + 2*148 + 2*34 = 374 bytes
+		{2000, 2, 2, 20, 810}, // with 11 + 20 bytes of memo output
+		{1000, 3, 3, 0, 556},  // 10 + 3*148 + 3*34 = 556 bytes
+		{500000, 1, 2, 0, 113000},
+	}
+	for _, tc := range testCases {
+		have := estimateFee(tc.relayFeePerKb, tc.numInputs, tc.numOutputs, tc.memoLen)
+		if have != tc.want {
+			t.Errorf("estimate fee %+v: want %v, have %v", tc, tc.want, have)
+		}
+	}
+}
+
+func newTestUtxos(t *testing.T, pkScript []byte, values ...int64) []*UnspentOutput {
+	utxos := make([]*UnspentOutput, len(values))
+	for i, value := range values {
+		utxos[i] = &UnspentOutput{
+			TxID:         fmt.Sprintf("%064x", i+1),
+			ScriptPubKey: fmt.Sprintf("%x", pkScript),
+			Value:        value,
+		}
+	}
+	return utxos
+}
+
+func TestSelectUtxos(t *testing.T) {
+	key, _ := btcec.NewPrivateKey(btcec.S256())
+	p2pkhScript := payToScript(t, p2pkhAddress(key))
+	p2shAddr, _ := btcutil.NewAddressScriptHash(p2pkhScript, &chaincfg.RegressionNetParams)
+	p2shScript, _ := txscript.PayToAddrScript(p2shAddr)
+
+	testCases := []struct {
+		name      string
+		utxos     []*UnspentOutput
+		amount    int64
+		memoLen   int
+		wantCount int
+		wantErr   error
+	}{
+		{"first covers amount and fee", newTestUtxos(t, p2pkhScript, 500000, 300000), 499500, 0, 1, nil},
+		{"first not covers fee", newTestUtxos(t, p2pkhScript, 500000, 300000), 499600, 0, 2, nil},
+		{"memo increases fee", newTestUtxos(t, p2pkhScript, 500000, 300000), 499500, 80, 2, nil},
+		{"select until covered", newTestUtxos(t, p2pkhScript, 500000, 300000, 200000), 600000, 0, 2, nil},
+		{"total not covers fee", newTestUtxos(t, p2pkhScript, 500000, 300000, 200000), 1000000, 0, 0, errInsufficientFunds},
+		{"skip non p2pkh", append(newTestUtxos(t, p2shScript, 900000), newTestUtxos(t, p2pkhScript, 500000)...), 400000, 0, 1, nil},
+		{"only non p2pkh", newTestUtxos(t, p2shScript, 900000), 400000, 0, 0, errInsufficientFunds},
+		{"no utxos", nil, 1000, 0, 0, errInsufficientFunds},
+	}
+	for _, tc := range testCases {
+		selected, err := selectUtxos(tc.utxos, tc.amount, 2000, tc.memoLen)
+		if !errors.Is(err, tc.wantErr) || len(selected) != tc.wantCount {
+			t.Errorf("%v: want %v utxos and error %v, have %v utxos and error %v", tc.name, tc.wantCount, tc.wantErr, len(selected), err)
+			continue
+		}
+		for _, prevOut := range selected {
+			if txscript.GetScriptClass(prevOut.pkScript) != txscript.PubKeyHashTy {
+				t.Errorf("%v: select non p2pkh output %v", tc.name, prevOut.outPoint.Hash)
+			}
+		}
+	}
+
+	// at most MaxTxInputs inputs in one tx
+	values := make([]int64, MaxTxInputs+10)
+	for i := range values {
+		values[i] = 10000
+	}
+	_, err := selectUtxos(newTestUtxos(t, p2pkhScript, values...), int64(MaxTxInputs)*10000, 2000, 0)
+	if !errors.Is(err, errInsufficientFunds) {
+		t.Errorf("select more than max inputs, want error %v, have %v", errInsufficientFunds, err)
+	}
+	selected, err := selectUtxos(newTestUtxos(t, p2pkhScript, values...), int64(MaxTxInputs-10)*10000, 2000, 0)
+	if err != nil || len(selected) > MaxTxInputs {
+		t.Errorf("select below max inputs failed, have %v utxos and error %v", len(selected), err)
+	}
+}
@@ -0,0 +1,194 @@
+package btc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errEmptyURLs = errors.New("empty URLs")
+)
+
+// RPCTransaction verbose result of 'getrawtransaction'
+type RPCTransaction struct {
+	TxID          string `json:"txid"`
+	Hex           string `json:"hex"`
+	BlockHash     string `json:"blockhash,omitempty"`
+	Confirmations uint64 `json:"confirmations,omitempty"`
+	Time          uint64 `json:"time,omitempty"`
+	BlockTime     uint64 `json:"blocktime,omitempty"`
+}
+
+// MsgTx decode raw tx
+func (tx *RPCTransaction) MsgTx() (*wire.MsgTx, error) {
+	return DecodeRawTx(tx.Hex)
+}
+
+// RPCBlockHeader result of 'getblockheader'
+type RPCBlockHeader struct {
+	Hash          string `json:"hash"`
+	Height        uint64 `json:"height"`
+	Time          uint64 `json:"time"`
+	Confirmations int64  `json:"confirmations"`
+}
+
+// UnspentOutput result item of 'listunspent'
+type UnspentOutput struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Address       string  `json:"address"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+	Amount        float64 `json:"amount"`
+	Confirmations int64   `json:"confirmations"`
+
+	Value int64 `json:"-"` // amount in satoshi
+}
+
+// DecodeRawTx decode raw tx from hex string
+func DecodeRawTx(rawHex string) (*wire.MsgTx, error) {
+	data, err := hex.DecodeString(rawHex)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(wire.MsgTx)
+	err = tx.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// EncodeRawTx encode raw tx to hex string
+func EncodeRawTx(tx *wire.MsgTx) (string, error) {
+	var buf bytes.Buffer
+	buf.Grow(tx.SerializeSize())
+	if err := tx.Serialize(&buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+func (b *Bridge) rpcCall(result interface{}, method string, params ...interface{}) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, result, url, method, params...)
+		if err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, method, params...)
+}
+
+// GetBlockCount call getblockcount
+func (b *Bridge) GetBlockCount() (result uint64, err error) {
+	err = b.rpcCall(&result, "getblockcount")
+	return result, err
+}
+
+// GetBlockCountOf call getblockcount of specified url
+func (b *Bridge) GetBlockCountOf(url string) (result uint64, err error) {
+	err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "getblockcount")
+	if err != nil {
+		return 0, wrapRPCQueryError(err, "getblockcount")
+	}
+	return result, nil
+}
+
+// GetBlockHeader call getblockheader
+func (b *Bridge) GetBlockHeader(blockHash string) (*RPCBlockHeader, error) {
+	var result *RPCBlockHeader
+	err := b.rpcCall(&result, "getblockheader", blockHash, true)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, wrapRPCQueryError(nil, "getblockheader", blockHash)
+	}
+	return result, nil
+}
+
+// GetTransactionByHash call getrawtransaction with verbose
+func (b *Bridge) GetTransactionByHash(txHash string) (*RPCTransaction, error) {
+	var result *RPCTransaction
+	err := b.rpcCall(&result, "getrawtransaction", txHash, true)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, tokens.ErrTxNotFound
+	}
+	return result, nil
+}
+
+// GetMsgTx get decoded transaction by hash
+func (b *Bridge) GetMsgTx(txHash string) (*wire.MsgTx, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MsgTx()
+}
+
+// ListUnspent call listunspent of address (sorted by value descending)
+func (b *Bridge) ListUnspent(address string) ([]*UnspentOutput, error) {
+	var result []*UnspentOutput
+	err := b.rpcCall(&result, "listunspent", 1, 9999999, []string{address})
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range result {
+		amount, errt := btcutil.NewAmount(utxo.Amount)
+		if errt != nil {
+			return nil, errt
+		}
+		utxo.Value = int64(amount)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		if result[i].TxID != result[j].TxID {
+			return result[i].TxID < result[j].TxID
+		}
+		return result[i].Vout < result[j].Vout
+	})
+	return result, nil
+}
+
+// EstimateFeePerKb call estimatesmartfee (in satoshi)
+func (b *Bridge) EstimateFeePerKb(blocks int) (int64, error) {
+	var result struct {
+		FeeRate float64  `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}
+	err := b.rpcCall(&result, "estimatesmartfee", blocks)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Errors) > 0 || result.FeeRate <= 0 {
+		return 0, wrapRPCQueryError(errors.New("no fee rate estimated"), "estimatesmartfee", blocks)
+	}
+	amount, err := btcutil.NewAmount(result.FeeRate)
+	if err != nil {
+		return 0, err
+	}
+	return int64(amount), nil
+}
+
+// SendRawTransaction call sendrawtransaction
+func (b *Bridge) SendRawTransaction(rawHex string) (txHash string, err error) {
+	err = b.rpcCall(&txHash, "sendrawtransaction", rawHex)
+	return txHash, err
+}
@@ -0,0 +1,87 @@
+package btc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// network names
+const (
+	MainNet = "mainnet"
+	TestNet = "testnet"
+	RegTest = "regtest"
+)
+
+var (
+	// LtcMainNetParams litecoin mainnet params
+	LtcMainNetParams = newChainParams(&chaincfg.MainNetParams, "litecoin", 0xdbb6c0fb, 0x30, 0x32, 0xb0, "ltc")
+	// LtcTestNetParams litecoin testnet4 params
+	LtcTestNetParams = newChainParams(&chaincfg.TestNet3Params, "litecoin-testnet4", 0xf1c8d2fd, 0x6f, 0x3a, 0xef, "tltc")
+	// DogeMainNetParams dogecoin mainnet params
+	DogeMainNetParams = newChainParams(&chaincfg.MainNetParams, "dogecoin", 0xc0c0c0c0, 0x1e, 0x16, 0x9e, "")
+	// DogeTestNetParams dogecoin testnet params
+	DogeTestNetParams = newChainParams(&chaincfg.TestNet3Params, "dogecoin-testnet", 0xfcc1b7dc, 0x71, 0xc4, 0xf1, "")
+
+	// key is coin, then network
+	allChainParams = map[string]map[string]*chaincfg.Params{
+		"btc": {
+			MainNet: &chaincfg.MainNetParams,
+			TestNet: &chaincfg.TestNet3Params,
+			RegTest: &chaincfg.RegressionNetParams,
+		},
+		"ltc": {
+			MainNet: LtcMainNetParams,
+			TestNet: LtcTestNetParams,
+		},
+		"doge": {
+			MainNet: DogeMainNetParams,
+			TestNet: DogeTestNetParams,
+		},
+	}
+
+	coinAliases = map[string]string{
+		"btc":      "btc",
+		"bitcoin":  "btc",
+		"ltc":      "ltc",
+		"litecoin": "ltc",
+		"doge":     "doge",
+		"dogecoin": "doge",
+	}
+)
+
+func init() {
+	for _, p := range []*chaincfg.Params{LtcMainNetParams, LtcTestNetParams, DogeMainNetParams, DogeTestNetParams} {
+		// register to recognize address prefixes when decoding
+		_ = chaincfg.Register(p)
+	}
+}
+
+func newChainParams(base *chaincfg.Params, name string, net wire.BitcoinNet, pubKeyHashAddrID, scriptHashAddrID, privateKeyID byte, bech32HRP string) *chaincfg.Params {
+	p := *base
+	p.Name = name
+	p.Net = net
+	p.PubKeyHashAddrID = pubKeyHashAddrID
+	p.ScriptHashAddrID = scriptHashAddrID
+	p.PrivateKeyID = privateKeyID
+	p.Bech32HRPSegwit = bech32HRP
+	return &p
+}
+
+// GetChainParams get chain params by block chain name and network (default mainnet)
+func GetChainParams(blockChain, network string) (*chaincfg.Params, error) {
+	coin, exist := coinAliases[strings.ToLower(blockChain)]
+	if !exist {
+		return nil, fmt.Errorf("unsupported utxo block chain '%v'", blockChain)
+	}
+	if network == "" {
+		network = MainNet
+	}
+	chainParams, exist := allChainParams[coin][strings.ToLower(network)]
+	if !exist {
+		return nil, fmt.Errorf("unsupported network '%v' of block chain '%v'", network, blockChain)
+	}
+	return chainParams, nil
+}
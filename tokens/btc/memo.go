@@ -0,0 +1,28 @@
+package btc
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// GetNullDataMemo get memo of OP_RETURN output script
+func GetNullDataMemo(pkScript []byte) (memo string, ok bool) {
+	if txscript.GetScriptClass(pkScript) != txscript.NullDataTy {
+		return "", false
+	}
+	pushes, err := txscript.PushedData(pkScript)
+	if err != nil || len(pushes) == 0 {
+		return "", false
+	}
+	var sb strings.Builder
+	for _, push := range pushes {
+		_, _ = sb.Write(push)
+	}
+	return sb.String(), true
+}
+
+// NullDataScript build OP_RETURN script of memo
+func NullDataScript(memo string) ([]byte, error) {
+	return txscript.NullDataScript([]byte(memo))
+}
@@ -0,0 +1,96 @@
+package btc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestGetNullDataMemo(t *testing.T) {
+	key, _ := btcec.NewPrivateKey(btcec.S256())
+	oneData, _ := NullDataScript("bind:56")
+	multiData, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).
+		AddData([]byte("bind")).AddData([]byte(":56")).Script()
+	emptyData, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).Script()
+
+	testCases := []struct {
+		name     string
+		pkScript []byte
+		wantMemo string
+		wantOk   bool
+	}{
+		{"one push", oneData, "bind:56", true},
+		{"multiple pushes are not standard", multiData, "", false},
+		{"no push", emptyData, "", false},
+		{"not null data", payToScript(t, p2pkhAddress(key)), "", false},
+	}
+	for _, tc := range testCases {
+		memo, ok := GetNullDataMemo(tc.pkScript)
+		if memo != tc.wantMemo || ok != tc.wantOk {
+			t.Errorf("%v: want memo '%v' %v, have '%v' %v", tc.name, tc.wantMemo, tc.wantOk, memo, ok)
+		}
+	}
+}
+
+func TestParseSwapoutTx(t *testing.T) {
+	depositKey, _ := btcec.NewPrivateKey(btcec.S256())
+	otherKey, _ := btcec.NewPrivateKey(btcec.S256())
+	depositAddr := p2pkhAddress(depositKey)
+	b := newTestBridge(tFromChain, "", depositAddr)
+
+	newTx := func(memos []string, values ...int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+		for _, value := range values {
+			tx.AddTxOut(wire.NewTxOut(value, payToScript(t, depositAddr)))
+		}
+		for _, memo := range memos {
+			memoScript, _ := NullDataScript(memo)
+			tx.AddTxOut(wire.NewTxOut(0, memoScript))
+		}
+		tx.AddTxOut(wire.NewTxOut(5000, payToScript(t, p2pkhAddress(otherKey))))
+		return tx
+	}
+
+	testCases := []struct {
+		name      string
+		tx        *wire.MsgTx
+		wantErr   error
+		wantValue int64
+		wantBind  string
+		wantTo    string
+	}{
+		{"one deposit", newTx([]string{"bind:2000"}, 1000), nil, 1000, "bind", "2000"},
+		{"deposits are summed", newTx([]string{"bind:2000"}, 1000, 2000), nil, 3000, "bind", "2000"},
+		{"first memo is used", newTx([]string{"bind:2000", "other:3000"}, 1000), nil, 1000, "bind", "2000"},
+		{"bind with separator", newTx([]string{"a:b:2000"}, 1000), nil, 1000, "a:b", "2000"},
+		{"no deposit", newTx([]string{"bind:2000"}), tokens.ErrSwapoutLogNotFound, 0, "", ""},
+		{"no memo", newTx(nil, 1000), tokens.ErrTxWithoutMemo, 1000, "", ""},
+		{"memo without chainID", newTx([]string{"bind"}, 1000), tokens.ErrTxWithWrongMemo, 1000, "", ""},
+		{"memo with zero chainID", newTx([]string{"bind:0"}, 1000), tokens.ErrTxWithWrongMemo, 1000, "", ""},
+		{"memo with wrong chainID", newTx([]string{"bind:abc"}, 1000), tokens.ErrTxWithWrongMemo, 1000, "", ""},
+	}
+	for _, tc := range testCases {
+		swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+		err := b.parseSwapoutTx(swapInfo, tc.tx)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%v: want error %v, have %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if tc.wantValue != 0 && (swapInfo.Value == nil || swapInfo.Value.Int64() != tc.wantValue) {
+			t.Errorf("%v: want value %v, have %v", tc.name, tc.wantValue, swapInfo.Value)
+		}
+		if err != nil {
+			continue
+		}
+		if swapInfo.Bind != tc.wantBind || swapInfo.ToChainID.String() != tc.wantTo ||
+			swapInfo.To != depositAddr || swapInfo.ERC20SwapInfo.TokenID != tTokenID {
+			t.Errorf("%v: wrong swap info %+v", tc.name, swapInfo)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package btc
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*wire.MsgTx)
+	if !ok {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	rawHex, err := EncodeRawTx(tx)
+	if err != nil {
+		return "", err
+	}
+	txHash, err = b.SendRawTransaction(rawHex)
+	if err != nil {
+		log.Info("SendTransaction failed", "hash", tx.TxHash().String(), "err", err)
+	} else {
+		log.Info("SendTransaction success", "hash", txHash)
+	}
+	if params.IsDebugMode() {
+		log.Infof("SendTransaction rawtx is %v", rawHex)
+	}
+	return txHash, err
+}
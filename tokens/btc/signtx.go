@@ -0,0 +1,163 @@
+package btc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, args *tokens.BuildTxArgs) (*AuthoredTx, error) {
+	authoredTx, ok := rawTx.(*AuthoredTx)
+	if !ok || authoredTx.Tx == nil || len(authoredTx.Tx.TxOut) == 0 {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	checkReceiver, err := b.GetPayToAddrScript(args.Bind)
+	if err != nil {
+		return nil, err
+	}
+	if string(authoredTx.Tx.TxOut[0].PkScript) != string(checkReceiver) {
+		return nil, fmt.Errorf("[sign] tx receiver mismatch. want %v", args.Bind)
+	}
+	return authoredTx, nil
+}
+
+// MPCSignTransaction mpc sign raw tx (one msg hash per input)
+func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	authoredTx, err := b.verifyTransactionReceiver(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SignWithPrivateKey() {
+		priKey := params.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+	}
+
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	pubKey, err := btcec.ParsePubKey(common.FromHex(mpcPubkey), btcec.S256())
+	if err != nil {
+		return nil, "", err
+	}
+
+	sigHashes, err := authoredTx.SigHashes()
+	if err != nil {
+		return nil, "", err
+	}
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+	msgHashes := make([]string, len(sigHashes))
+	msgContexts := make([]string, len(sigHashes))
+	for i, sigHash := range sigHashes {
+		msgHashes[i] = common.ToHex(sigHash)
+		msgContexts[i] = msgContext
+	}
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "msghashes", msgHashes)
+	keyID, rsvs, err := mpc.DoSign(mpc.SignTypeEC256K1, mpcPubkey, msgHashes, msgContexts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghashes", msgHashes)
+
+	if len(rsvs) != len(msgHashes) {
+		log.Warn("get sign status rsvs count mismatch",
+			"rsvs", len(rsvs), "msghashes", len(msgHashes), "keyID", keyID, "txid", txid)
+		return nil, "", errors.New("get sign status rsvs count mismatch")
+	}
+
+	signatures := make([][]byte, len(rsvs))
+	for i, rsv := range rsvs {
+		signature := common.FromHex(rsv)
+		if len(signature) != crypto.SignatureLength {
+			log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
+			return nil, "", errors.New("wrong signature length")
+		}
+		signatures[i] = signature
+	}
+
+	signedTx, err := b.signTxWithSignatures(authoredTx, signatures, pubKey, sigHashes)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash = signedTx.TxHash().String()
+	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash)
+	return signedTx, txHash, nil
+}
+
+// SignTransactionWithPrivateKey sign tx with ECDSA private key
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
+	authoredTx, ok := rawTx.(*AuthoredTx)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	ecPriKey, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		return nil, "", err
+	}
+	privKey := (*btcec.PrivateKey)(ecPriKey)
+
+	sigHashes, err := authoredTx.SigHashes()
+	if err != nil {
+		return nil, "", err
+	}
+	signatures := make([][]byte, len(sigHashes))
+	for i, sigHash := range sigHashes {
+		signatures[i], err = crypto.Sign(sigHash, ecPriKey)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	signedTx, err := b.signTxWithSignatures(authoredTx, signatures, privKey.PubKey(), sigHashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return signedTx, signedTx.TxHash().String(), nil
+}
+
+// signTxWithSignatures fill in signature scripts of p2pkh inputs with rsv signatures
+func (b *Bridge) signTxWithSignatures(authoredTx *AuthoredTx, signatures [][]byte, pubKey *btcec.PublicKey, sigHashes [][]byte) (*wire.MsgTx, error) {
+	tx := authoredTx.Tx.Copy()
+	if len(signatures) != len(tx.TxIn) || len(sigHashes) != len(tx.TxIn) {
+		return nil, tokens.ErrWrongCountOfMsgHashes
+	}
+	pubKeyData := pubKey.SerializeCompressed()
+	for i, rsv := range signatures {
+		sig := &btcec.Signature{
+			R: new(big.Int).SetBytes(rsv[:32]),
+			S: new(big.Int).SetBytes(rsv[32:64]),
+		}
+		if !sig.Verify(sigHashes[i], pubKey) {
+			return nil, fmt.Errorf("verify signature of input %v failed", i)
+		}
+		sigData := append(sig.Serialize(), byte(txscript.SigHashAll))
+		sigScript, err := txscript.NewScriptBuilder().AddData(sigData).AddData(pubKeyData).Script()
+		if err != nil {
+			return nil, err
+		}
+		tx.TxIn[i].SignatureScript = sigScript
+	}
+	return tx, nil
+}
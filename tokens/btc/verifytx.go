@@ -0,0 +1,227 @@
+package btc
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	txStatus := &tokens.TxStatus{}
+	if tx.BlockHash == "" {
+		return txStatus, nil
+	}
+	header, err := b.GetBlockHeader(tx.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	txStatus.BlockHash = tx.BlockHash
+	txStatus.BlockHeight = header.Height
+	txStatus.BlockTime = header.Time
+	txStatus.Confirmations = tx.Confirmations
+	return txStatus, nil
+}
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	authoredTx, ok := rawTx.(*AuthoredTx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	sigHashes, err := authoredTx.SigHashes()
+	if err != nil {
+		return err
+	}
+	if len(sigHashes) != len(msgHashes) {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	for i, sigHash := range sigHashes {
+		if !strings.EqualFold(common.ToHex(sigHash), msgHashes[i]) {
+			log.Trace("message hash mismatch", "index", i, "want", msgHashes[i], "have", common.ToHex(sigHash))
+			return tokens.ErrMsgHashMismatch
+		}
+	}
+	return nil
+}
+
+// VerifyTransaction api
+func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	return b.verifySwapoutTx(txHash, args.LogIndex, args.AllowUnstable)
+}
+
+func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ERC20SwapType // SwapType
+	swapInfo.Hash = strings.ToLower(txHash)  // Hash
+	swapInfo.LogIndex = logIndex             // LogIndex
+
+	// utxo tx has only one swapout at index 0
+	if logIndex != 0 {
+		return swapInfo, tokens.ErrLogIndexOutOfRange
+	}
+
+	tx, err := b.getStableSwapTx(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.parseSwapoutTx(swapInfo, tx)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapoutInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify router swap tx stable pass",
+			"identifier", params.GetIdentifier(),
+			"from", swapInfo.From, "to", swapInfo.To,
+			"bind", swapInfo.Bind, "value", swapInfo.Value,
+			"txid", txHash, "logIndex", logIndex,
+			"height", swapInfo.Height, "timestamp", swapInfo.Timestamp,
+			"fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID,
+			"token", swapInfo.ERC20SwapInfo.Token, "tokenID", swapInfo.ERC20SwapInfo.TokenID)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) getStableSwapTx(swapInfo *tokens.SwapTxInfo, allowUnstable bool) (*wire.MsgTx, error) {
+	txStatus, err := b.GetTransactionStatus(swapInfo.Hash)
+	if err != nil {
+		log.Error("get tx status failed", "hash", swapInfo.Hash, "err", err)
+		return nil, err
+	}
+	if txStatus.BlockHeight == 0 {
+		return nil, tokens.ErrTxNotFound
+	}
+	if txStatus.BlockHeight < b.ChainConfig.InitialHeight {
+		return nil, tokens.ErrTxBeforeInitialHeight
+	}
+
+	swapInfo.Height = txStatus.BlockHeight  // Height
+	swapInfo.Timestamp = txStatus.BlockTime // Timestamp
+
+	if !allowUnstable && txStatus.Confirmations < b.ChainConfig.Confirmations {
+		return nil, tokens.ErrTxNotStable
+	}
+
+	return b.GetMsgTx(swapInfo.Hash)
+}
+
+func (b *Bridge) parseSwapoutTx(swapInfo *tokens.SwapTxInfo, tx *wire.MsgTx) (err error) {
+	depositAddress := b.ChainConfig.RouterContract
+	tokenCfg := b.GetTokenConfig(NativeToken)
+	if tokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+
+	value := big.NewInt(0)
+	memo := ""
+	for _, txOut := range tx.TxOut {
+		if nullData, ok := GetNullDataMemo(txOut.PkScript); ok {
+			if memo == "" {
+				memo = nullData
+			}
+			continue
+		}
+		_, addrs, _, errt := txscript.ExtractPkScriptAddrs(txOut.PkScript, b.ChainParams)
+		if errt != nil || len(addrs) != 1 {
+			continue
+		}
+		if addrs[0].EncodeAddress() == depositAddress {
+			value.Add(value, big.NewInt(txOut.Value))
+		}
+	}
+	if value.Sign() == 0 {
+		return tokens.ErrSwapoutLogNotFound
+	}
+
+	swapInfo.TxTo = depositAddress                    // TxTo
+	swapInfo.To = depositAddress                      // To
+	swapInfo.Value = value                            // Value
+	swapInfo.From = b.getTxSender(tx)                 // From
+	swapInfo.FromChainID = b.ChainConfig.GetChainID() // FromChainID
+
+	swapInfo.ERC20SwapInfo.Token = NativeToken        // Token
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID // TokenID
+
+	if memo == "" {
+		return tokens.ErrTxWithoutMemo
+	}
+	swapInfo.Bind, swapInfo.ToChainID, err = tokens.ParseSwapoutMemo(memo) // Bind, ToChainID
+	if err != nil {
+		log.Warn("parse swapout memo failed", "txid", swapInfo.Hash, "memo", memo, "err", err)
+		return tokens.ErrTxWithWrongMemo
+	}
+	return nil
+}
+
+// getTxSender get address of the first input's previous output
+func (b *Bridge) getTxSender(tx *wire.MsgTx) string {
+	if len(tx.TxIn) == 0 {
+		return ""
+	}
+	prevOut := tx.TxIn[0].PreviousOutPoint
+	prevTx, err := b.GetMsgTx(prevOut.Hash.String())
+	if err != nil || int(prevOut.Index) >= len(prevTx.TxOut) {
+		log.Warn("get tx sender failed", "prevTx", prevOut.Hash, "index", prevOut.Index, "err", err)
+		return ""
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(prevTx.TxOut[prevOut.Index].PkScript, b.ChainParams)
+	if err != nil || len(addrs) == 0 {
+		return ""
+	}
+	return addrs[0].EncodeAddress()
+}
+
+func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
+	if swapInfo.FromChainID.Cmp(swapInfo.ToChainID) == 0 {
+		return tokens.ErrSameFromAndToChainID
+	}
+	erc20SwapInfo := swapInfo.ERC20SwapInfo
+	fromTokenCfg := b.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil || erc20SwapInfo.TokenID == "" {
+		return tokens.ErrMissTokenConfig
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, swapInfo.ToChainID.String())
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", swapInfo.ToChainID, "txid", swapInfo.Hash)
+		return tokens.ErrMissTokenConfig
+	}
+	toBridge := router.GetBridgeByChainID(swapInfo.ToChainID.String())
+	if toBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	toTokenCfg := toBridge.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", swapInfo.ToChainID, "token", multichainToken)
+		return tokens.ErrMissTokenConfig
+	}
+	if !tokens.CheckTokenSwapValue(swapInfo, fromTokenCfg.Decimals, toTokenCfg.Decimals) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !toBridge.IsValidAddress(swapInfo.Bind) {
+		log.Warn("wrong bind address in swapout", "txid", swapInfo.Hash, "bind", swapInfo.Bind)
+		return tokens.ErrWrongBindAddress
+	}
+	return nil
+}
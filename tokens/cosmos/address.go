@@ -0,0 +1,70 @@
+package cosmos
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// DecodeAddress decode bech32 address of this chain
+func (b *Bridge) DecodeAddress(address string) ([]byte, error) {
+	hrp, data, err := bech32.Decode(address)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != b.Bech32Prefix {
+		return nil, fmt.Errorf("address '%v' has prefix '%v', want '%v'", address, hrp, b.Bech32Prefix)
+	}
+	return bech32.ConvertBits(data, 5, 8, false)
+}
+
+// EncodeAddress encode address bytes to bech32 address
+func EncodeAddress(prefix string, addr []byte) (string, error) {
+	data, err := bech32.ConvertBits(addr, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(prefix, data)
+}
+
+// IsValidAddress check address (both account and contract address)
+func (b *Bridge) IsValidAddress(address string) bool {
+	addr, err := b.DecodeAddress(address)
+	if err != nil {
+		return false
+	}
+	return len(addr) == 20 || len(addr) == 32
+}
+
+// GetCompressedPublicKey get compressed secp256k1 public key
+func GetCompressedPublicKey(pubKeyHex string) ([]byte, error) {
+	pubKey, err := btcec.ParsePubKey(common.FromHex(pubKeyHex), btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.SerializeCompressed(), nil
+}
+
+// PublicKeyToAddress returns bech32 address of secp256k1 public key
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	pubKey, err := GetCompressedPublicKey(pubKeyHex)
+	if err != nil {
+		return "", err
+	}
+	return EncodeAddress(b.Bech32Prefix, btcutil.Hash160(pubKey))
+}
+
+// VerifyMPCPubKey verify mpc address and public key is matching
+func (b *Bridge) VerifyMPCPubKey(mpcAddress, mpcPubkey string) error {
+	address, err := b.PublicKeyToAddress(mpcPubkey)
+	if err != nil {
+		return err
+	}
+	if address != mpcAddress {
+		return fmt.Errorf("mpc address %v and public key address %v is not match", mpcAddress, address)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Package cosmos implements the bridge interfaces for cosmos sdk based chains.
+//
+// Swapout is a bank send (MsgSend) or a cw20 transfer (MsgExecuteContract)
+// to the mpc address, with memo 'bind:toChainID'. Swapin is built as
+// protobuf tx with sign mode direct. The rest api (lcd) of the chain is
+// configed in GatewayConfig.APIAddress.
+package cosmos
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ensure Bridge impl tokens.CrossChainBridge
+	_ tokens.IBridge = &Bridge{}
+)
+
+const (
+	// DefaultBech32Prefix default bech32 address prefix
+	DefaultBech32Prefix = "cosmos"
+	// DefaultGasLimit default gas limit of swapin tx
+	DefaultGasLimit uint64 = 200000
+)
+
+func init() {
+	tokens.RegisterBridge("cosmos", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	}, "cosmoshub", "osmosis", "juno", "terra")
+}
+
+// Bridge cosmos bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	RPCClientTimeout int
+	Bech32Prefix     string
+	GasLimit         uint64
+	DefaultFee       string // coin string like '5000uatom'
+
+	cosmosChainID string
+}
+
+// NewCrossChainBridge new bridge
+func NewCrossChainBridge() *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(),
+		RPCClientTimeout:     client.GetDefaultTimeout(false),
+		Bech32Prefix:         DefaultBech32Prefix,
+		GasLimit:             DefaultGasLimit,
+	}
+}
+
+// InitAfterConfig init variables (ie. extra members) after loading config
+func (b *Bridge) InitAfterConfig() {
+	isReload := router.IsReloading
+	logErrFunc := log.GetLogFuncOr(isReload, log.Error, log.Fatal)
+	chainID := b.ChainConfig.ChainID
+
+	if prefix := params.GetCustom(chainID, "bech32Prefix"); prefix != "" {
+		b.Bech32Prefix = prefix
+	}
+	if gasLimitStr := params.GetCustom(chainID, "gasLimit"); gasLimitStr != "" {
+		gasLimit, err := strconv.ParseUint(gasLimitStr, 10, 64)
+		if err != nil {
+			logErrFunc("wrong custom 'gasLimit'", "chainID", chainID, "value", gasLimitStr, "err", err)
+			return
+		}
+		b.GasLimit = gasLimit
+	}
+	b.DefaultFee = params.GetCustom(chainID, "fee")
+	if _, err := ParseCoin(b.DefaultFee); err != nil {
+		logErrFunc("cosmos bridge must config custom 'fee' (eg. 5000uatom)", "chainID", chainID, "err", err)
+		return
+	}
+	b.cosmosChainID = params.GetCustom(chainID, "cosmosChainID")
+	if timeout := params.GetRPCClientTimeout(chainID); timeout != 0 {
+		b.RPCClientTimeout = timeout
+	}
+	log.Info("init cosmos bridge success", "chainID", chainID, "blockChain", b.ChainConfig.BlockChain,
+		"bech32Prefix", b.Bech32Prefix, "gasLimit", b.GasLimit, "fee", b.DefaultFee)
+}
+
+// GetCosmosChainID get chain id string (eg. cosmoshub-4) used in sign doc
+func (b *Bridge) GetCosmosChainID() (string, error) {
+	if b.cosmosChainID != "" {
+		return b.cosmosChainID, nil
+	}
+	header, err := b.GetLatestBlockHeader()
+	if err != nil {
+		return "", err
+	}
+	if header.ChainID == "" {
+		return "", wrapRPCQueryError(nil, "chain_id")
+	}
+	b.cosmosChainID = header.ChainID
+	return b.cosmosChainID, nil
+}
+
+// InitRouterInfo init router info.
+// the router contract of cosmos chain is the mpc address.
+func (b *Bridge) InitRouterInfo(routerContract string) (err error) {
+	if routerContract == "" {
+		return nil
+	}
+	chainID := b.ChainConfig.ChainID
+	log.Info(fmt.Sprintf("[%5v] start init router info", chainID), "routerContract", routerContract)
+	if !b.IsValidAddress(routerContract) {
+		return fmt.Errorf("wrong router mpc address '%v'", routerContract)
+	}
+	routerMPC := routerContract
+	routerMPCPubkey, err := router.GetMPCPubkey(routerMPC)
+	if err != nil {
+		log.Warn("get mpc public key failed", "mpc", routerMPC, "err", err)
+		return err
+	}
+	if err = b.VerifyMPCPubKey(routerMPC, routerMPCPubkey); err != nil {
+		log.Warn("verify mpc public key failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
+		return err
+	}
+	router.SetRouterInfo(
+		routerContract,
+		&router.SwapRouterInfo{
+			RouterMPC: routerMPC,
+		},
+	)
+	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+
+	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
+		"routerContract", routerContract, "routerMPC", routerMPC)
+	return nil
+}
+
+// GetBalance get balance of fee denom
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	fee, err := ParseCoin(b.DefaultFee)
+	if err != nil {
+		return nil, err
+	}
+	coin, err := b.GetDenomBalance(account, fee.Denom)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := new(big.Int).SetString(coin.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("wrong balance '%v'", coin.Amount)
+	}
+	return balance, nil
+}
+
+// IsContractAddress token address is cw20 contract (otherwise it's a denom)
+func (b *Bridge) IsContractAddress(token string) bool {
+	addr, err := b.DecodeAddress(token)
+	return err == nil && len(addr) > 0
+}
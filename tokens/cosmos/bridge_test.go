@@ -0,0 +1,309 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	tTokenID   = "ATOM"
+	tDenom     = "uatom"
+	tFromChain = "1100"
+	tToChain   = "1200"
+	tTxHeight  = 990
+	tTxHash    = "2E4A5A30B4E1A0C7D8F5D0B6C0F2D3A1B4C5D6E7F8091A2B3C4D5E6F708192A3"
+)
+
+func TestParseCoin(t *testing.T) {
+	coin, err := ParseCoin("5000uatom")
+	if err != nil || coin.Denom != "uatom" || coin.Amount != "5000" {
+		t.Fatalf("parse coin failed. coin %v err %v", coin, err)
+	}
+	for _, wrong := range []string{"", "uatom", "5000", "-5uatom", "5000 uatom"} {
+		if _, err := ParseCoin(wrong); err == nil {
+			t.Errorf("parse wrong coin '%v' should fail", wrong)
+		}
+	}
+	coin = &Coin{Denom: "uatom", Amount: "10"}
+	if have := hex.EncodeToString(coin.marshal()); have != "0a057561746f6d12023130" {
+		t.Errorf("marshal coin failed. have %v", have)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	b := NewCrossChainBridge()
+	ecKey, _ := crypto.GenerateKey()
+	pubKey := (*btcec.PublicKey)(&ecKey.PublicKey)
+	address, err := b.PublicKeyToAddress(common.ToHex(pubKey.SerializeUncompressed()))
+	if err != nil {
+		t.Fatalf("public key to address failed. err %v", err)
+	}
+	compressedAddress, _ := b.PublicKeyToAddress(common.ToHex(pubKey.SerializeCompressed()))
+	if address != compressedAddress {
+		t.Errorf("address of uncompressed and compressed public key mismatch. %v %v", address, compressedAddress)
+	}
+	addr, err := b.DecodeAddress(address)
+	if err != nil || !bytes.Equal(addr, btcutil.Hash160(pubKey.SerializeCompressed())) {
+		t.Fatalf("decode address failed. err %v", err)
+	}
+	if !b.IsValidAddress(address) {
+		t.Errorf("address %v should be valid", address)
+	}
+	b.Bech32Prefix = "osmo"
+	if b.IsValidAddress(address) {
+		t.Errorf("address %v with other prefix should be invalid", address)
+	}
+}
+
+// stubLCD serves rest api by url path
+type stubLCD struct {
+	results   map[string]interface{}
+	broadcast []byte
+}
+
+func (s *stubLCD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		var req struct {
+			TxBytes []byte `json:"tx_bytes"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.broadcast = req.TxBytes
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"tx_response": map[string]interface{}{"txhash": tTxHash, "code": 0},
+		})
+		return
+	}
+	if result, exist := s.results[r.URL.Path]; exist {
+		_ = json.NewEncoder(w).Encode(result)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+type testEnv struct {
+	lcd        *stubLCD
+	srcBridge  *Bridge
+	dstBridge  *Bridge
+	mpcKey     *btcec.PrivateKey
+	mpcAddress string
+	userAddr   string
+}
+
+func newTestBridge(chainID, url, routerContract string) *Bridge {
+	b := NewCrossChainBridge()
+	b.DefaultFee = "5000" + tDenom
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{url}})
+	chainCfg := &tokens.ChainConfig{
+		ChainID:        chainID,
+		BlockChain:     "cosmoshub",
+		RouterContract: routerContract,
+		Confirmations:  3,
+	}
+	_ = chainCfg.CheckConfig()
+	b.SetChainConfig(chainCfg)
+	b.SetTokenConfig(tDenom, &tokens.TokenConfig{
+		TokenID:         tTokenID,
+		Decimals:        6,
+		ContractAddress: tDenom,
+	})
+	return b
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{lcd: &stubLCD{results: make(map[string]interface{})}}
+	server := httptest.NewServer(env.lcd)
+	t.Cleanup(server.Close)
+
+	ecKey, _ := crypto.GenerateKey()
+	env.mpcKey = (*btcec.PrivateKey)(ecKey)
+	userKey, _ := crypto.GenerateKey()
+	b := NewCrossChainBridge()
+	env.mpcAddress, _ = b.PublicKeyToAddress(common.ToHex(crypto.FromECDSAPub(&ecKey.PublicKey)))
+	env.userAddr, _ = b.PublicKeyToAddress(common.ToHex(crypto.FromECDSAPub(&userKey.PublicKey)))
+
+	env.srcBridge = newTestBridge(tFromChain, server.URL, env.mpcAddress)
+	env.dstBridge = newTestBridge(tToChain, server.URL, env.mpcAddress)
+	router.SetBridge(tFromChain, env.srcBridge)
+	router.SetBridge(tToChain, env.dstBridge)
+	router.SetMultichainToken(tTokenID, tFromChain, tDenom)
+	router.SetMultichainToken(tTokenID, tToChain, tDenom)
+	router.SetRouterInfo(env.mpcAddress, &router.SwapRouterInfo{RouterMPC: env.mpcAddress})
+	router.SetMPCPublicKey(env.mpcAddress, common.ToHex(crypto.FromECDSAPub(&ecKey.PublicKey)))
+
+	tokens.InitRouterSwapType("erc20swap")
+	oneATOM := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	swapCfg := &tokens.SwapConfig{
+		MaximumSwap:           new(big.Int).Mul(oneATOM, big.NewInt(100)),
+		MinimumSwap:           new(big.Int).Div(oneATOM, big.NewInt(10000)),
+		BigValueThreshold:     new(big.Int).Mul(oneATOM, big.NewInt(10)),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        new(big.Int).Div(oneATOM, big.NewInt(100)),
+		MinimumSwapFee:        new(big.Int).Div(oneATOM, big.NewInt(100000)),
+	}
+	swapConfig := new(sync.Map)
+	swapConfig.Store(tFromChain, swapCfg)
+	swapConfig.Store(tToChain, swapCfg)
+	swapConfigs := new(sync.Map)
+	swapConfigs.Store(tTokenID, swapConfig)
+	tokens.SetSwapConfigs(swapConfigs)
+
+	env.lcd.results["/cosmos/base/tendermint/v1beta1/blocks/latest"] = map[string]interface{}{
+		"block": map[string]interface{}{
+			"header": map[string]interface{}{"chain_id": "cosmoshub-4", "height": "1000"},
+		},
+	}
+	env.lcd.results["/cosmos/auth/v1beta1/accounts/"+env.mpcAddress] = map[string]interface{}{
+		"account": map[string]interface{}{
+			"@type":          "/cosmos.auth.v1beta1.BaseAccount",
+			"address":        env.mpcAddress,
+			"account_number": "12",
+			"sequence":       "7",
+		},
+	}
+	return env
+}
+
+func (env *testEnv) setSwapoutTx(memo string, code int, messages ...map[string]interface{}) {
+	env.lcd.results["/cosmos/tx/v1beta1/txs/"+strings.ToLower(tTxHash)] = map[string]interface{}{
+		"tx": map[string]interface{}{
+			"body": map[string]interface{}{"messages": messages, "memo": memo},
+		},
+		"tx_response": map[string]interface{}{
+			"height":    "990",
+			"txhash":    tTxHash,
+			"code":      code,
+			"timestamp": "2021-10-01T00:00:00Z",
+		},
+	}
+}
+
+func (env *testEnv) sendMsg(to, amount string) map[string]interface{} {
+	return map[string]interface{}{
+		"@type":        MsgSendTypeURL,
+		"from_address": env.userAddr,
+		"to_address":   to,
+		"amount":       []map[string]string{{"denom": tDenom, "amount": amount}},
+	}
+}
+
+func TestRegisterAndVerifySwap(t *testing.T) {
+	env := newTestEnv(t)
+	b := env.srcBridge
+	memo := tokens.EncodeSwapoutMemo(env.userAddr, big.NewInt(1200))
+	args := &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType}
+
+	env.setSwapoutTx(memo, 0, env.sendMsg(env.userAddr, "1"), env.sendMsg(env.mpcAddress, "1000000"))
+	args.LogIndex = 1
+	swapInfos, errs := b.RegisterSwap(tTxHash, args)
+	if errs[0] != nil {
+		t.Fatalf("register swap failed. err %v", errs[0])
+	}
+	swapInfo := swapInfos[0]
+	if swapInfo.From != env.userAddr || swapInfo.Bind != env.userAddr ||
+		swapInfo.ToChainID.String() != tToChain || swapInfo.Value.String() != "1000000" ||
+		swapInfo.ERC20SwapInfo.TokenID != tTokenID || swapInfo.Height != tTxHeight {
+		t.Fatalf("wrong swap info %+v", swapInfo)
+	}
+
+	args.LogIndex = 0
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrTxWithWrongReceiver {
+		t.Errorf("register swap to wrong receiver. have %v want %v", errs[0], tokens.ErrTxWithWrongReceiver)
+	}
+	args.LogIndex = 2
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrLogIndexOutOfRange {
+		t.Errorf("register swap with wrong log index. have %v want %v", errs[0], tokens.ErrLogIndexOutOfRange)
+	}
+
+	env.setSwapoutTx("wrong-memo", 0, env.sendMsg(env.mpcAddress, "1000000"))
+	args.LogIndex = 0
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrTxWithWrongMemo {
+		t.Errorf("register swap with wrong memo. have %v want %v", errs[0], tokens.ErrTxWithWrongMemo)
+	}
+
+	env.setSwapoutTx(memo, 5, env.sendMsg(env.mpcAddress, "1000000"))
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrTxWithWrongStatus {
+		t.Errorf("register failed swap tx. have %v want %v", errs[0], tokens.ErrTxWithWrongStatus)
+	}
+
+	env.setSwapoutTx(memo, 0, env.sendMsg(env.mpcAddress, "1000000"))
+	if _, err := b.VerifyTransaction(tTxHash, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType}); err != nil {
+		t.Errorf("verify stable swap failed. err %v", err)
+	}
+}
+
+func TestBuildAndSignTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	b := env.dstBridge
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  "test",
+			SwapID:      tTxHash,
+			SwapType:    tokens.ERC20SwapType,
+			Bind:        env.userAddr,
+			FromChainID: big.NewInt(1100),
+			ToChainID:   big.NewInt(1200),
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+				Token:   tDenom,
+				TokenID: tTokenID,
+			}},
+		},
+		From:        env.mpcAddress,
+		OriginValue: big.NewInt(1000000),
+	}
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatalf("build tx failed. err %v", err)
+	}
+	tx := rawTx.(*Tx)
+	if tx.Sequence != 7 || tx.AccountNumber != 12 || tx.ChainID != "cosmoshub-4" ||
+		tx.Memo != tTxHash || tx.Fee.String() != "5000uatom" || args.SwapValue.String() != "999000" {
+		t.Fatalf("wrong built tx %+v swapValue %v", tx, args.SwapValue)
+	}
+	if *args.Extra.Sequence != 7 || *args.Extra.Fee != "5000uatom" {
+		t.Errorf("build tx should save sequence and fee in extra")
+	}
+	if err = b.VerifyMsgHash(rawTx, []string{common.ToHex(tx.SignHash())}); err != nil {
+		t.Errorf("verify msg hash failed. err %v", err)
+	}
+
+	// rebuild with saved extras should produce the same sign hash
+	rebuildArgs := *args
+	rebuildArgs.SwapValue = nil
+	rawTx2, err := b.BuildRawTransaction(&rebuildArgs)
+	if err != nil || common.ToHex(rawTx2.(*Tx).SignHash()) != common.ToHex(tx.SignHash()) {
+		t.Fatalf("rebuild tx mismatch. err %v", err)
+	}
+
+	if _, err = b.verifyTransactionReceiver(rawTx, args); err != nil {
+		t.Errorf("verify tx receiver failed. err %v", err)
+	}
+	signedTx, txHash, err := b.SignTransactionWithPrivateKey(rawTx, hex.EncodeToString(env.mpcKey.Serialize()))
+	if err != nil {
+		t.Fatalf("sign tx failed. err %v", err)
+	}
+	signature := signedTx.(*SignedTx).Signature
+	if len(signature) != 64 || new(big.Int).SetBytes(signature[32:]).Cmp(secp256k1HalfN) > 0 {
+		t.Errorf("signature should be 64 bytes in low-s form")
+	}
+	sentHash, err := b.SendTransaction(signedTx)
+	if err != nil || sentHash != tTxHash || len(env.lcd.broadcast) == 0 {
+		t.Errorf("send tx failed. hash %v err %v", sentHash, err)
+	}
+	if len(txHash) != 64 {
+		t.Errorf("wrong tx hash %v", txHash)
+	}
+}
@@ -0,0 +1,188 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
+		return nil, tokens.ErrToChainIDMismatch
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if args.From != routerMPC {
+		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
+		return nil, tokens.ErrSenderMismatch
+	}
+
+	amount, err := b.getSwapAmount(args)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("wrong swap value %v", amount)
+	}
+	args.To = args.Bind     // to
+	args.SwapValue = amount // swapValue
+	args.Memo = args.SwapID // memo
+
+	msg, err := b.buildSwapInMsg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args, msg)
+}
+
+func (b *Bridge) getMultichainToken(args *tokens.BuildTxArgs) (string, error) {
+	erc20SwapInfo := args.ERC20SwapInfo
+	if erc20SwapInfo == nil || erc20SwapInfo.TokenID == "" {
+		return "", errors.New("build router swaptx without tokenID")
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", b.ChainConfig.ChainID)
+		return "", tokens.ErrMissTokenConfig
+	}
+	return multichainToken, nil
+}
+
+func (b *Bridge) getSwapAmount(args *tokens.BuildTxArgs) (*big.Int, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	if !b.IsValidAddress(args.Bind) {
+		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
+		return nil, errors.New("can not swapout to empty or invalid receiver")
+	}
+	erc20SwapInfo := args.ERC20SwapInfo
+	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
+	if fromBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := fromBridge.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", args.FromChainID, "token", erc20SwapInfo.Token)
+		return nil, tokens.ErrMissTokenConfig
+	}
+	toTokenCfg := b.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	amount, swapFeeInfo := tokens.CalcSwapValueWithFee(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo, args.OriginTime)
+	args.SwapFeeInfo = swapFeeInfo
+	return amount, nil
+}
+
+// buildSwapInMsg build bank send message of denom token,
+// or cw20 transfer message of contract token
+func (b *Bridge) buildSwapInMsg(args *tokens.BuildTxArgs) (Msg, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	if b.IsContractAddress(multichainToken) {
+		return NewCw20TransferMsg(args.From, multichainToken, args.Bind, args.SwapValue)
+	}
+	return &MsgSend{
+		FromAddress: args.From,
+		ToAddress:   args.Bind,
+		Amount:      []*Coin{{Denom: multichainToken, Amount: args.SwapValue.String()}},
+	}, nil
+}
+
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) error {
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	if args.Extra.Sequence == nil {
+		account, err := b.GetAccountInfo(args.From)
+		if err != nil {
+			return err
+		}
+		sequence, err := strconv.ParseUint(account.Sequence, 10, 64)
+		if err != nil {
+			return err
+		}
+		args.Extra.Sequence = &sequence
+	}
+	if args.Extra.Fee == nil {
+		fee := b.DefaultFee
+		args.Extra.Fee = &fee
+	}
+	if _, err := ParseCoin(*args.Extra.Fee); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *Bridge) buildTx(args *tokens.BuildTxArgs, msg Msg) (rawTx interface{}, err error) {
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	pubKey, err := GetCompressedPublicKey(mpcPubkey)
+	if err != nil {
+		return nil, err
+	}
+	account, err := b.GetAccountInfo(args.From)
+	if err != nil {
+		return nil, err
+	}
+	accountNumber, err := strconv.ParseUint(account.AccountNumber, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := b.GetCosmosChainID()
+	if err != nil {
+		return nil, err
+	}
+	fee, _ := ParseCoin(*args.Extra.Fee)
+
+	tx := &Tx{
+		Msgs:          []Msg{msg},
+		Memo:          args.Memo,
+		PubKey:        pubKey,
+		Sequence:      *args.Extra.Sequence,
+		Fee:           fee,
+		GasLimit:      b.GasLimit,
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+	}
+
+	log.Info("build tx success",
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "to", args.To, "bind", args.Bind,
+		"sequence", tx.Sequence, "accountNumber", accountNumber,
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"fee", fee, "gasLimit", tx.GasLimit, "replaceNum", args.GetReplaceNum())
+
+	return tx, nil
+}
@@ -0,0 +1,269 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errEmptyURLs = errors.New("empty URLs")
+)
+
+// TxMessage message in tx body (decoded by rest api)
+type TxMessage struct {
+	Type        string          `json:"@type"`
+	FromAddress string          `json:"from_address,omitempty"`
+	ToAddress   string          `json:"to_address,omitempty"`
+	Amount      []*Coin         `json:"amount,omitempty"`
+	Sender      string          `json:"sender,omitempty"`
+	Contract    string          `json:"contract,omitempty"`
+	Msg         json.RawMessage `json:"msg,omitempty"`
+	Funds       []*Coin         `json:"funds,omitempty"`
+}
+
+// TxBody tx body
+type TxBody struct {
+	Messages []*TxMessage `json:"messages"`
+	Memo     string       `json:"memo"`
+}
+
+// TxResponse tx response
+type TxResponse struct {
+	Height    string `json:"height"`
+	TxHash    string `json:"txhash"`
+	Code      uint32 `json:"code"`
+	RawLog    string `json:"raw_log"`
+	Timestamp string `json:"timestamp"`
+}
+
+// IsStatusOk impl tokens.StatusInterface
+func (r *TxResponse) IsStatusOk() bool {
+	return r.Code == 0
+}
+
+// GetHeight get height
+func (r *TxResponse) GetHeight() uint64 {
+	height, _ := strconv.ParseUint(r.Height, 10, 64)
+	return height
+}
+
+// GetTimestamp get timestamp in seconds
+func (r *TxResponse) GetTimestamp() uint64 {
+	t, err := time.Parse(time.RFC3339, r.Timestamp)
+	if err != nil {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+// GetTxResult result of '/cosmos/tx/v1beta1/txs/{hash}'
+type GetTxResult struct {
+	Tx struct {
+		Body *TxBody `json:"body"`
+	} `json:"tx"`
+	TxResponse *TxResponse `json:"tx_response"`
+}
+
+// BaseAccount base account
+type BaseAccount struct {
+	Address       string `json:"address"`
+	AccountNumber string `json:"account_number"`
+	Sequence      string `json:"sequence"`
+}
+
+// AccountInfo account info (base account or vesting account)
+type AccountInfo struct {
+	BaseAccount
+	Base        *BaseAccount `json:"base_account,omitempty"`
+	BaseVesting *struct {
+		BaseAccount *BaseAccount `json:"base_account"`
+	} `json:"base_vesting_account,omitempty"`
+}
+
+// GetBaseAccount get base account
+func (a *AccountInfo) GetBaseAccount() *BaseAccount {
+	switch {
+	case a.Base != nil:
+		return a.Base
+	case a.BaseVesting != nil && a.BaseVesting.BaseAccount != nil:
+		return a.BaseVesting.BaseAccount
+	default:
+		return &a.BaseAccount
+	}
+}
+
+// BlockHeader block header
+type BlockHeader struct {
+	ChainID string `json:"chain_id"`
+	Height  string `json:"height"`
+	Time    string `json:"time"`
+}
+
+// GetLatestBlockResult result of '/cosmos/base/tendermint/v1beta1/blocks/latest'
+type GetLatestBlockResult struct {
+	Block struct {
+		Header *BlockHeader `json:"header"`
+	} `json:"block"`
+}
+
+func (b *Bridge) restGet(result interface{}, path string) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = restGetOf(result, url, path, b.RPCClientTimeout)
+		if err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, path)
+}
+
+func restGetOf(result interface{}, url, path string, timeout int) error {
+	return client.RPCGetWithTimeout(result, strings.TrimSuffix(url, "/")+path, timeout)
+}
+
+func restPostOf(result interface{}, url, path string, body interface{}, timeout int) error {
+	resp, err := client.HTTPPost(strings.TrimSuffix(url, "/")+path, body, nil, nil, timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(data))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// GetLatestBlockHeader get latest block header
+func (b *Bridge) GetLatestBlockHeader() (*BlockHeader, error) {
+	var result GetLatestBlockResult
+	err := b.restGet(&result, "/cosmos/base/tendermint/v1beta1/blocks/latest")
+	if err != nil {
+		return nil, err
+	}
+	if result.Block.Header == nil {
+		return nil, wrapRPCQueryError(nil, "blocks/latest")
+	}
+	return result.Block.Header, nil
+}
+
+// GetLatestBlockNumberOf get latest block number of specified url
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	var result GetLatestBlockResult
+	err := restGetOf(&result, url, "/cosmos/base/tendermint/v1beta1/blocks/latest", b.RPCClientTimeout)
+	if err != nil {
+		return 0, wrapRPCQueryError(err, "blocks/latest")
+	}
+	if result.Block.Header == nil {
+		return 0, wrapRPCQueryError(nil, "blocks/latest")
+	}
+	return strconv.ParseUint(result.Block.Header.Height, 10, 64)
+}
+
+// GetLatestBlockNumber get latest block number
+func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
+	header, err := b.GetLatestBlockHeader()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(header.Height, 10, 64)
+}
+
+// GetTransactionByHash get tx by hash
+func (b *Bridge) GetTransactionByHash(txHash string) (*GetTxResult, error) {
+	var result GetTxResult
+	err := b.restGet(&result, "/cosmos/tx/v1beta1/txs/"+txHash)
+	if err != nil {
+		return nil, err
+	}
+	if result.TxResponse == nil || result.Tx.Body == nil {
+		return nil, tokens.ErrTxNotFound
+	}
+	return &result, nil
+}
+
+// GetAccountInfo get account number and sequence
+func (b *Bridge) GetAccountInfo(address string) (*BaseAccount, error) {
+	var result struct {
+		Account *AccountInfo `json:"account"`
+	}
+	err := b.restGet(&result, "/cosmos/auth/v1beta1/accounts/"+address)
+	if err != nil {
+		return nil, err
+	}
+	if result.Account == nil {
+		return nil, wrapRPCQueryError(nil, "accounts", address)
+	}
+	return result.Account.GetBaseAccount(), nil
+}
+
+// GetDenomBalance get balance of denom
+func (b *Bridge) GetDenomBalance(address, denom string) (*Coin, error) {
+	var result struct {
+		Balance *Coin `json:"balance"`
+	}
+	err := b.restGet(&result, fmt.Sprintf("/cosmos/bank/v1beta1/balances/%v/by_denom?denom=%v", address, denom))
+	if err != nil {
+		return nil, err
+	}
+	if result.Balance == nil {
+		return &Coin{Denom: denom, Amount: "0"}, nil
+	}
+	return result.Balance, nil
+}
+
+// BroadcastTx broadcast tx bytes to all gateways (sync mode)
+func (b *Bridge) BroadcastTx(txBytes []byte) (txHash string, err error) {
+	urls := append([]string{}, b.GatewayConfig.APIAddress...)
+	urls = append(urls, b.GatewayConfig.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	req := map[string]string{
+		"tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+		"mode":     "BROADCAST_MODE_SYNC",
+	}
+	for _, url := range urls {
+		var result struct {
+			TxResponse *TxResponse `json:"tx_response"`
+		}
+		err = restPostOf(&result, url, "/cosmos/tx/v1beta1/txs", req, b.RPCClientTimeout)
+		if err != nil {
+			continue
+		}
+		if result.TxResponse == nil {
+			err = wrapRPCQueryError(nil, "broadcast tx")
+			continue
+		}
+		if result.TxResponse.Code != 0 {
+			err = fmt.Errorf("broadcast tx failed. code %v, log %v", result.TxResponse.Code, result.TxResponse.RawLog)
+			continue
+		}
+		txHash = result.TxResponse.TxHash
+	}
+	if txHash != "" {
+		return txHash, nil
+	}
+	return "", wrapRPCQueryError(err, "broadcast tx")
+}
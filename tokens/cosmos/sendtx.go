@@ -0,0 +1,29 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*SignedTx)
+	if !ok {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	txBytes := tx.TxBytes()
+	txHash, err = b.BroadcastTx(txBytes)
+	if err != nil {
+		log.Info("SendTransaction failed", "hash", tx.Hash(), "err", err)
+	} else {
+		log.Info("SendTransaction success", "hash", txHash)
+	}
+	if params.IsDebugMode() {
+		log.Infof("SendTransaction rawtx is %v", hex.EncodeToString(txBytes))
+	}
+	return txHash, err
+}
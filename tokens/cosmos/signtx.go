@@ -0,0 +1,158 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+var secp256k1HalfN = new(big.Int).Rsh(btcec.S256().N, 1)
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Tx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	sigHash := common.ToHex(tx.SignHash())
+	if !strings.EqualFold(sigHash, msgHashes[0]) {
+		log.Trace("message hash mismatch", "want", msgHashes[0], "have", sigHash)
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, args *tokens.BuildTxArgs) (*Tx, error) {
+	tx, ok := rawTx.(*Tx)
+	if !ok || len(tx.Msgs) != 1 {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	checkMsg, err := b.buildSwapInMsg(args)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Msgs[0].TypeURL() != checkMsg.TypeURL() || !bytes.Equal(tx.Msgs[0].Marshal(), checkMsg.Marshal()) {
+		return nil, errors.New("[sign] tx message mismatch")
+	}
+	if tx.Memo != args.SwapID {
+		return nil, errors.New("[sign] tx memo mismatch")
+	}
+	return tx, nil
+}
+
+// MPCSignTransaction mpc sign raw tx
+func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, err := b.verifyTransactionReceiver(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SignWithPrivateKey() {
+		priKey := params.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+	}
+
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	pubKey, err := btcec.ParsePubKey(common.FromHex(mpcPubkey), btcec.S256())
+	if err != nil {
+		return nil, "", err
+	}
+
+	msgHash := common.ToHex(tx.SignHash())
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash)
+	keyID, rsvs, err := mpc.DoSignOneEC(mpcPubkey, msgHash, msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash)
+
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many",
+			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
+		return nil, "", errors.New("get sign status require one rsv but return many")
+	}
+
+	signature := common.FromHex(rsvs[0])
+	if len(signature) != crypto.SignatureLength {
+		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
+		return nil, "", errors.New("wrong signature length")
+	}
+
+	signedTx, err := signTxWithSignature(tx, signature, pubKey)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash = signedTx.Hash()
+	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash, "sequence", tx.Sequence)
+	return signedTx, txHash, nil
+}
+
+// SignTransactionWithPrivateKey sign tx with ECDSA private key
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Tx)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	ecPriKey, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		return nil, "", err
+	}
+	signature, err := crypto.Sign(tx.SignHash(), ecPriKey)
+	if err != nil {
+		return nil, "", err
+	}
+	privKey := (*btcec.PrivateKey)(ecPriKey)
+	signedTx, err := signTxWithSignature(tx, signature, privKey.PubKey())
+	if err != nil {
+		return nil, "", err
+	}
+	return signedTx, signedTx.Hash(), nil
+}
+
+// signTxWithSignature verify rsv signature and attach it in low-s form (r || s)
+func signTxWithSignature(tx *Tx, rsv []byte, pubKey *btcec.PublicKey) (*SignedTx, error) {
+	if !bytes.Equal(tx.PubKey, pubKey.SerializeCompressed()) {
+		return nil, errors.New("signer public key mismatch")
+	}
+	sig := &btcec.Signature{
+		R: new(big.Int).SetBytes(rsv[:32]),
+		S: new(big.Int).SetBytes(rsv[32:64]),
+	}
+	if sig.S.Cmp(secp256k1HalfN) > 0 {
+		sig.S = new(big.Int).Sub(btcec.S256().N, sig.S)
+	}
+	if !sig.Verify(tx.SignHash(), pubKey) {
+		return nil, errors.New("verify signature failed")
+	}
+	signature := make([]byte, 64)
+	sig.R.FillBytes(signature[:32])
+	sig.S.FillBytes(signature[32:])
+	return &SignedTx{Tx: tx, Signature: signature}, nil
+}
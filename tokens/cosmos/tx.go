@@ -0,0 +1,211 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/tools/protobuf"
+)
+
+// type urls of supported messages
+const (
+	MsgSendTypeURL            = "/cosmos.bank.v1beta1.MsgSend"
+	MsgExecuteContractTypeURL = "/cosmwasm.wasm.v1.MsgExecuteContract"
+	Secp256k1PubKeyTypeURL    = "/cosmos.crypto.secp256k1.PubKey"
+
+	signModeDirect = 1
+)
+
+var coinRegexp = regexp.MustCompile(`^([0-9]+)([a-zA-Z][a-zA-Z0-9/:._-]{2,127})$`)
+
+// Coin struct
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// ParseCoin parse coin string like '5000uatom'
+func ParseCoin(coinStr string) (*Coin, error) {
+	matches := coinRegexp.FindStringSubmatch(strings.TrimSpace(coinStr))
+	if len(matches) != 3 {
+		return nil, fmt.Errorf("wrong coin format '%v'", coinStr)
+	}
+	return &Coin{Denom: matches[2], Amount: matches[1]}, nil
+}
+
+// String coin string
+func (c *Coin) String() string {
+	return c.Amount + c.Denom
+}
+
+func (c *Coin) marshal() []byte {
+	p := &protobuf.Buffer{}
+	p.String(1, c.Denom)
+	p.String(2, c.Amount)
+	return p.Result()
+}
+
+// Msg message in tx body
+type Msg interface {
+	TypeURL() string
+	Marshal() []byte
+}
+
+// MsgSend bank send message
+type MsgSend struct {
+	FromAddress string
+	ToAddress   string
+	Amount      []*Coin
+}
+
+// TypeURL impl Msg
+func (msg *MsgSend) TypeURL() string {
+	return MsgSendTypeURL
+}
+
+// Marshal impl Msg
+func (msg *MsgSend) Marshal() []byte {
+	p := &protobuf.Buffer{}
+	p.String(1, msg.FromAddress)
+	p.String(2, msg.ToAddress)
+	for _, coin := range msg.Amount {
+		p.Message(3, coin.marshal())
+	}
+	return p.Result()
+}
+
+// MsgExecuteContract cosmwasm execute contract message
+type MsgExecuteContract struct {
+	Sender   string
+	Contract string
+	Msg      []byte // json
+	Funds    []*Coin
+}
+
+// TypeURL impl Msg
+func (msg *MsgExecuteContract) TypeURL() string {
+	return MsgExecuteContractTypeURL
+}
+
+// Marshal impl Msg
+func (msg *MsgExecuteContract) Marshal() []byte {
+	p := &protobuf.Buffer{}
+	p.String(1, msg.Sender)
+	p.String(2, msg.Contract)
+	p.Bytes(3, msg.Msg)
+	for _, coin := range msg.Funds {
+		p.Message(5, coin.marshal())
+	}
+	return p.Result()
+}
+
+// NewCw20TransferMsg new cw20 transfer message
+func NewCw20TransferMsg(sender, contract, recipient string, amount *big.Int) (*MsgExecuteContract, error) {
+	msg, err := json.Marshal(map[string]interface{}{
+		"transfer": map[string]string{
+			"recipient": recipient,
+			"amount":    amount.String(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MsgExecuteContract{Sender: sender, Contract: contract, Msg: msg}, nil
+}
+
+func marshalAny(typeURL string, value []byte) []byte {
+	p := &protobuf.Buffer{}
+	p.String(1, typeURL)
+	p.Bytes(2, value)
+	return p.Result()
+}
+
+// Tx unsigned tx with sign mode direct
+type Tx struct {
+	Msgs          []Msg
+	Memo          string
+	PubKey        []byte // compressed secp256k1 public key
+	Sequence      uint64
+	Fee           *Coin
+	GasLimit      uint64
+	ChainID       string
+	AccountNumber uint64
+}
+
+// BodyBytes marshal tx body
+func (tx *Tx) BodyBytes() []byte {
+	p := &protobuf.Buffer{}
+	for _, msg := range tx.Msgs {
+		p.Message(1, marshalAny(msg.TypeURL(), msg.Marshal()))
+	}
+	p.String(2, tx.Memo)
+	return p.Result()
+}
+
+// AuthInfoBytes marshal auth info
+func (tx *Tx) AuthInfoBytes() []byte {
+	pubKey := &protobuf.Buffer{}
+	pubKey.Bytes(1, tx.PubKey)
+
+	single := &protobuf.Buffer{}
+	single.Uint64(1, signModeDirect)
+	modeInfo := &protobuf.Buffer{}
+	modeInfo.Message(1, single.Result())
+
+	signerInfo := &protobuf.Buffer{}
+	signerInfo.Message(1, marshalAny(Secp256k1PubKeyTypeURL, pubKey.Result()))
+	signerInfo.Message(2, modeInfo.Result())
+	signerInfo.Uint64(3, tx.Sequence)
+
+	fee := &protobuf.Buffer{}
+	if tx.Fee != nil {
+		fee.Message(1, tx.Fee.marshal())
+	}
+	fee.Uint64(2, tx.GasLimit)
+
+	p := &protobuf.Buffer{}
+	p.Message(1, signerInfo.Result())
+	p.Message(2, fee.Result())
+	return p.Result()
+}
+
+// SignBytes marshal sign doc
+func (tx *Tx) SignBytes() []byte {
+	p := &protobuf.Buffer{}
+	p.Bytes(1, tx.BodyBytes())
+	p.Bytes(2, tx.AuthInfoBytes())
+	p.String(3, tx.ChainID)
+	p.Uint64(4, tx.AccountNumber)
+	return p.Result()
+}
+
+// SignHash sha256 hash of sign doc
+func (tx *Tx) SignHash() []byte {
+	hash := sha256.Sum256(tx.SignBytes())
+	return hash[:]
+}
+
+// SignedTx tx with signature
+type SignedTx struct {
+	*Tx
+	Signature []byte // 64 bytes r || s
+}
+
+// TxBytes marshal tx raw
+func (tx *SignedTx) TxBytes() []byte {
+	p := &protobuf.Buffer{}
+	p.Bytes(1, tx.BodyBytes())
+	p.Bytes(2, tx.AuthInfoBytes())
+	p.Message(3, tx.Signature)
+	return p.Result()
+}
+
+// Hash tx hash (upper case hex of sha256 of tx raw bytes)
+func (tx *SignedTx) Hash() string {
+	hash := sha256.Sum256(tx.TxBytes())
+	return fmt.Sprintf("%X", hash[:])
+}
@@ -0,0 +1,215 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// Cw20TransferMsg cw20 transfer execute message
+type Cw20TransferMsg struct {
+	Transfer *struct {
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+	} `json:"transfer"`
+}
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.GetTransactionByHash(txHash)
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	txStatus := &tokens.TxStatus{
+		Receipt:     tx.TxResponse,
+		BlockHeight: tx.TxResponse.GetHeight(),
+		BlockTime:   tx.TxResponse.GetTimestamp(),
+	}
+	if latest, errt := b.GetLatestBlockNumber(); errt == nil && latest >= txStatus.BlockHeight {
+		txStatus.Confirmations = latest - txStatus.BlockHeight + 1
+	}
+	return txStatus, nil
+}
+
+// VerifyTransaction api
+func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	return b.verifySwapoutTx(txHash, args.LogIndex, args.AllowUnstable)
+}
+
+func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ERC20SwapType // SwapType
+	swapInfo.Hash = strings.ToLower(txHash)  // Hash
+	swapInfo.LogIndex = logIndex             // LogIndex
+
+	tx, err := b.getStableSwapTx(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.parseSwapoutMsg(swapInfo, tx.Tx.Body)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapoutInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify router swap tx stable pass",
+			"identifier", params.GetIdentifier(),
+			"from", swapInfo.From, "to", swapInfo.To,
+			"bind", swapInfo.Bind, "value", swapInfo.Value,
+			"txid", txHash, "logIndex", logIndex,
+			"height", swapInfo.Height, "timestamp", swapInfo.Timestamp,
+			"fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID,
+			"token", swapInfo.ERC20SwapInfo.Token, "tokenID", swapInfo.ERC20SwapInfo.TokenID)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) getStableSwapTx(swapInfo *tokens.SwapTxInfo, allowUnstable bool) (*GetTxResult, error) {
+	tx, err := b.GetTransactionByHash(swapInfo.Hash)
+	if err != nil {
+		log.Error("get tx failed", "hash", swapInfo.Hash, "err", err)
+		return nil, err
+	}
+	txResponse := tx.TxResponse
+	height := txResponse.GetHeight()
+	if height == 0 {
+		return nil, tokens.ErrTxNotFound
+	}
+	if height < b.ChainConfig.InitialHeight {
+		return nil, tokens.ErrTxBeforeInitialHeight
+	}
+	if !txResponse.IsStatusOk() {
+		return nil, tokens.ErrTxWithWrongStatus
+	}
+
+	swapInfo.Height = height                       // Height
+	swapInfo.Timestamp = txResponse.GetTimestamp() // Timestamp
+
+	if !allowUnstable {
+		latest, errt := b.GetLatestBlockNumber()
+		if errt != nil {
+			return nil, errt
+		}
+		if latest < height || latest-height+1 < b.ChainConfig.Confirmations {
+			return nil, tokens.ErrTxNotStable
+		}
+	}
+	return tx, nil
+}
+
+func (b *Bridge) parseSwapoutMsg(swapInfo *tokens.SwapTxInfo, body *TxBody) (err error) {
+	if swapInfo.LogIndex >= len(body.Messages) {
+		return tokens.ErrLogIndexOutOfRange
+	}
+	msg := body.Messages[swapInfo.LogIndex]
+	routerMPC := b.ChainConfig.RouterContract
+
+	var token, from, receiver, amountStr string
+	switch msg.Type {
+	case MsgSendTypeURL:
+		if len(msg.Amount) != 1 {
+			return tokens.ErrTxWithWrongValue
+		}
+		token = msg.Amount[0].Denom
+		amountStr = msg.Amount[0].Amount
+		from = msg.FromAddress
+		receiver = msg.ToAddress
+	case MsgExecuteContractTypeURL:
+		var cw20Msg Cw20TransferMsg
+		if err = json.Unmarshal(msg.Msg, &cw20Msg); err != nil || cw20Msg.Transfer == nil {
+			return tokens.ErrSwapoutLogNotFound
+		}
+		if len(msg.Funds) != 0 {
+			return tokens.ErrTxWithWrongValue
+		}
+		token = msg.Contract
+		amountStr = cw20Msg.Transfer.Amount
+		from = msg.Sender
+		receiver = cw20Msg.Transfer.Recipient
+	default:
+		return tokens.ErrSwapoutLogNotFound
+	}
+	if receiver != routerMPC {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	tokenCfg := b.GetTokenConfig(token)
+	if tokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+	amount, ok := new(big.Int).SetString(amountStr, 10)
+	if !ok {
+		return tokens.ErrTxWithWrongValue
+	}
+
+	swapInfo.TxTo = routerMPC                         // TxTo
+	swapInfo.To = routerMPC                           // To
+	swapInfo.From = from                              // From
+	swapInfo.Value = amount                           // Value
+	swapInfo.FromChainID = b.ChainConfig.GetChainID() // FromChainID
+
+	swapInfo.ERC20SwapInfo.Token = token              // Token
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID // TokenID
+
+	if strings.TrimSpace(body.Memo) == "" {
+		return tokens.ErrTxWithoutMemo
+	}
+	swapInfo.Bind, swapInfo.ToChainID, err = tokens.ParseSwapoutMemo(body.Memo) // Bind, ToChainID
+	if err != nil {
+		log.Warn("parse swapout memo failed", "txid", swapInfo.Hash, "memo", body.Memo, "err", err)
+		return tokens.ErrTxWithWrongMemo
+	}
+	return nil
+}
+
+func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
+	if swapInfo.FromChainID.Cmp(swapInfo.ToChainID) == 0 {
+		return tokens.ErrSameFromAndToChainID
+	}
+	erc20SwapInfo := swapInfo.ERC20SwapInfo
+	fromTokenCfg := b.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil || erc20SwapInfo.TokenID == "" {
+		return tokens.ErrMissTokenConfig
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, swapInfo.ToChainID.String())
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", swapInfo.ToChainID, "txid", swapInfo.Hash)
+		return tokens.ErrMissTokenConfig
+	}
+	toBridge := router.GetBridgeByChainID(swapInfo.ToChainID.String())
+	if toBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	toTokenCfg := toBridge.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", swapInfo.ToChainID, "token", multichainToken)
+		return tokens.ErrMissTokenConfig
+	}
+	if !tokens.CheckTokenSwapValue(swapInfo, fromTokenCfg.Decimals, toTokenCfg.Decimals) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !toBridge.IsValidAddress(swapInfo.Bind) {
+		log.Warn("wrong bind address in swapout", "txid", swapInfo.Hash, "bind", swapInfo.Bind)
+		return tokens.ErrWrongBindAddress
+	}
+	return nil
+}
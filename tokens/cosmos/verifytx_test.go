@@ -0,0 +1,100 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	tRouterMPC    = "cosmos1router"
+	tSender       = "cosmos1sender"
+	tCw20Contract = "juno1cw20"
+	tCw20TokenID  = "CW20"
+)
+
+func newTestTxBody(t *testing.T, memo string, messages ...string) *TxBody {
+	body := &TxBody{Memo: memo}
+	for _, message := range messages {
+		var msg TxMessage
+		if err := json.Unmarshal([]byte(message), &msg); err != nil {
+			t.Fatal(err)
+		}
+		body.Messages = append(body.Messages, &msg)
+	}
+	return body
+}
+
+func TestParseSwapoutMsg(t *testing.T) {
+	b := newTestBridge(tFromChain, "", tRouterMPC)
+	b.SetTokenConfig(tCw20Contract, &tokens.TokenConfig{
+		TokenID:         tCw20TokenID,
+		Decimals:        6,
+		ContractAddress: tCw20Contract,
+	})
+
+	sendMsg := `{"@type": "/cosmos.bank.v1beta1.MsgSend", "from_address": "cosmos1sender", "to_address": "cosmos1router",
+		"amount": [{"denom": "uatom", "amount": "1000000"}]}`
+	sendOtherMsg := `{"@type": "/cosmos.bank.v1beta1.MsgSend", "from_address": "cosmos1sender", "to_address": "cosmos1other",
+		"amount": [{"denom": "uatom", "amount": "1000000"}]}`
+	sendCoinsMsg := `{"@type": "/cosmos.bank.v1beta1.MsgSend", "from_address": "cosmos1sender", "to_address": "cosmos1router",
+		"amount": [{"denom": "uatom", "amount": "1000000"}, {"denom": "uosmo", "amount": "1"}]}`
+	sendUnknownMsg := `{"@type": "/cosmos.bank.v1beta1.MsgSend", "from_address": "cosmos1sender", "to_address": "cosmos1router",
+		"amount": [{"denom": "uosmo", "amount": "1000000"}]}`
+	cw20Msg := `{"@type": "/cosmwasm.wasm.v1.MsgExecuteContract", "sender": "cosmos1sender", "contract": "juno1cw20",
+		"msg": {"transfer": {"recipient": "cosmos1router", "amount": "2500"}}}`
+	cw20FundsMsg := `{"@type": "/cosmwasm.wasm.v1.MsgExecuteContract", "sender": "cosmos1sender", "contract": "juno1cw20",
+		"msg": {"transfer": {"recipient": "cosmos1router", "amount": "2500"}}, "funds": [{"denom": "uatom", "amount": "1"}]}`
+	cw20SendMsg := `{"@type": "/cosmwasm.wasm.v1.MsgExecuteContract", "sender": "cosmos1sender", "contract": "juno1cw20",
+		"msg": {"send": {"contract": "cosmos1router", "amount": "2500", "msg": ""}}}`
+	ibcTransferMsg := `{"@type": "/ibc.applications.transfer.v1.MsgTransfer", "sender": "cosmos1sender",
+		"receiver": "cosmos1router", "token": {"denom": "uatom", "amount": "1000000"}}`
+
+	testCases := []struct {
+		name      string
+		body      *TxBody
+		logIndex  int
+		wantErr   error
+		wantToken string
+		wantValue string
+		wantBind  string
+		wantTo    string
+	}{
+		{"bank send", newTestTxBody(t, "0xbind:1200", sendMsg), 0, nil, tDenom, "1000000", "0xbind", "1200"},
+		{"memo with spaces", newTestTxBody(t, " 0xbind:1200\n", sendMsg), 0, nil, tDenom, "1000000", "0xbind", "1200"},
+		{"cw20 transfer", newTestTxBody(t, "0xbind:1300", cw20Msg), 0, nil, tCw20Contract, "2500", "0xbind", "1300"},
+		{"message of log index", newTestTxBody(t, "0xbind:1200", sendOtherMsg, cw20Msg), 1, nil, tCw20Contract, "2500", "0xbind", "1200"},
+		{"log index out of range", newTestTxBody(t, "0xbind:1200", sendMsg), 1, tokens.ErrLogIndexOutOfRange, "", "", "", ""},
+		{"send to other", newTestTxBody(t, "0xbind:1200", sendOtherMsg), 0, tokens.ErrTxWithWrongReceiver, "", "", "", ""},
+		{"send many coins", newTestTxBody(t, "0xbind:1200", sendCoinsMsg), 0, tokens.ErrTxWithWrongValue, "", "", "", ""},
+		{"send unknown denom", newTestTxBody(t, "0xbind:1200", sendUnknownMsg), 0, tokens.ErrMissTokenConfig, "", "", "", ""},
+		{"cw20 transfer with funds", newTestTxBody(t, "0xbind:1200", cw20FundsMsg), 0, tokens.ErrTxWithWrongValue, "", "", "", ""},
+		{"cw20 send is not transfer", newTestTxBody(t, "0xbind:1200", cw20SendMsg), 0, tokens.ErrSwapoutLogNotFound, "", "", "", ""},
+		{"ibc transfer is not supported", newTestTxBody(t, "0xbind:1200", ibcTransferMsg), 0, tokens.ErrSwapoutLogNotFound, "", "", "", ""},
+		{"no memo", newTestTxBody(t, " ", sendMsg), 0, tokens.ErrTxWithoutMemo, "", "", "", ""},
+		{"memo without chainID", newTestTxBody(t, "0xbind", sendMsg), 0, tokens.ErrTxWithWrongMemo, "", "", "", ""},
+		{"memo with empty bind", newTestTxBody(t, ":1200", sendMsg), 0, tokens.ErrTxWithWrongMemo, "", "", "", ""},
+		{"memo with negative chainID", newTestTxBody(t, "0xbind:-1", sendMsg), 0, tokens.ErrTxWithWrongMemo, "", "", "", ""},
+	}
+	for _, tc := range testCases {
+		swapInfo := &tokens.SwapTxInfo{
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}},
+			LogIndex: tc.logIndex,
+		}
+		err := b.parseSwapoutMsg(swapInfo, tc.body)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%v: want error %v, have %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if swapInfo.ERC20SwapInfo.Token != tc.wantToken || swapInfo.Value.String() != tc.wantValue ||
+			swapInfo.Bind != tc.wantBind || swapInfo.ToChainID.String() != tc.wantTo ||
+			swapInfo.From != tSender || swapInfo.To != tRouterMPC || swapInfo.FromChainID.String() != tFromChain {
+			t.Errorf("%v: wrong swap info %+v", tc.name, swapInfo)
+		}
+	}
+}
@@ -9,6 +9,7 @@ var (
 	ErrNotImplemented        = errors.New("not implemented")
 	ErrSwapTypeNotSupported  = errors.New("swap type not supported")
 	ErrNoBridgeForChainID    = errors.New("no bridge for chain id")
+	ErrNoBridgeForFamily     = errors.New("no bridge for chain family")
 	ErrSwapTradeNotSupport   = errors.New("swap trade not support")
 	ErrNotFound              = errors.New("not found")
 	ErrTxNotFound            = errors.New("tx not found")
@@ -42,6 +43,9 @@ var (
 	ErrNoEnoughReserveBudget = errors.New("no enough reserve budget")
 	ErrTxWithNoPayment       = errors.New("tx with no payment")
 	ErrTxIsNotValidated      = errors.New("tx is not validated")
+	ErrTxWithoutMemo         = errors.New("tx without memo")
+	ErrTxWithWrongMemo       = errors.New("tx with wrong memo")
+	ErrSameFromAndToChainID  = errors.New("from and to chainID are same")
 
 	// errors should register in router swap
 	ErrTxWithWrongValue  = errors.New("tx with wrong value")
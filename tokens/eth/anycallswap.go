@@ -29,55 +29,6 @@ var (
 	defMinReserveBudget = big.NewInt(1e16)
 )
 
-func (b *Bridge) registerAnyCallSwapTx(txHash string, logIndex int) ([]*tokens.SwapTxInfo, []error) {
-	commonInfo := &tokens.SwapTxInfo{}
-	commonInfo.SwapType = tokens.AnyCallSwapType // SwapType
-	commonInfo.Hash = strings.ToLower(txHash)    // Hash
-	commonInfo.LogIndex = logIndex               // LogIndex
-
-	receipt, err := b.getSwapTxReceipt(commonInfo, true)
-	if err != nil {
-		return []*tokens.SwapTxInfo{commonInfo}, []error{err}
-	}
-
-	swapInfos := make([]*tokens.SwapTxInfo, 0)
-	errs := make([]error, 0)
-	startIndex, endIndex := 0, len(receipt.Logs)
-
-	if logIndex != 0 {
-		if logIndex >= endIndex || logIndex < 0 {
-			return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrLogIndexOutOfRange}
-		}
-		startIndex = logIndex
-		endIndex = logIndex + 1
-	}
-
-	for i := startIndex; i < endIndex; i++ {
-		swapInfo := &tokens.SwapTxInfo{}
-		*swapInfo = *commonInfo
-		swapInfo.LogIndex = i // LogIndex
-		err := b.verifyAnyCallSwapTxLog(swapInfo, receipt.Logs[i])
-		switch {
-		case errors.Is(err, tokens.ErrSwapoutLogNotFound),
-			errors.Is(err, tokens.ErrTxWithWrongTopics),
-			errors.Is(err, tokens.ErrTxWithWrongContract):
-			continue
-		case err == nil:
-			err = b.checkAnyCallSwapInfo(swapInfo)
-		default:
-			log.Debug(b.ChainConfig.BlockChain+" register anycall swap error", "txHash", txHash, "logIndex", swapInfo.LogIndex, "err", err)
-		}
-		swapInfos = append(swapInfos, swapInfo)
-		errs = append(errs, err)
-	}
-
-	if len(swapInfos) == 0 {
-		return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrSwapoutLogNotFound}
-	}
-
-	return swapInfos, errs
-}
-
 func (b *Bridge) verifyAnyCallSwapTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
 	swapInfo := &tokens.SwapTxInfo{}
 	swapInfo.SwapType = tokens.AnyCallSwapType // SwapType
@@ -89,7 +40,7 @@ func (b *Bridge) verifyAnyCallSwapTx(txHash string, logIndex int, allowUnstable
 		return swapInfo, err
 	}
 
-	if logIndex >= len(receipt.Logs) {
+	if logIndex >= len(receipt.Logs) || logIndex < 0 {
 		return swapInfo, tokens.ErrLogIndexOutOfRange
 	}
 
@@ -132,6 +83,9 @@ func (b *Bridge) verifyAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types
 	default:
 		err = b.parseAnyCallSwapTxLog(swapInfo, rlog)
 	}
+	if errors.Is(err, tokens.ErrSwapoutLogNotFound) {
+		return err
+	}
 	if err != nil {
 		log.Info(b.ChainConfig.BlockChain+" b.verifyAnyCallSwapTxLog fail", "tx", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", err)
 		return err
@@ -151,13 +105,12 @@ func (b *Bridge) verifyAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types
 
 func (b *Bridge) parseCurveAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog) (err error) {
 	logTopics := rlog.Topics
+	if len(logTopics) == 0 || !bytes.Equal(logTopics[0].Bytes(), LogCurveAnyCallTopic) {
+		return tokens.ErrSwapoutLogNotFound
+	}
 	if len(logTopics) != 4 {
 		return tokens.ErrTxWithWrongTopics
 	}
-	logTopic := rlog.Topics[0].Bytes()
-	if !bytes.Equal(logTopic, LogCurveAnyCallTopic) {
-		return tokens.ErrSwapoutLogNotFound
-	}
 
 	logData := *rlog.Data
 	if len(logData) < 96 {
@@ -185,13 +138,12 @@ func (b *Bridge) parseCurveAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *t
 
 func (b *Bridge) parseAnyCallSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog) (err error) {
 	logTopics := rlog.Topics
+	if len(logTopics) == 0 || !bytes.Equal(logTopics[0].Bytes(), LogAnyCallTopic) {
+		return tokens.ErrSwapoutLogNotFound
+	}
 	if len(logTopics) != 2 {
 		return tokens.ErrTxWithWrongTopics
 	}
-	logTopic := rlog.Topics[0].Bytes()
-	if !bytes.Equal(logTopic, LogAnyCallTopic) {
-		return tokens.ErrSwapoutLogNotFound
-	}
 
 	logData := *rlog.Data
 	if len(logData) < 320 {
@@ -33,6 +33,12 @@ type Bridge struct {
 	SignerChainID *big.Int
 }
 
+func init() {
+	tokens.RegisterBridge(tokens.DefaultBridgeFamily, func() tokens.IBridge {
+		return NewCrossChainBridge()
+	})
+}
+
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
 	return &Bridge{
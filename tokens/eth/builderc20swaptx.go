@@ -122,6 +122,9 @@ func (b *Bridge) buildSwapAndExecTxInput(args *tokens.BuildTxArgs, multichainTok
 	erc20SwapInfo := args.ERC20SwapInfo
 
 	funcHash := GetSwapInAndExecFuncHash(toTokenCfg)
+	if args.IsPayAnyToken() {
+		funcHash = AnySwapInAndExecFuncHash
+	}
 
 	input := abicoder.PackDataWithFuncHash(funcHash,
 		common.HexToHash(args.SwapID),
@@ -151,6 +154,10 @@ func (b *Bridge) buildERC20SwapoutTxInput(args *tokens.BuildTxArgs, multichainTo
 	}
 
 	funcHash := GetSwapInFuncHash(toTokenCfg, args.ERC20SwapInfo.ForUnderlying)
+	if args.IsPayAnyToken() {
+		// pay anyToken when the vault lacks underlying for a long time
+		funcHash = AnySwapInFuncHash
+	}
 
 	input := abicoder.PackDataWithFuncHash(funcHash,
 		common.HexToHash(args.SwapID),
@@ -231,7 +238,11 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	var feeGasPrice *big.Int
+	if args.Extra != nil {
+		feeGasPrice = args.Extra.FeeGasPrice
+	}
+	amount, args.SwapFeeInfo = tokens.CalcSwapValueWithFeeGasPrice(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo, args.OriginTime, feeGasPrice)
 	return receiver, amount, err
 }
 
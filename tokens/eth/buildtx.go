@@ -40,17 +40,12 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, tokens.ErrSenderMismatch
 	}
 
-	switch args.SwapType {
-	case tokens.ERC20SwapType:
-		err = b.buildERC20SwapTxInput(args)
-	case tokens.NFTSwapType:
-		err = b.buildNFTSwapTxInput(args)
-	case tokens.AnyCallSwapType:
-		err = b.buildAnyCallSwapTxInput(args)
-	default:
-		return nil, tokens.ErrSwapTypeNotSupported
+	err = b.setFeeGasPrice(args)
+	if err != nil {
+		return nil, err
 	}
 
+	err = b.BuildSwapTxInput(args)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +58,20 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	return b.buildTx(args)
 }
 
+// BuildSwapTxInput build router contract call input of swap (set args.Input, args.To and args.SwapValue)
+func (b *Bridge) BuildSwapTxInput(args *tokens.BuildTxArgs) error {
+	switch args.SwapType {
+	case tokens.ERC20SwapType:
+		return b.buildERC20SwapTxInput(args)
+	case tokens.NFTSwapType:
+		return b.buildNFTSwapTxInput(args)
+	case tokens.AnyCallSwapType:
+		return b.buildAnyCallSwapTxInput(args)
+	default:
+		return tokens.ErrSwapTypeNotSupported
+	}
+}
+
 func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
 	var (
 		to        = common.HexToAddress(args.To)
@@ -248,6 +257,50 @@ func (b *Bridge) getGasPrice(args *tokens.BuildTxArgs) (price *big.Int, err erro
 	return price, nil
 }
 
+// setFeeGasPrice set dest gas price used by gas scaled fee policy.
+// server records its current gas price in args (keep the recorded one when rebuild),
+// oracle checks the recorded one is within tolerance of its own gas price.
+func (b *Bridge) setFeeGasPrice(args *tokens.BuildTxArgs) error {
+	if args.ERC20SwapInfo == nil {
+		return nil
+	}
+	policyCfg := tokens.GetGasScaledFeePolicyConfig(args.ERC20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if policyCfg == nil {
+		return nil
+	}
+	var feeGasPrice *big.Int
+	if args.Extra != nil {
+		feeGasPrice = args.Extra.FeeGasPrice
+	}
+	if feeGasPrice != nil && params.IsSwapServer {
+		return nil
+	}
+	gasPrice, err := b.getFeeGasPrice()
+	if err != nil {
+		return err
+	}
+	if !params.IsSwapServer {
+		return tokens.CheckFeeGasPrice(policyCfg, feeGasPrice, gasPrice)
+	}
+	getOrInitEthExtra(args)
+	args.Extra.FeeGasPrice = gasPrice
+	return nil
+}
+
+func (b *Bridge) getFeeGasPrice() (price *big.Int, err error) {
+	if fixedGasPrice := params.GetFixedGasPrice(b.ChainConfig.ChainID); fixedGasPrice != nil {
+		return fixedGasPrice, nil
+	}
+	for i := 0; i < retryRPCCount; i++ {
+		price, err = b.SuggestPrice()
+		if err == nil {
+			return price, nil
+		}
+		time.Sleep(retryRPCInterval)
+	}
+	return nil, err
+}
+
 // args and oldGasPrice should be read only
 func (b *Bridge) adjustSwapGasPrice(args *tokens.BuildTxArgs, oldGasPrice *big.Int) (newGasPrice *big.Int, err error) {
 	serverCfg := params.GetRouterServerConfig()
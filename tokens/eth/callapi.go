@@ -25,6 +25,7 @@ var (
 	errTxHashMismatch         = errors.New("tx hash mismatch with rpc result")
 	errTxBlockHashMismatch    = errors.New("tx block hash mismatch with rpc result")
 	errTxReceiptMissBlockInfo = errors.New("tx receipt missing block info")
+	errTxNotConfirmed         = errors.New("tx is not confirmed by independent gateways")
 
 	wrapRPCQueryError = tokens.WrapRPCQueryError
 )
@@ -213,6 +214,41 @@ func (b *Bridge) getTransactionReceipt(txHash string, urls []string) (result *ty
 	return nil, "", wrapRPCQueryError(err, "eth_getTransactionReceipt", txHash)
 }
 
+// ConfirmTransactionByGateways confirm tx receipt by independent gateways (not in gateway config)
+func (b *Bridge) ConfirmTransactionByGateways(txHash string, urls []string) error {
+	receipt, _, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		return err
+	}
+	gateway := b.GatewayConfig
+	ownURLs := make(map[string]struct{})
+	for _, addrs := range [][]string{gateway.APIAddress, gateway.APIAddressExt} {
+		for _, url := range addrs {
+			ownURLs[url] = struct{}{}
+		}
+	}
+	for _, url := range urls {
+		if _, exist := ownURLs[url]; exist {
+			continue
+		}
+		var result *types.RPCTxReceipt
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "eth_getTransactionReceipt", txHash)
+		if err != nil || result == nil || result.BlockHash == nil {
+			log.Warn("confirm tx by gateway failed", "txHash", txHash, "url", url, "err", err)
+			continue
+		}
+		if *result.BlockHash != *receipt.BlockHash {
+			return fmt.Errorf("%w, block hash mismatch: %v != %v", errTxNotConfirmed, result.BlockHash.Hex(), receipt.BlockHash.Hex())
+		}
+		if result.IsStatusOk() != receipt.IsStatusOk() || len(result.Logs) != len(receipt.Logs) {
+			return fmt.Errorf("%w, receipt status or logs mismatch", errTxNotConfirmed)
+		}
+		return nil
+	}
+	// no gateway answered, we can not judge now
+	return fmt.Errorf("%w: no confirm gateway answered, tx %v", tokens.ErrRPCQueryError, txHash)
+}
+
 func (b *Bridge) checkTxBlockHash(blockNumber *big.Int, blockHash common.Hash) error {
 	block, err := b.GetBlockByNumber(blockNumber)
 	if err != nil {
@@ -0,0 +1,75 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func newTestReceiptServer(blockHash string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case req.Method == "eth_getTransactionReceipt" && blockHash != "":
+			resp["result"] = map[string]interface{}{
+				"transactionHash":  tRegisterTxHash,
+				"transactionIndex": "0x0",
+				"blockNumber":      "0x64",
+				"blockHash":        blockHash,
+				"status":           "0x1",
+				"logs":             []interface{}{},
+			}
+		case req.Method == "eth_getTransactionReceipt":
+			resp["result"] = nil
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestConfirmTransactionByGateways(t *testing.T) {
+	const otherBlockHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+
+	ownServer := newTestReceiptServer(tRegisterTxHash)
+	defer ownServer.Close()
+	sameServer := newTestReceiptServer(tRegisterTxHash)
+	defer sameServer.Close()
+	otherServer := newTestReceiptServer(otherBlockHash)
+	defer otherServer.Close()
+	emptyServer := newTestReceiptServer("")
+	defer emptyServer.Close()
+	deadServer := newTestReceiptServer(tRegisterTxHash)
+	deadServer.Close()
+
+	b := NewCrossChainBridge()
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{ownServer.URL}})
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "testBlockChain"}
+	b.RPCClientTimeout = 1
+
+	testCases := []struct {
+		urls    []string
+		wantErr error
+	}{
+		{[]string{sameServer.URL}, nil},
+		{[]string{deadServer.URL, emptyServer.URL, sameServer.URL}, nil},
+		{[]string{otherServer.URL}, errTxNotConfirmed},
+		{[]string{deadServer.URL, emptyServer.URL}, tokens.ErrRPCQueryError},
+		{[]string{ownServer.URL}, tokens.ErrRPCQueryError},
+	}
+	for i, tc := range testCases {
+		err := b.ConfirmTransactionByGateways(tRegisterTxHash, tc.urls)
+		if tc.wantErr == nil && err != nil || tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+			t.Errorf("test case %v: want error %v, have %v", i, tc.wantErr, err)
+		}
+	}
+}
@@ -44,57 +44,6 @@ var (
 	nft721SwapInWithDataFuncHash = common.FromHex("2cbba1a4")
 )
 
-// nolint:dupl // ok
-func (b *Bridge) registerNFTSwapTx(txHash string, logIndex int) ([]*tokens.SwapTxInfo, []error) {
-	commonInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{NFTSwapInfo: &tokens.NFTSwapInfo{}}}
-	commonInfo.SwapType = tokens.NFTSwapType  // SwapType
-	commonInfo.Hash = strings.ToLower(txHash) // Hash
-	commonInfo.LogIndex = logIndex            // LogIndex
-
-	receipt, err := b.getSwapTxReceipt(commonInfo, true)
-	if err != nil {
-		return []*tokens.SwapTxInfo{commonInfo}, []error{err}
-	}
-
-	swapInfos := make([]*tokens.SwapTxInfo, 0)
-	errs := make([]error, 0)
-	startIndex, endIndex := 1, len(receipt.Logs)
-
-	if logIndex != 0 {
-		if logIndex >= endIndex || logIndex < 0 {
-			return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrLogIndexOutOfRange}
-		}
-		startIndex = logIndex
-		endIndex = logIndex + 1
-	}
-
-	for i := startIndex; i < endIndex; i++ {
-		swapInfo := &tokens.SwapTxInfo{}
-		*swapInfo = *commonInfo
-		swapInfo.NFTSwapInfo = &tokens.NFTSwapInfo{}
-		swapInfo.LogIndex = i // LogIndex
-		err := b.verifyNFTSwapTxLog(swapInfo, receipt.Logs[i])
-		switch {
-		case errors.Is(err, tokens.ErrSwapoutLogNotFound),
-			errors.Is(err, tokens.ErrTxWithWrongTopics),
-			errors.Is(err, tokens.ErrTxWithWrongContract):
-			continue
-		case err == nil:
-			err = b.checkNFTSwapInfo(swapInfo)
-		default:
-			log.Debug(b.ChainConfig.BlockChain+" register nft swap error", "txHash", txHash, "logIndex", swapInfo.LogIndex, "err", err)
-		}
-		swapInfos = append(swapInfos, swapInfo)
-		errs = append(errs, err)
-	}
-
-	if len(swapInfos) == 0 {
-		return []*tokens.SwapTxInfo{commonInfo}, []error{tokens.ErrSwapoutLogNotFound}
-	}
-
-	return swapInfos, errs
-}
-
 func (b *Bridge) verifyNFTSwapTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
 	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{NFTSwapInfo: &tokens.NFTSwapInfo{}}}
 	swapInfo.SwapType = tokens.NFTSwapType  // SwapType
@@ -106,7 +55,7 @@ func (b *Bridge) verifyNFTSwapTx(txHash string, logIndex int, allowUnstable bool
 		return swapInfo, err
 	}
 
-	if logIndex >= len(receipt.Logs) {
+	if logIndex >= len(receipt.Logs) || logIndex < 0 {
 		return swapInfo, tokens.ErrLogIndexOutOfRange
 	}
 
@@ -136,6 +85,9 @@ func (b *Bridge) verifyNFTSwapTx(txHash string, logIndex int, allowUnstable bool
 func (b *Bridge) verifyNFTSwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog) (err error) {
 	swapInfo.To = rlog.Address.LowerHex() // To
 
+	if len(rlog.Topics) == 0 {
+		return tokens.ErrSwapoutLogNotFound
+	}
 	logTopic := rlog.Topics[0].Bytes()
 	if params.IsNFTSwapWithData() {
 		switch {
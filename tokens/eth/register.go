@@ -6,6 +6,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
 )
 
 // RegisterSwap api
@@ -25,12 +26,51 @@ func (b *Bridge) RegisterSwap(txHash string, args *tokens.RegisterArgs) ([]*toke
 	}
 }
 
-// nolint:dupl // ok
 func (b *Bridge) registerERC20SwapTx(txHash string, logIndex int) ([]*tokens.SwapTxInfo, []error) {
-	commonInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
-	commonInfo.SwapType = tokens.ERC20SwapType // SwapType
-	commonInfo.Hash = strings.ToLower(txHash)  // Hash
-	commonInfo.LogIndex = logIndex             // LogIndex
+	return b.registerSwapTxLogs(txHash, logIndex, tokens.ERC20SwapType,
+		func() tokens.SwapInfo {
+			return tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}
+		},
+		b.verifyERC20SwapTxLog,
+		b.checkERC20SwapInfo,
+	)
+}
+
+func (b *Bridge) registerNFTSwapTx(txHash string, logIndex int) ([]*tokens.SwapTxInfo, []error) {
+	return b.registerSwapTxLogs(txHash, logIndex, tokens.NFTSwapType,
+		func() tokens.SwapInfo {
+			return tokens.SwapInfo{NFTSwapInfo: &tokens.NFTSwapInfo{}}
+		},
+		b.verifyNFTSwapTxLog,
+		b.checkNFTSwapInfo,
+	)
+}
+
+func (b *Bridge) registerAnyCallSwapTx(txHash string, logIndex int) ([]*tokens.SwapTxInfo, []error) {
+	return b.registerSwapTxLogs(txHash, logIndex, tokens.AnyCallSwapType,
+		func() tokens.SwapInfo {
+			return tokens.SwapInfo{} // set when parsing log
+		},
+		b.verifyAnyCallSwapTxLog,
+		b.checkAnyCallSwapInfo,
+	)
+}
+
+// registerSwapTxLogs verify every swap log in the tx (a tx may emit many swap logs,
+// eg. a batch contract calls the router several times) and return each result.
+// if logIndex is 0 then check all logs (from index 1, as the swap log always
+// follows the token burn or transfer log), otherwise only check the specified log.
+// logs which are not swap logs of the router contract are skipped.
+func (b *Bridge) registerSwapTxLogs(
+	txHash string, logIndex int, swapType tokens.SwapType,
+	newSwapInfo func() tokens.SwapInfo,
+	verifyLog func(*tokens.SwapTxInfo, *types.RPCLog) error,
+	checkSwapInfo func(*tokens.SwapTxInfo) error,
+) ([]*tokens.SwapTxInfo, []error) {
+	commonInfo := &tokens.SwapTxInfo{SwapInfo: newSwapInfo()}
+	commonInfo.SwapType = swapType            // SwapType
+	commonInfo.Hash = strings.ToLower(txHash) // Hash
+	commonInfo.LogIndex = logIndex            // LogIndex
 
 	receipt, err := b.getSwapTxReceipt(commonInfo, true)
 	if err != nil {
@@ -50,20 +90,24 @@ func (b *Bridge) registerERC20SwapTx(txHash string, logIndex int) ([]*tokens.Swa
 	}
 
 	for i := startIndex; i < endIndex; i++ {
+		rlog := receipt.Logs[i]
+		if rlog == nil || rlog.Address == nil {
+			continue
+		}
 		swapInfo := &tokens.SwapTxInfo{}
 		*swapInfo = *commonInfo
-		swapInfo.ERC20SwapInfo = &tokens.ERC20SwapInfo{}
+		swapInfo.SwapInfo = newSwapInfo()
 		swapInfo.LogIndex = i // LogIndex
-		err = b.verifyERC20SwapTxLog(swapInfo, receipt.Logs[i])
+		err = verifyLog(swapInfo, rlog)
 		switch {
 		case errors.Is(err, tokens.ErrSwapoutLogNotFound),
 			errors.Is(err, tokens.ErrTxWithWrongTopics),
 			errors.Is(err, tokens.ErrTxWithWrongContract):
 			continue
 		case err == nil:
-			err = b.checkERC20SwapInfo(swapInfo)
+			err = checkSwapInfo(swapInfo)
 		default:
-			log.Debug(b.ChainConfig.BlockChain+" register router swap error", "txHash", txHash, "logIndex", swapInfo.LogIndex, "err", err)
+			log.Debug(b.ChainConfig.BlockChain+" register "+swapType.String()+" error", "txHash", txHash, "logIndex", swapInfo.LogIndex, "err", err)
 		}
 		swapInfos = append(swapInfos, swapInfo)
 		errs = append(errs, err)
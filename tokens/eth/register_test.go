@@ -0,0 +1,112 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const tRegisterTxHash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+
+func TestRegisterSwapWithManyLogs(t *testing.T) {
+	swapoutLog := map[string]interface{}{
+		"address": tRouterAddress,
+		"data":    consArgsSlice[0].args[4],
+		"topics":  consArgsSlice[0].args[6:],
+	}
+	wrongContractLog := map[string]interface{}{
+		"address": tWrongContract,
+		"data":    consArgsSlice[0].args[4],
+		"topics":  consArgsSlice[0].args[6:],
+	}
+	otherLog := map[string]interface{}{
+		"address": tTokenAddress,
+		"data":    "0x",
+		"topics":  []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+	}
+	anonymousLog := map[string]interface{}{
+		"address": tTokenAddress,
+		"data":    "0x",
+		"topics":  []string{},
+	}
+	receipt := map[string]interface{}{
+		"transactionHash":  tRegisterTxHash,
+		"transactionIndex": "0x0",
+		"blockNumber":      "0x64",
+		"blockHash":        tRegisterTxHash,
+		"status":           "0x1",
+		"from":             "0x1111111111111111111111111111111111111111",
+		"to":               tRouterAddress,
+		"logs":             []interface{}{otherLog, swapoutLog, anonymousLog, wrongContractLog, swapoutLog},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_getTransactionReceipt":
+			resp["result"] = receipt
+		case "eth_blockNumber":
+			resp["result"] = "0x70"
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain:     "testBlockChain",
+		RouterContract: tRouterAddress,
+	}
+	b.SetTokenConfig(tTokenAddress, &tokens.TokenConfig{
+		TokenID:         "testTokenID",
+		ContractAddress: tTokenAddress,
+	})
+
+	swapInfos, errs := b.RegisterSwap(tRegisterTxHash, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if len(swapInfos) != 2 || len(errs) != 2 {
+		t.Fatalf("register swap should return every swapout log, have %v logs", len(swapInfos))
+	}
+	for i, wantIndex := range []int{1, 4} {
+		swapInfo := swapInfos[i]
+		if swapInfo.LogIndex != wantIndex || swapInfo.ERC20SwapInfo == nil ||
+			swapInfo.ERC20SwapInfo.TokenID != "testTokenID" || swapInfo.Height != 100 {
+			t.Errorf("wrong swap info of log %v: %+v", wantIndex, swapInfo)
+		}
+	}
+	if swapInfos[0].ERC20SwapInfo == swapInfos[1].ERC20SwapInfo {
+		t.Errorf("swap infos of different logs should not share erc20 swap info")
+	}
+
+	for logIndex, wantErr := range map[int]error{
+		2:  tokens.ErrSwapoutLogNotFound,
+		3:  tokens.ErrSwapoutLogNotFound,
+		5:  tokens.ErrLogIndexOutOfRange,
+		-1: tokens.ErrLogIndexOutOfRange,
+	} {
+		_, errs = b.RegisterSwap(tRegisterTxHash, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType, LogIndex: logIndex})
+		if len(errs) != 1 || !errors.Is(errs[0], wantErr) {
+			t.Errorf("register log %v, have errors %v, want %v", logIndex, errs, wantErr)
+		}
+	}
+	receipt["logs"] = []interface{}{swapoutLog, otherLog, swapoutLog}
+	swapInfos, _ = b.RegisterSwap(tRegisterTxHash, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if len(swapInfos) != 1 || swapInfos[0].LogIndex != 2 {
+		t.Errorf("register all logs should start from log index 1, have %v logs", len(swapInfos))
+	}
+	receipt["logs"] = []interface{}{otherLog, swapoutLog, anonymousLog, wrongContractLog, swapoutLog}
+
+	if _, err := b.VerifyTransaction(tRegisterTxHash, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: 2, AllowUnstable: true}); !errors.Is(err, tokens.ErrSwapoutLogNotFound) {
+		t.Errorf("verify anonymous log, have error %v, want %v", err, tokens.ErrSwapoutLogNotFound)
+	}
+}
@@ -0,0 +1,105 @@
+package eth
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+// GetSwapLogTopics get swap log topics of router swap type
+func GetSwapLogTopics(swapType tokens.SwapType) []common.Hash {
+	var topics [][]byte
+	switch swapType {
+	case tokens.ERC20SwapType:
+		topics = [][]byte{
+			LogAnySwapOutTopic,
+			LogAnySwapOut2Topic,
+			LogAnySwapOutAndCallTopic,
+			LogAnySwapTradeTokensForTokensTopic,
+			LogAnySwapTradeTokensForNativeTopic,
+		}
+	case tokens.NFTSwapType:
+		topics = [][]byte{
+			LogNFT721SwapOutTopic,
+			LogNFT1155SwapOutTopic,
+			LogNFT1155SwapOutBatchTopic,
+			LogNFT721SwapOutWithDataTopic,
+		}
+	case tokens.AnyCallSwapType:
+		topics = [][]byte{
+			LogAnyCallTopic,
+			LogCurveAnyCallTopic,
+		}
+	default:
+		return nil
+	}
+	result := make([]common.Hash, len(topics))
+	for i, topic := range topics {
+		result[i] = common.BytesToHash(topic)
+	}
+	return result
+}
+
+// getScanRouterContracts get router contracts of chain and tokens to scan
+func (b *Bridge) getScanRouterContracts(swapType tokens.SwapType) []common.Address {
+	var contracts []common.Address
+	exist := make(map[string]bool)
+	addContract := func(contract string) {
+		key := strings.ToLower(contract)
+		if contract == "" || exist[key] {
+			return
+		}
+		exist[key] = true
+		contracts = append(contracts, common.HexToAddress(contract))
+	}
+	addContract(b.ChainConfig.RouterContract)
+	if swapType == tokens.ERC20SwapType {
+		b.TokenConfigMap.Range(func(k, v interface{}) bool {
+			addContract(v.(*tokens.TokenConfig).RouterContract)
+			return true
+		})
+	}
+	return contracts
+}
+
+// ScanSwapTxs scan swap txs of router contracts in block range [fromHeight, toHeight]
+func (b *Bridge) ScanSwapTxs(fromHeight, toHeight uint64) (txHashes []string, err error) {
+	return b.scanSwapTxs(fromHeight, toHeight, tokens.GetRouterSwapType())
+}
+
+func (b *Bridge) scanSwapTxs(fromHeight, toHeight uint64, swapType tokens.SwapType) (txHashes []string, err error) {
+	contracts := b.getScanRouterContracts(swapType)
+	topics := GetSwapLogTopics(swapType)
+	if len(contracts) == 0 || len(topics) == 0 {
+		return nil, nil
+	}
+	filter := &types.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromHeight),
+		ToBlock:   new(big.Int).SetUint64(toHeight),
+		Addresses: contracts,
+		Topics:    [][]common.Hash{topics},
+	}
+	logs, err := b.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+	exist := make(map[string]bool)
+	for _, rlog := range logs {
+		if rlog == nil || rlog.TxHash == nil {
+			continue
+		}
+		if rlog.Removed != nil && *rlog.Removed {
+			continue
+		}
+		txHash := rlog.TxHash.Hex()
+		if exist[txHash] {
+			continue
+		}
+		exist[txHash] = true
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes, nil
+}
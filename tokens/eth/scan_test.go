@@ -0,0 +1,84 @@
+package eth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestScanSwapTxs(t *testing.T) {
+	const (
+		txHash1 = "0x1111111111111111111111111111111111111111111111111111111111111111"
+		txHash2 = "0x2222222222222222222222222222222222222222222222222222222222222222"
+		txHash3 = "0x3333333333333333333333333333333333333333333333333333333333333333"
+	)
+	newLog := func(txHash string, removed bool) map[string]interface{} {
+		return map[string]interface{}{
+			"address":         tRouterAddress,
+			"data":            "0x",
+			"topics":          []string{"0x97116cf6cd4f6412bb47914d6db18da9e16ab2142f543b86e207c24fbd16b23a"},
+			"removed":         removed,
+			"transactionHash": txHash,
+			"blockNumber":     "0x64",
+		}
+	}
+	var filter map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_getLogs":
+			_ = json.Unmarshal(req.Params[0], &filter)
+			resp["result"] = []interface{}{
+				newLog(txHash1, false), newLog(txHash2, false), newLog(txHash1, false), newLog(txHash3, true),
+			}
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge()
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{server.URL}})
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain:     "testBlockChain",
+		RouterContract: tRouterAddress,
+	}
+	b.CrossChainBridgeBase.SetTokenConfig(tTokenAddress, &tokens.TokenConfig{
+		TokenID:         "testTokenID",
+		ContractAddress: tTokenAddress,
+		RouterContract:  tWrongContract,
+	})
+
+	txHashes, err := b.scanSwapTxs(100, 199, tokens.ERC20SwapType)
+	if err != nil {
+		t.Fatalf("scan swap txs failed: %v", err)
+	}
+	if len(txHashes) != 2 || txHashes[0] != txHash1 || txHashes[1] != txHash2 {
+		t.Errorf("scan swap txs should dedup and skip removed logs, have %v", txHashes)
+	}
+
+	if filter["fromBlock"] != "0x64" || filter["toBlock"] != "0xc7" {
+		t.Errorf("wrong block range in filter: %v", filter)
+	}
+	addresses, _ := filter["address"].([]interface{})
+	if len(addresses) != 2 ||
+		!strings.EqualFold(addresses[0].(string), tRouterAddress) ||
+		!strings.EqualFold(addresses[1].(string), tWrongContract) {
+		t.Errorf("wrong router contracts in filter: %v", filter["address"])
+	}
+	topics, _ := filter["topics"].([]interface{})
+	if len(topics) != 1 || len(topics[0].([]interface{})) != len(GetSwapLogTopics(tokens.ERC20SwapType)) {
+		t.Errorf("wrong topics in filter: %v", filter["topics"])
+	}
+}
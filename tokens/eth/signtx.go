@@ -72,6 +72,11 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	if err != nil {
 		return nil, "", err
 	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
 	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash.String())
 
 	if len(rsvs) != 1 {
@@ -131,23 +136,37 @@ func (b *Bridge) GetSignedTxHashOfKeyID(sender, keyID string, rawTx interface{})
 	if err != nil {
 		return "", err
 	}
+	_, txHash, err = b.getSignedTxWithRsvs(sender, tx, rsvs)
+	if err != nil {
+		return "", fmt.Errorf("%w of keyID %v", err, keyID)
+	}
+	return txHash, nil
+}
+
+// GetSignedTxWithRsvs get signed tx by rsvs of finished mpc sign (used to resume sign request)
+func (b *Bridge) GetSignedTxWithRsvs(sender string, rawTx interface{}, rsvs []string) (signedTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, "", errors.New("wrong raw tx param")
+	}
+	return b.getSignedTxWithRsvs(sender, tx, rsvs)
+}
+
+func (b *Bridge) getSignedTxWithRsvs(sender string, tx *types.Transaction, rsvs []string) (*types.Transaction, string, error) {
 	if len(rsvs) != 1 {
-		return "", errors.New("wrong number of rsvs of keyID " + keyID)
+		return nil, "", errors.New("wrong number of rsvs")
 	}
 
-	rsv := rsvs[0]
-	signature := common.FromHex(rsv)
+	signature := common.FromHex(rsvs[0])
 	if len(signature) != crypto.SignatureLength {
-		return "", errors.New("wrong signature of keyID " + keyID)
+		return nil, "", errors.New("wrong signature")
 	}
 
 	signedTx, err := b.signTxWithSignature(tx, signature, common.HexToAddress(sender))
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
-
-	txHash = signedTx.Hash().String()
-	return txHash, nil
+	return signedTx, signedTx.Hash().String(), nil
 }
 
 // SignTransactionWithPrivateKey sign tx with private key (use for testing)
@@ -43,7 +43,7 @@ func (b *Bridge) verifyERC20SwapTx(txHash string, logIndex int, allowUnstable bo
 		return swapInfo, err
 	}
 
-	if logIndex >= len(receipt.Logs) {
+	if logIndex >= len(receipt.Logs) || logIndex < 0 {
 		return swapInfo, tokens.ErrLogIndexOutOfRange
 	}
 
@@ -213,6 +213,9 @@ func (b *Bridge) checkCallByContract(swapInfo *tokens.SwapTxInfo) error {
 func (b *Bridge) verifyERC20SwapTxLog(swapInfo *tokens.SwapTxInfo, rlog *types.RPCLog) (err error) {
 	swapInfo.To = rlog.Address.LowerHex() // To
 
+	if len(rlog.Topics) == 0 {
+		return tokens.ErrSwapoutLogNotFound
+	}
 	logTopic := rlog.Topics[0].Bytes()
 	switch {
 	case bytes.Equal(logTopic, LogAnySwapOutTopic):
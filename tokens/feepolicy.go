@@ -0,0 +1,347 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// fee policy types
+const (
+	DefaultFeePolicyType   = "default"
+	TieredFeePolicyType    = "tiered"
+	FlatFeePolicyType      = "flat"
+	GasScaledFeePolicyType = "gasscaled"
+)
+
+var (
+	million = big.NewInt(1000000)
+	ether   = big.NewInt(1e18)
+
+	feePolicyRegistryLock sync.RWMutex
+	feePolicyFactories    = make(map[string]FeePolicyFactory) // key is lower case policy type
+
+	errWrongFeePolicyValue = errors.New("wrong value in fee policy config")
+	errFeeGasPriceMismatch = errors.New("fee gas price is out of tolerance")
+)
+
+// FeeContext context of calculating swap fee
+type FeeContext struct {
+	TokenID      string
+	ToChainID    string
+	Value        *big.Int
+	FromDecimals uint8
+	OriginFrom   string
+	OriginTxTo   string
+	OriginTime   uint64   // timestamp of source tx (unix seconds), 0 means now
+	GasPrice     *big.Int // gas price (wei) of dest chain, nil means unknown
+	SwapConfig   *SwapConfig
+
+	// FeeInfo records the policy inputs (filled by fee policy)
+	FeeInfo *SwapFeeInfo
+}
+
+// SwapFeeInfo charged swap fee and the policy inputs
+type SwapFeeInfo struct {
+	Policy            string   `json:"policy"`
+	SwapFee           *big.Int `json:"swapFee"` // in unit of from token
+	FeeRatePerMillion uint64   `json:"feeRatePerMillion,omitempty"`
+	MinimumSwapFee    *big.Int `json:"minimumSwapFee,omitempty"`
+	MaximumSwapFee    *big.Int `json:"maximumSwapFee,omitempty"`
+	BaseFeePercent    int64    `json:"baseFeePercent,omitempty"`
+	AdjustBaseFee     *big.Int `json:"adjustBaseFee,omitempty"`
+	GasFee            *big.Int `json:"gasFee,omitempty"`
+	GasPrice          *big.Int `json:"gasPrice,omitempty"`
+	InWhitelist       bool     `json:"inWhitelist,omitempty"`
+	InPromoWindow     bool     `json:"inPromoWindow,omitempty"`
+	FromDecimals      uint8    `json:"fromDecimals"`
+	ToDecimals        uint8    `json:"toDecimals"`
+}
+
+func (ctx *FeeContext) feeInfo() *SwapFeeInfo {
+	if ctx.FeeInfo == nil {
+		ctx.FeeInfo = &SwapFeeInfo{}
+	}
+	return ctx.FeeInfo
+}
+
+// FeePolicy swap fee policy
+type FeePolicy interface {
+	// CalcSwapFee calc swap fee in unit of from token (with `FromDecimals`)
+	CalcSwapFee(ctx *FeeContext) (*big.Int, error)
+}
+
+// FeePolicyFactory create fee policy from config
+type FeePolicyFactory func(cfg *params.FeePolicyConfig) (FeePolicy, error)
+
+func init() {
+	RegisterFeePolicy(DefaultFeePolicyType, func(*params.FeePolicyConfig) (FeePolicy, error) {
+		return &DefaultFeePolicy{}, nil
+	})
+	RegisterFeePolicy(TieredFeePolicyType, func(cfg *params.FeePolicyConfig) (FeePolicy, error) {
+		return &TieredFeePolicy{Tiers: cfg.Tiers}, nil
+	})
+	RegisterFeePolicy(FlatFeePolicyType, func(cfg *params.FeePolicyConfig) (FeePolicy, error) {
+		return &FlatFeePolicy{FlatFee: cfg.FlatFee}, nil
+	})
+	RegisterFeePolicy(GasScaledFeePolicyType, newGasScaledFeePolicy)
+}
+
+// RegisterFeePolicy register fee policy factory of policy type
+func RegisterFeePolicy(policyType string, factory FeePolicyFactory) {
+	key := strings.ToLower(policyType)
+	if key == "" || factory == nil {
+		log.Fatal("register fee policy with empty type or factory", "type", policyType)
+	}
+	feePolicyRegistryLock.Lock()
+	defer feePolicyRegistryLock.Unlock()
+	feePolicyFactories[key] = factory
+}
+
+// NewFeePolicy new fee policy from config, nil config means the default policy
+func NewFeePolicy(cfg *params.FeePolicyConfig) (FeePolicy, error) {
+	if cfg == nil {
+		return &DefaultFeePolicy{}, nil
+	}
+	policyType := strings.ToLower(cfg.Type)
+	if policyType == "" {
+		policyType = DefaultFeePolicyType
+	}
+	feePolicyRegistryLock.RLock()
+	factory, exist := feePolicyFactories[policyType]
+	feePolicyRegistryLock.RUnlock()
+	if !exist {
+		return nil, fmt.Errorf("unknown fee policy type '%v'", cfg.Type)
+	}
+	policy, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.PromoWindows) > 0 {
+		policy = &PromoFeePolicy{Windows: cfg.PromoWindows, Policy: policy}
+	}
+	return policy, nil
+}
+
+// GetFeePolicy get fee policy of tokenID and dest chain
+func GetFeePolicy(tokenID, toChainID string) (FeePolicy, error) {
+	return NewFeePolicy(params.GetFeePolicyConfig(tokenID, toChainID))
+}
+
+func isInBigValueWhitelist(ctx *FeeContext) bool {
+	return params.IsInBigValueWhitelist(ctx.TokenID, ctx.OriginFrom) ||
+		params.IsInBigValueWhitelist(ctx.TokenID, ctx.OriginTxTo)
+}
+
+// calcRateSwapFee calc swap fee by rate, clamped by min and max swap fee,
+// and adjusted by base fee percent of dest chain
+func calcRateSwapFee(ctx *FeeContext, feeRatePerMillion uint64) *big.Int {
+	swapCfg := ctx.SwapConfig
+	minSwapFee := ConvertTokenValue(swapCfg.MinimumSwapFee, 18, ctx.FromDecimals)
+	maxSwapFee := ConvertTokenValue(swapCfg.MaximumSwapFee, 18, ctx.FromDecimals)
+
+	feeInfo := ctx.feeInfo()
+	feeInfo.FeeRatePerMillion = feeRatePerMillion
+	feeInfo.MinimumSwapFee = minSwapFee
+	feeInfo.MaximumSwapFee = maxSwapFee
+
+	if isInBigValueWhitelist(ctx) {
+		feeInfo.InWhitelist = true
+		return minSwapFee
+	}
+
+	swapFee := new(big.Int).Mul(ctx.Value, new(big.Int).SetUint64(feeRatePerMillion))
+	swapFee.Div(swapFee, million)
+
+	if swapFee.Cmp(minSwapFee) < 0 {
+		swapFee = minSwapFee
+	} else if swapFee.Cmp(maxSwapFee) > 0 {
+		swapFee = maxSwapFee
+	}
+
+	baseFeePercent := params.GetBaseFeePercent(ctx.ToChainID)
+	if baseFeePercent != 0 && minSwapFee.Sign() > 0 {
+		adjustBaseFee := new(big.Int).Set(minSwapFee)
+		adjustBaseFee.Mul(adjustBaseFee, big.NewInt(baseFeePercent))
+		adjustBaseFee.Div(adjustBaseFee, big.NewInt(100))
+		feeInfo.BaseFeePercent = baseFeePercent
+		feeInfo.AdjustBaseFee = adjustBaseFee
+		swapFee = new(big.Int).Add(swapFee, adjustBaseFee)
+		if swapFee.Sign() < 0 {
+			swapFee = big.NewInt(0)
+		}
+	}
+	return swapFee
+}
+
+// DefaultFeePolicy fee rate per million of swap config,
+// clamped by min and max swap fee, and adjusted by base fee percent
+type DefaultFeePolicy struct{}
+
+// CalcSwapFee impl
+func (p *DefaultFeePolicy) CalcSwapFee(ctx *FeeContext) (*big.Int, error) {
+	if ctx.SwapConfig.SwapFeeRatePerMillion == 0 {
+		return big.NewInt(0), nil
+	}
+	return calcRateSwapFee(ctx, ctx.SwapConfig.SwapFeeRatePerMillion), nil
+}
+
+// TieredFeePolicy fee rate by the tier of swap value,
+// the tier with the largest `MinValue` not above swap value is used
+type TieredFeePolicy struct {
+	Tiers []*params.FeeTierConfig
+}
+
+// CalcSwapFee impl
+func (p *TieredFeePolicy) CalcSwapFee(ctx *FeeContext) (*big.Int, error) {
+	type tier struct {
+		minValue *big.Int
+		rate     uint64
+	}
+	tiers := make([]tier, 0, len(p.Tiers))
+	for _, t := range p.Tiers {
+		minValue := ToBits(t.MinValue, ctx.FromDecimals)
+		if minValue == nil {
+			return nil, errWrongFeePolicyValue
+		}
+		tiers = append(tiers, tier{minValue: minValue, rate: t.SwapFeeRatePerMillion})
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].minValue.Cmp(tiers[j].minValue) < 0
+	})
+	var rate uint64
+	for _, t := range tiers {
+		if ctx.Value.Cmp(t.minValue) < 0 {
+			break
+		}
+		rate = t.rate
+	}
+	if rate == 0 {
+		return big.NewInt(0), nil
+	}
+	return calcRateSwapFee(ctx, rate), nil
+}
+
+// FlatFeePolicy flat fee for every swap
+type FlatFeePolicy struct {
+	FlatFee string
+}
+
+// CalcSwapFee impl
+func (p *FlatFeePolicy) CalcSwapFee(ctx *FeeContext) (*big.Int, error) {
+	fee := ToBits(p.FlatFee, ctx.FromDecimals)
+	if fee == nil {
+		return nil, errWrongFeePolicyValue
+	}
+	return fee, nil
+}
+
+// GasScaledFeePolicy default fee plus the gas cost of dest swap tx,
+// gas cost = GasLimit * GasPrice * NativePrice (dest native coin has 18 decimals).
+// GasPrice is the dest gas price in fee context, which the server records
+// in build args and the oracles check by `CheckFeeGasPrice`.
+// the configed reference gas price is used if dest gas price is unknown (eg. quote).
+type GasScaledFeePolicy struct {
+	GasLimit    uint64
+	GasPrice    *big.Int // reference gas price
+	NativePrice *big.Int // price of dest native coin in this token (with 18 decimals)
+}
+
+func newGasScaledFeePolicy(cfg *params.FeePolicyConfig) (FeePolicy, error) {
+	gasPrice, ok := new(big.Int).SetString(cfg.GasPrice, 10)
+	if !ok {
+		return nil, errWrongFeePolicyValue
+	}
+	nativePrice := ToBits(cfg.NativePrice, 18)
+	if nativePrice == nil {
+		return nil, errWrongFeePolicyValue
+	}
+	return &GasScaledFeePolicy{
+		GasLimit:    cfg.GasLimit,
+		GasPrice:    gasPrice,
+		NativePrice: nativePrice,
+	}, nil
+}
+
+// CalcSwapFee impl
+func (p *GasScaledFeePolicy) CalcSwapFee(ctx *FeeContext) (*big.Int, error) {
+	swapFee, err := (&DefaultFeePolicy{}).CalcSwapFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeInfo := ctx.feeInfo()
+	gasPrice := p.GasPrice
+	if ctx.GasPrice != nil {
+		gasPrice = ctx.GasPrice
+		feeInfo.GasPrice = gasPrice
+	}
+	gasFee := new(big.Int).SetUint64(p.GasLimit)
+	gasFee.Mul(gasFee, gasPrice)
+	gasFee.Mul(gasFee, p.NativePrice)
+	gasFee.Div(gasFee, ether)
+	gasFee = ConvertTokenValue(gasFee, 18, ctx.FromDecimals)
+	feeInfo.GasFee = gasFee
+	return new(big.Int).Add(swapFee, gasFee), nil
+}
+
+// GetGasScaledFeePolicyConfig get fee policy config of tokenID and dest chain
+// if it is gas scaled, otherwise return nil
+func GetGasScaledFeePolicyConfig(tokenID, toChainID string) *params.FeePolicyConfig {
+	cfg := params.GetFeePolicyConfig(tokenID, toChainID)
+	if cfg != nil && strings.EqualFold(cfg.Type, GasScaledFeePolicyType) {
+		return cfg
+	}
+	return nil
+}
+
+// CheckFeeGasPrice check fee gas price (used by server) is within
+// `GasPriceTolerance` percent of the local gas price (of oracle)
+func CheckFeeGasPrice(cfg *params.FeePolicyConfig, feeGasPrice, localGasPrice *big.Int) error {
+	if feeGasPrice == nil || localGasPrice == nil {
+		return errFeeGasPriceMismatch
+	}
+	tolerance := new(big.Int).Mul(localGasPrice, new(big.Int).SetUint64(cfg.GasPriceTolerance))
+	tolerance.Div(tolerance, big.NewInt(100))
+	diff := new(big.Int).Sub(feeGasPrice, localGasPrice)
+	if diff.CmpAbs(tolerance) > 0 {
+		return fmt.Errorf("%w: fee gas price %v, local gas price %v, tolerance %v%%", errFeeGasPriceMismatch, feeGasPrice, localGasPrice, cfg.GasPriceTolerance)
+	}
+	return nil
+}
+
+// PromoFeePolicy zero fee in promotional windows, otherwise use the wrapped policy
+type PromoFeePolicy struct {
+	Windows []*params.PromoWindowConfig
+	Policy  FeePolicy
+}
+
+// IsInPromoWindow is timestamp (unix seconds) in promotional windows
+func (p *PromoFeePolicy) IsInPromoWindow(timestamp int64) bool {
+	for _, window := range p.Windows {
+		if timestamp >= window.Start && timestamp < window.End {
+			return true
+		}
+	}
+	return false
+}
+
+// CalcSwapFee impl (check promotional windows by the source tx time,
+// so that the server and oracles charge the same fee whenever the swap is processed)
+func (p *PromoFeePolicy) CalcSwapFee(ctx *FeeContext) (*big.Int, error) {
+	timestamp := int64(ctx.OriginTime)
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	if p.IsInPromoWindow(timestamp) {
+		ctx.feeInfo().InPromoWindow = true
+		return big.NewInt(0), nil
+	}
+	return p.Policy.CalcSwapFee(ctx)
+}
@@ -0,0 +1,143 @@
+package tokens
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestCalcSwapValueWithFeePolicies(t *testing.T) {
+	oldSwapType := routerSwapType
+	oldExtra := params.GetExtraConfig()
+	defer func() {
+		routerSwapType = oldSwapType
+		_ = params.SetExtraConfig(oldExtra)
+		SetSwapConfigs(new(sync.Map))
+	}()
+	routerSwapType = ERC20SwapType
+
+	swapCfg := &SwapConfig{
+		MaximumSwap:           ToBits("1000000", 18),
+		MinimumSwap:           ToBits("10", 18),
+		BigValueThreshold:     ToBits("100000", 18),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        ToBits("100", 18),
+		MinimumSwapFee:        ToBits("1", 18),
+	}
+	chainCfgs := new(sync.Map)
+	for _, toChainID := range []string{"1", "2", "3", "4", "5"} {
+		chainCfgs.Store(toChainID, swapCfg)
+	}
+	swapCfgs := new(sync.Map)
+	swapCfgs.Store("USDC", chainCfgs)
+	SetSwapConfigs(swapCfgs)
+
+	nowTime := time.Now().Unix()
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		FeePolicies: map[string]*params.FeePolicyConfig{
+			"USDC,2": {
+				Type: "tiered",
+				Tiers: []*params.FeeTierConfig{
+					{MinValue: "10000", SwapFeeRatePerMillion: 500},
+					{MinValue: "0", SwapFeeRatePerMillion: 2000},
+				},
+			},
+			"USDC,3": {Type: "flat", FlatFee: "2.5"},
+			"USDC,4": {Type: "gasscaled", GasLimit: 100000, GasPrice: "10000000000", GasPriceTolerance: 20, NativePrice: "2000"},
+			"USDC,5": {
+				Type:         "flat",
+				FlatFee:      "2.5",
+				PromoWindows: []*params.PromoWindowConfig{{Start: nowTime - 60, End: nowTime + 60}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set extra config failed: %v", err)
+	}
+
+	testCases := []struct {
+		toChainID string
+		value     string
+		want      string
+	}{
+		{"1", "5000", "4995"},        // default: 0.1%
+		{"1", "500", "499"},          // default: min fee
+		{"1", "500000", "499900"},    // default: max fee
+		{"2", "5000", "4990"},        // tiered: 0.2%
+		{"2", "20000", "19990"},      // tiered: 0.05%
+		{"3", "5000", "4997.5"},      // flat
+		{"4", "5000", "4993"},        // gas cost: 5 + 0.001 * 2000
+		{"5", "5000", "5000"},        // promo
+		{"3", "2.5", "0"},            // flat: not enough for fee
+		{"1", "0.000000000001", "0"}, // default: not enough for fee
+	}
+	for _, tc := range testCases {
+		value := ToBits(tc.value, 18)
+		have := CalcSwapValue("USDC", tc.toChainID, value, 18, 6, "", "", 0)
+		want := ToBits(tc.want, 6)
+		if have.Cmp(want) != 0 {
+			t.Errorf("calc swap value of %v to chain %v, want %v, have %v", tc.value, tc.toChainID, want, have)
+		}
+	}
+
+	if swapCfg.MinimumSwapFee.Cmp(ToBits("1", 18)) != 0 {
+		t.Errorf("swap config is modified by fee policy")
+	}
+
+	_, feeInfo := CalcSwapValueWithFee("USDC", "4", ToBits("5000", 18), 18, 6, "", "", 0)
+	if feeInfo == nil || feeInfo.Policy != GasScaledFeePolicyType || feeInfo.GasPrice != nil ||
+		feeInfo.SwapFee.Cmp(ToBits("7", 18)) != 0 || feeInfo.GasFee.Cmp(ToBits("2", 18)) != 0 {
+		t.Errorf("wrong swap fee info of gas scaled policy: %+v", feeInfo)
+	}
+	// gas fee is scaled by dest gas price
+	_, feeInfo = CalcSwapValueWithFeeGasPrice("USDC", "4", ToBits("5000", 18), 18, 6, "", "", 0, big.NewInt(30000000000))
+	if feeInfo == nil || feeInfo.GasPrice == nil || feeInfo.GasPrice.Cmp(big.NewInt(30000000000)) != 0 ||
+		feeInfo.SwapFee.Cmp(ToBits("11", 18)) != 0 || feeInfo.GasFee.Cmp(ToBits("6", 18)) != 0 {
+		t.Errorf("wrong swap fee info of gas scaled policy with gas price: %+v", feeInfo)
+	}
+	_, feeInfo = CalcSwapValueWithFee("USDC", "5", ToBits("5000", 18), 18, 6, "", "", 0)
+	if feeInfo == nil || !feeInfo.InPromoWindow || feeInfo.SwapFee.Sign() != 0 {
+		t.Errorf("wrong swap fee info of promo policy: %+v", feeInfo)
+	}
+
+	// promo window is checked by source tx time rather than now
+	if have := CalcSwapValue("USDC", "5", ToBits("5000", 18), 18, 6, "", "", uint64(nowTime-30)); have.Cmp(ToBits("5000", 6)) != 0 {
+		t.Errorf("swap in promo window should be free of fee, have %v", have)
+	}
+	if have := CalcSwapValue("USDC", "5", ToBits("5000", 18), 18, 6, "", "", uint64(nowTime-120)); have.Cmp(ToBits("4997.5", 6)) != 0 {
+		t.Errorf("swap before promo window should be charged, have %v", have)
+	}
+
+	_, err = NewFeePolicy(&params.FeePolicyConfig{Type: "unknown"})
+	if err == nil {
+		t.Errorf("new fee policy of unknown type should fail")
+	}
+	if CalcSwapValue("USDC", "1", big.NewInt(0), 18, 18, "", "", 0).Sign() != 0 {
+		t.Errorf("calc swap value of zero value should be zero")
+	}
+}
+
+func TestCheckFeeGasPrice(t *testing.T) {
+	cfg := &params.FeePolicyConfig{Type: GasScaledFeePolicyType, GasPriceTolerance: 20}
+	localGasPrice := big.NewInt(100)
+	testCases := []struct {
+		feeGasPrice *big.Int
+		wantErr     bool
+	}{
+		{big.NewInt(100), false},
+		{big.NewInt(80), false},
+		{big.NewInt(120), false},
+		{big.NewInt(79), true},
+		{big.NewInt(121), true},
+		{nil, true},
+	}
+	for _, tc := range testCases {
+		err := CheckFeeGasPrice(cfg, tc.feeGasPrice, localGasPrice)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("check fee gas price %v: want error %v, have %v", tc.feeGasPrice, tc.wantErr, err)
+		}
+	}
+}
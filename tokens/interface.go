@@ -55,6 +55,11 @@ type ISwapTrade interface {
 	GetPairFor(factory, token0, token1 string) (string, error)
 }
 
+// ISwapScanner interface (scan swap txs of router contract in block range)
+type ISwapScanner interface {
+	ScanSwapTxs(fromHeight, toHeight uint64) (txHashes []string, err error)
+}
+
 // NonceSetter interface (for eth-like)
 type NonceSetter interface {
 	GetPoolNonce(address, height string) (uint64, error)
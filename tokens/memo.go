@@ -0,0 +1,33 @@
+package tokens
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+)
+
+// MemoSeparator separator of swapout memo fields
+const MemoSeparator = ":"
+
+// EncodeSwapoutMemo encode swapout memo with format 'bind:toChainID'
+func EncodeSwapoutMemo(bind string, toChainID *big.Int) string {
+	return bind + MemoSeparator + toChainID.String()
+}
+
+// ParseSwapoutMemo parse swapout memo with format 'bind:toChainID'
+// (used by chains which swapout by transfer with memo)
+func ParseSwapoutMemo(memo string) (bind string, toChainID *big.Int, err error) {
+	memo = strings.TrimSpace(memo)
+	pos := strings.LastIndex(memo, MemoSeparator)
+	if pos <= 0 || pos == len(memo)-1 {
+		return "", nil, fmt.Errorf("wrong memo format '%v'", memo)
+	}
+	bind = memo[:pos]
+	toChainID, err = common.GetBigIntFromStr(memo[pos+1:])
+	if err != nil || toChainID.Sign() <= 0 {
+		return "", nil, fmt.Errorf("wrong to chainID in memo '%v'", memo)
+	}
+	return bind, toChainID, nil
+}
@@ -0,0 +1,17 @@
+package tokens
+
+import (
+	"testing"
+)
+
+func TestParseSwapoutMemo(t *testing.T) {
+	bind, toChainID, err := ParseSwapoutMemo(" 0x1111111111111111111111111111111111111111:56 ")
+	if err != nil || bind != "0x1111111111111111111111111111111111111111" || toChainID.Uint64() != 56 {
+		t.Fatalf("parse memo failed. bind %v toChainID %v err %v", bind, toChainID, err)
+	}
+	for _, memo := range []string{"", "abc", ":56", "abc:", "abc:xyz", "abc:-1"} {
+		if _, _, err := ParseSwapoutMemo(memo); err == nil {
+			t.Errorf("parse wrong memo '%v' should fail", memo)
+		}
+	}
+}
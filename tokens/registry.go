@@ -0,0 +1,114 @@
+package tokens
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// DefaultBridgeFamily default chain family if nothing matched (EVM chains)
+const DefaultBridgeFamily = "eth"
+
+// BridgeFactory create a new bridge instance
+type BridgeFactory func() IBridge
+
+type chainIDRange struct {
+	family string
+	from   *big.Int
+	to     *big.Int
+}
+
+var (
+	bridgeRegistryLock sync.RWMutex
+	bridgeFactories    = make(map[string]BridgeFactory) // key is chain family
+	blockChainFamilies = make(map[string]string)        // key is lower case block chain name
+	chainIDRanges      []*chainIDRange
+)
+
+func familyKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// RegisterBridge register bridge factory of chain family,
+// and the block chain names belonging to this family (eg. called in init).
+// a later registration of the same family replaces the previous factory.
+func RegisterBridge(family string, factory BridgeFactory, blockChains ...string) {
+	key := familyKey(family)
+	if key == "" || factory == nil {
+		log.Fatal("register bridge with empty family or factory", "family", family)
+	}
+	bridgeRegistryLock.Lock()
+	defer bridgeRegistryLock.Unlock()
+	bridgeFactories[key] = factory
+	for _, blockChain := range blockChains {
+		blockChainFamilies[familyKey(blockChain)] = key
+	}
+}
+
+// RegisterChainIDRange register chain id range [from, to] to chain family
+func RegisterChainIDRange(family string, from, to *big.Int) {
+	if from == nil || to == nil || from.Cmp(to) > 0 {
+		log.Fatal("register chain id range with wrong range", "family", family, "from", from, "to", to)
+	}
+	bridgeRegistryLock.Lock()
+	defer bridgeRegistryLock.Unlock()
+	chainIDRanges = append(chainIDRanges, &chainIDRange{
+		family: familyKey(family),
+		from:   new(big.Int).Set(from),
+		to:     new(big.Int).Set(to),
+	})
+}
+
+// GetRegisteredBridgeFamilies get registered bridge families
+func GetRegisteredBridgeFamilies() []string {
+	bridgeRegistryLock.RLock()
+	defer bridgeRegistryLock.RUnlock()
+	families := make([]string, 0, len(bridgeFactories))
+	for family := range bridgeFactories {
+		families = append(families, family)
+	}
+	return families
+}
+
+// GetBridgeFamily get chain family by block chain name firstly,
+// then by chain id range, otherwise return the default family.
+func GetBridgeFamily(chainID *big.Int, blockChain string) string {
+	bridgeRegistryLock.RLock()
+	defer bridgeRegistryLock.RUnlock()
+	key := familyKey(blockChain)
+	if key != "" {
+		if _, exist := bridgeFactories[key]; exist {
+			return key
+		}
+		if family, exist := blockChainFamilies[key]; exist {
+			return family
+		}
+	}
+	if chainID != nil {
+		for _, r := range chainIDRanges {
+			if chainID.Cmp(r.from) >= 0 && chainID.Cmp(r.to) <= 0 {
+				return r.family
+			}
+		}
+	}
+	return DefaultBridgeFamily
+}
+
+// NewBridgeOfFamily new bridge of the specified chain family
+func NewBridgeOfFamily(family string) (IBridge, error) {
+	bridgeRegistryLock.RLock()
+	factory, exist := bridgeFactories[familyKey(family)]
+	bridgeRegistryLock.RUnlock()
+	if !exist {
+		return nil, fmt.Errorf("%w: '%v'", ErrNoBridgeForFamily, family)
+	}
+	return factory(), nil
+}
+
+// NewRegisteredBridge new bridge by block chain name or chain id
+func NewRegisteredBridge(chainID *big.Int, blockChain string) (IBridge, error) {
+	return NewBridgeOfFamily(GetBridgeFamily(chainID, blockChain))
+}
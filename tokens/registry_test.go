@@ -0,0 +1,35 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGetBridgeFamily(t *testing.T) {
+	RegisterBridge("testfamily", func() IBridge { return nil }, "TestChain", "TestChain2")
+	RegisterChainIDRange("testfamily", big.NewInt(1000000), big.NewInt(1000099))
+
+	tests := []struct {
+		chainID    int64
+		blockChain string
+		want       string
+	}{
+		{1, "testfamily", "testfamily"},
+		{1, "testchain2", "testfamily"},
+		{1, " TESTCHAIN ", "testfamily"},
+		{1000050, "", "testfamily"},
+		{1000050, "Ethereum", "testfamily"},
+		{1000100, "", DefaultBridgeFamily},
+		{56, "BSC", DefaultBridgeFamily},
+	}
+	for i, test := range tests {
+		got := GetBridgeFamily(big.NewInt(test.chainID), test.blockChain)
+		if got != test.want {
+			t.Errorf("test %v: get bridge family of (%v, %v) want %v but got %v", i, test.chainID, test.blockChain, test.want, got)
+		}
+	}
+
+	if _, err := NewBridgeOfFamily("notregistered"); err == nil {
+		t.Errorf("new bridge of not registered family should fail")
+	}
+}
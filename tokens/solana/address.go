@@ -0,0 +1,62 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/mr-tron/base58"
+)
+
+var (
+	errWrongPublicKeyLength = errors.New("wrong public key length")
+)
+
+// PublicKey solana account address (ed25519 public key or program derived address)
+type PublicKey [ed25519.PublicKeySize]byte
+
+// PublicKeyFromBase58 decode base58 address
+func PublicKeyFromBase58(address string) (pubKey PublicKey, err error) {
+	data, err := base58.Decode(address)
+	if err != nil {
+		return pubKey, err
+	}
+	if len(data) != len(pubKey) {
+		return pubKey, errWrongPublicKeyLength
+	}
+	copy(pubKey[:], data)
+	return pubKey, nil
+}
+
+// String base58 address
+func (k PublicKey) String() string {
+	return base58.Encode(k[:])
+}
+
+// IsValidAddress check address
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := PublicKeyFromBase58(address)
+	return err == nil
+}
+
+// PublicKeyToAddress returns base58 address of ed25519 public key
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	pubKey := common.FromHex(pubKeyHex)
+	if len(pubKey) != ed25519.PublicKeySize {
+		return "", errWrongPublicKeyLength
+	}
+	return base58.Encode(pubKey), nil
+}
+
+// VerifyMPCPubKey verify mpc address and public key is matching
+func (b *Bridge) VerifyMPCPubKey(mpcAddress, mpcPubkey string) error {
+	address, err := b.PublicKeyToAddress(mpcPubkey)
+	if err != nil {
+		return err
+	}
+	if address != mpcAddress {
+		return fmt.Errorf("mpc address %v and public key address %v is not match", mpcAddress, address)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package solana implements the bridge interfaces for solana chain
+// which swap tokens through the anyswap router program.
+//
+// The router contract of chain config is the router program id, and the
+// mpc account which is the authority of the router program is configed by
+// custom key 'routerMPC'. Swapout is recognized by the 'SwapOut' log
+// emitted by the router program, and swapin is an instruction of the router
+// program signed by mpc with ed25519.
+package solana
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ensure Bridge impl tokens.CrossChainBridge
+	_ tokens.IBridge = &Bridge{}
+)
+
+const (
+	// SwapOutLogPrefix prefix of log emitted by router program when user swapout
+	SwapOutLogPrefix = "SwapOut "
+	// SwapInInstruction name of router program instruction to swapin
+	SwapInInstruction = "swapin"
+)
+
+func init() {
+	tokens.RegisterBridge("solana", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	})
+}
+
+// Bridge solana bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	RPCClientTimeout int
+}
+
+// NewCrossChainBridge new bridge
+func NewCrossChainBridge() *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(),
+		RPCClientTimeout:     client.GetDefaultTimeout(false),
+	}
+}
+
+// InitAfterConfig init variables (ie. extra members) after loading config
+func (b *Bridge) InitAfterConfig() {
+	chainID := b.ChainConfig.ChainID
+	if timeout := params.GetRPCClientTimeout(chainID); timeout != 0 {
+		b.RPCClientTimeout = timeout
+	}
+	log.Info("init solana bridge success", "chainID", chainID, "blockChain", b.ChainConfig.BlockChain)
+}
+
+// InitRouterInfo init router info.
+// the router contract of solana chain is the router program id.
+func (b *Bridge) InitRouterInfo(routerContract string) (err error) {
+	if routerContract == "" {
+		return nil
+	}
+	chainID := b.ChainConfig.ChainID
+	log.Info(fmt.Sprintf("[%5v] start init router info", chainID), "routerContract", routerContract)
+	if !b.IsValidAddress(routerContract) {
+		return fmt.Errorf("wrong router program id '%v'", routerContract)
+	}
+	routerMPC := params.GetCustom(chainID, "routerMPC")
+	if !b.IsValidAddress(routerMPC) {
+		return fmt.Errorf("solana bridge must config custom 'routerMPC', have '%v'", routerMPC)
+	}
+	routerMPCPubkey, err := router.GetMPCPubkey(routerMPC)
+	if err != nil {
+		log.Warn("get mpc public key failed", "mpc", routerMPC, "err", err)
+		return err
+	}
+	if err = b.VerifyMPCPubKey(routerMPC, routerMPCPubkey); err != nil {
+		log.Warn("verify mpc public key failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
+		return err
+	}
+	router.SetRouterInfo(
+		routerContract,
+		&router.SwapRouterInfo{
+			RouterMPC: routerMPC,
+		},
+	)
+	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+
+	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
+		"routerContract", routerContract, "routerMPC", routerMPC)
+	return nil
+}
+
+// GetBalance get balance in lamports
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	balance, err := b.GetLamportsBalance(account)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(balance), nil
+}
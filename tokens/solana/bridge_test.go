@@ -0,0 +1,319 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	tTokenID   = "USDC"
+	tFromChain = "1300"
+	tToChain   = "1400"
+	tFinalized = 1000
+	tTxSlot    = 990
+	tTxHash    = "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW"
+	tBlockhash = "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N"
+)
+
+func TestCompactU16(t *testing.T) {
+	for n, want := range map[int]string{
+		0:     "00",
+		127:   "7f",
+		128:   "8001",
+		16383: "ff7f",
+		16384: "808001",
+	} {
+		if have := hex.EncodeToString(appendCompactU16(nil, n)); have != want {
+			t.Errorf("encode compact u16 %v failed. have %v want %v", n, have, want)
+		}
+	}
+}
+
+func TestGetProgramLogs(t *testing.T) {
+	program := "Router1111111111111111111111111111111111111"
+	logs := []string{
+		"Program " + program + " invoke [1]",
+		"Program log: Instruction: SwapOut",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: SwapOut token=fake from=fake to=fake amount=1 toChainID=1",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4000 of 200000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program log: SwapOut token=a from=b to=c amount=10 toChainID=56",
+		"Program " + program + " success",
+		"Program log: SwapOut token=fake from=fake to=fake amount=1 toChainID=1",
+	}
+	programLogs := GetProgramLogs(logs, program)
+	if len(programLogs) != 2 || programLogs[1] != "SwapOut token=a from=b to=c amount=10 toChainID=56" {
+		t.Fatalf("get program logs failed. have %v", programLogs)
+	}
+	swapoutLog, err := ParseSwapOutLog(programLogs[1])
+	if err != nil || swapoutLog.Token != "a" || swapoutLog.From != "b" || swapoutLog.To != "c" ||
+		swapoutLog.Amount.Uint64() != 10 || swapoutLog.ToChainID.Uint64() != 56 {
+		t.Fatalf("parse swapout log failed. log %+v err %v", swapoutLog, err)
+	}
+	if _, err = ParseSwapOutLog("SwapOut token=a from=b to=c amount=x toChainID=56"); err == nil {
+		t.Errorf("parse swapout log with wrong amount should fail")
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	var payer, readonlySigner, writable, readonly, program PublicKey
+	payer[0], readonlySigner[0], writable[0], readonly[0], program[0] = 1, 2, 3, 4, 5
+	ins := &Instruction{
+		ProgramID: program,
+		Accounts: []*AccountMeta{
+			{PublicKey: readonly},
+			{PublicKey: writable, IsWritable: true},
+			{PublicKey: readonlySigner, IsSigner: true},
+			{PublicKey: payer, IsSigner: true},
+		},
+		Data: []byte{9},
+	}
+	msg, err := NewMessage(payer, []*Instruction{ins}, PublicKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Header != (MessageHeader{2, 1, 2}) {
+		t.Errorf("wrong message header %+v", msg.Header)
+	}
+	wantKeys := []PublicKey{payer, readonlySigner, writable, readonly, program}
+	for i, key := range wantKeys {
+		if msg.AccountKeys[i] != key {
+			t.Fatalf("wrong account key order at %v", i)
+		}
+	}
+	compiled := msg.Instructions[0]
+	if compiled.ProgramIDIndex != 4 || string(compiled.Accounts) != string([]byte{3, 2, 1, 0}) {
+		t.Errorf("wrong compiled instruction %+v", compiled)
+	}
+}
+
+// stubNode serves json-rpc by method name
+type stubNode struct {
+	results map[string]interface{}
+	sent    string
+}
+
+func (s *stubNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if req.Method == "sendTransaction" {
+		_ = json.Unmarshal(req.Params[0], &s.sent)
+		resp["result"] = tTxHash
+	} else if result, exist := s.results[req.Method]; exist {
+		resp["result"] = result
+	} else {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+type testEnv struct {
+	node       *stubNode
+	srcBridge  *Bridge
+	dstBridge  *Bridge
+	mpcKey     ed25519.PrivateKey
+	mpcAddress string
+	userAddr   string
+	program    string
+	mint       string
+}
+
+func randomAddress() string {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	return base58.Encode(pub)
+}
+
+func newTestBridge(chainID, url, program, mint string) *Bridge {
+	b := NewCrossChainBridge()
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{url}})
+	chainCfg := &tokens.ChainConfig{
+		ChainID:        chainID,
+		BlockChain:     "solana",
+		RouterContract: program,
+		Confirmations:  1,
+	}
+	_ = chainCfg.CheckConfig()
+	b.SetChainConfig(chainCfg)
+	b.SetTokenConfig(mint, &tokens.TokenConfig{
+		TokenID:         tTokenID,
+		Decimals:        6,
+		ContractAddress: mint,
+	})
+	return b
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{node: &stubNode{results: make(map[string]interface{})}}
+	server := httptest.NewServer(env.node)
+	t.Cleanup(server.Close)
+
+	_, env.mpcKey, _ = ed25519.GenerateKey(nil)
+	env.mpcAddress = base58.Encode(env.mpcKey.Public().(ed25519.PublicKey))
+	env.userAddr = randomAddress()
+	env.program = randomAddress()
+	env.mint = randomAddress()
+
+	env.srcBridge = newTestBridge(tFromChain, server.URL, env.program, env.mint)
+	env.dstBridge = newTestBridge(tToChain, server.URL, env.program, env.mint)
+	router.SetBridge(tFromChain, env.srcBridge)
+	router.SetBridge(tToChain, env.dstBridge)
+	router.SetMultichainToken(tTokenID, tFromChain, env.mint)
+	router.SetMultichainToken(tTokenID, tToChain, env.mint)
+	router.SetRouterInfo(env.program, &router.SwapRouterInfo{RouterMPC: env.mpcAddress})
+	env.srcBridge.GetTokenConfig(env.mint).RouterContract = env.program
+	env.dstBridge.GetTokenConfig(env.mint).RouterContract = env.program
+
+	tokens.InitRouterSwapType("erc20swap")
+	oneUSDC := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	swapCfg := &tokens.SwapConfig{
+		MaximumSwap:           new(big.Int).Mul(oneUSDC, big.NewInt(100)),
+		MinimumSwap:           new(big.Int).Div(oneUSDC, big.NewInt(10000)),
+		BigValueThreshold:     new(big.Int).Mul(oneUSDC, big.NewInt(10)),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        new(big.Int).Div(oneUSDC, big.NewInt(100)),
+		MinimumSwapFee:        new(big.Int).Div(oneUSDC, big.NewInt(100000)),
+	}
+	swapConfig := new(sync.Map)
+	swapConfig.Store(tFromChain, swapCfg)
+	swapConfig.Store(tToChain, swapCfg)
+	swapConfigs := new(sync.Map)
+	swapConfigs.Store(tTokenID, swapConfig)
+	tokens.SetSwapConfigs(swapConfigs)
+
+	env.node.results["getSlot"] = tFinalized
+	env.node.results["getLatestBlockhash"] = map[string]interface{}{
+		"value": map[string]interface{}{"blockhash": tBlockhash, "lastValidBlockHeight": 2000},
+	}
+	return env
+}
+
+func (env *testEnv) setSwapoutTx(txErr interface{}, logs ...string) {
+	logMessages := []string{"Program " + env.program + " invoke [1]"}
+	logMessages = append(logMessages, logs...)
+	logMessages = append(logMessages, "Program "+env.program+" success")
+	env.node.results["getTransaction"] = map[string]interface{}{
+		"slot":      tTxSlot,
+		"blockTime": 1633046400,
+		"meta":      map[string]interface{}{"err": txErr, "logMessages": logMessages},
+	}
+}
+
+func (env *testEnv) swapoutLog(amount string) string {
+	return "Program log: SwapOut token=" + env.mint + " from=" + env.userAddr +
+		" to=" + env.userAddr + " amount=" + amount + " toChainID=" + tToChain
+}
+
+func TestRegisterAndVerifySwap(t *testing.T) {
+	env := newTestEnv(t)
+	b := env.srcBridge
+	args := &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType}
+
+	env.setSwapoutTx(nil, "Program log: Instruction: SwapOut", env.swapoutLog("1000000"))
+	swapInfos, errs := b.RegisterSwap(tTxHash, args)
+	if errs[0] != nil {
+		t.Fatalf("register swap failed. err %v", errs[0])
+	}
+	swapInfo := swapInfos[0]
+	if swapInfo.Hash != tTxHash || swapInfo.From != env.userAddr || swapInfo.Bind != env.userAddr ||
+		swapInfo.ToChainID.String() != tToChain || swapInfo.Value.String() != "1000000" ||
+		swapInfo.ERC20SwapInfo.TokenID != tTokenID || swapInfo.Height != tTxSlot {
+		t.Fatalf("wrong swap info %+v", swapInfo)
+	}
+	if _, err := b.VerifyTransaction(tTxHash, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType}); err != nil {
+		t.Errorf("verify stable swap failed. err %v", err)
+	}
+
+	args.LogIndex = 1
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrLogIndexOutOfRange {
+		t.Errorf("register swap with wrong log index. have %v want %v", errs[0], tokens.ErrLogIndexOutOfRange)
+	}
+
+	args.LogIndex = 0
+	env.setSwapoutTx(map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}}, env.swapoutLog("1000000"))
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrTxWithWrongStatus {
+		t.Errorf("register failed swap tx. have %v want %v", errs[0], tokens.ErrTxWithWrongStatus)
+	}
+
+	env.setSwapoutTx(nil, "Program log: Instruction: Transfer")
+	if _, errs = b.RegisterSwap(tTxHash, args); errs[0] != tokens.ErrSwapoutLogNotFound {
+		t.Errorf("register tx without swapout log. have %v want %v", errs[0], tokens.ErrSwapoutLogNotFound)
+	}
+}
+
+func TestBuildAndSignTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	b := env.dstBridge
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  "test",
+			SwapID:      tTxHash,
+			SwapType:    tokens.ERC20SwapType,
+			Bind:        env.userAddr,
+			FromChainID: big.NewInt(1300),
+			ToChainID:   big.NewInt(1400),
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+				Token:   env.mint,
+				TokenID: tTokenID,
+			}},
+		},
+		From:        env.mpcAddress,
+		OriginValue: big.NewInt(1000000),
+	}
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatalf("build tx failed. err %v", err)
+	}
+	tx := rawTx.(*Transaction)
+	if *args.Extra.SolanaExtra.RecentBlockhash != tBlockhash || args.SwapValue.String() != "999000" {
+		t.Fatalf("wrong build args. extra %+v swapValue %v", args.Extra.SolanaExtra, args.SwapValue)
+	}
+	if tx.Message.AccountKeys[0].String() != env.mpcAddress || tx.Message.Header.NumRequiredSignatures != 1 {
+		t.Fatalf("fee payer should be mpc")
+	}
+	wantData := GetInstructionDiscriminator(SwapInInstruction)
+	wantData = appendBorshString(wantData, tTxHash)
+	wantData = appendBorshUint64(wantData, 999000)
+	wantData = appendBorshUint64(wantData, 1300)
+	if string(tx.Message.Instructions[0].Data) != string(wantData) {
+		t.Errorf("wrong instruction data %x", tx.Message.Instructions[0].Data)
+	}
+	if err = b.VerifyMsgHash(rawTx, []string{common.ToHex(tx.Message.Serialize())}); err != nil {
+		t.Errorf("verify msg hash failed. err %v", err)
+	}
+	if _, err = b.verifyTransactionReceiver(rawTx, args); err != nil {
+		t.Errorf("verify tx receiver failed. err %v", err)
+	}
+
+	signedTx, txHash, err := b.SignTransactionWithPrivateKey(rawTx, hex.EncodeToString(env.mpcKey.Seed()))
+	if err != nil {
+		t.Fatalf("sign tx failed. err %v", err)
+	}
+	if sig, _ := base58.Decode(txHash); len(sig) != SignatureLength {
+		t.Errorf("tx hash should be base58 of signature. have %v", txHash)
+	}
+	if _, _, err = b.SignTransactionWithPrivateKey(rawTx, strings.Repeat("11", 32)); err == nil {
+		t.Errorf("sign with key other than fee payer should fail")
+	}
+	sentHash, err := b.SendTransaction(signedTx)
+	if err != nil || sentHash != tTxHash || env.node.sent == "" {
+		t.Errorf("send tx failed. hash %v err %v", sentHash, err)
+	}
+}
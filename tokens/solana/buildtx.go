@@ -0,0 +1,185 @@
+package solana
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
+		return nil, tokens.ErrToChainIDMismatch
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if args.From != routerMPC {
+		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
+		return nil, tokens.ErrSenderMismatch
+	}
+
+	amount, err := b.getSwapAmount(args)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 || !amount.IsUint64() {
+		return nil, fmt.Errorf("wrong swap value %v", amount)
+	}
+	args.To = args.Bind     // to
+	args.SwapValue = amount // swapValue
+
+	ins, err := b.buildSwapInInstruction(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args, ins)
+}
+
+func (b *Bridge) getMultichainToken(args *tokens.BuildTxArgs) (string, error) {
+	erc20SwapInfo := args.ERC20SwapInfo
+	if erc20SwapInfo == nil || erc20SwapInfo.TokenID == "" {
+		return "", errors.New("build router swaptx without tokenID")
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", b.ChainConfig.ChainID)
+		return "", tokens.ErrMissTokenConfig
+	}
+	return multichainToken, nil
+}
+
+func (b *Bridge) getSwapAmount(args *tokens.BuildTxArgs) (*big.Int, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	if !b.IsValidAddress(args.Bind) {
+		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
+		return nil, errors.New("can not swapout to empty or invalid receiver")
+	}
+	erc20SwapInfo := args.ERC20SwapInfo
+	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
+	if fromBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := fromBridge.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", args.FromChainID, "token", erc20SwapInfo.Token)
+		return nil, tokens.ErrMissTokenConfig
+	}
+	toTokenCfg := b.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	amount, swapFeeInfo := tokens.CalcSwapValueWithFee(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo, args.OriginTime)
+	args.SwapFeeInfo = swapFeeInfo
+	return amount, nil
+}
+
+// buildSwapInInstruction build instruction of router program
+// swapin(tx: String, amount: u64, from_chain_id: u64)
+// with accounts [mpc (signer), token mint, receiver]
+func (b *Bridge) buildSwapInInstruction(args *tokens.BuildTxArgs) (*Instruction, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	routerProgram, err := PublicKeyFromBase58(b.ChainConfig.RouterContract)
+	if err != nil {
+		return nil, err
+	}
+	mpc, err := PublicKeyFromBase58(args.From)
+	if err != nil {
+		return nil, err
+	}
+	mint, err := PublicKeyFromBase58(multichainToken)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := PublicKeyFromBase58(args.Bind)
+	if err != nil {
+		return nil, err
+	}
+	if !args.FromChainID.IsUint64() {
+		return nil, fmt.Errorf("from chain id %v overflow u64", args.FromChainID)
+	}
+	data := GetInstructionDiscriminator(SwapInInstruction)
+	data = appendBorshString(data, args.SwapID)
+	data = appendBorshUint64(data, args.SwapValue.Uint64())
+	data = appendBorshUint64(data, args.FromChainID.Uint64())
+	return &Instruction{
+		ProgramID: routerProgram,
+		Accounts: []*AccountMeta{
+			{PublicKey: mpc, IsSigner: true, IsWritable: true},
+			{PublicKey: mint, IsWritable: true},
+			{PublicKey: receiver, IsWritable: true},
+		},
+		Data: data,
+	}, nil
+}
+
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) error {
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	if args.Extra.SolanaExtra == nil {
+		args.Extra.SolanaExtra = &tokens.SolanaExtraArgs{}
+	}
+	extra := args.Extra.SolanaExtra
+	if extra.RecentBlockhash == nil {
+		blockhash, err := b.GetLatestBlockhash()
+		if err != nil {
+			return err
+		}
+		extra.RecentBlockhash = &blockhash
+	}
+	return nil
+}
+
+func (b *Bridge) buildTx(args *tokens.BuildTxArgs, ins *Instruction) (rawTx interface{}, err error) {
+	feePayer, err := PublicKeyFromBase58(args.From)
+	if err != nil {
+		return nil, err
+	}
+	recentBlockhash, err := PublicKeyFromBase58(*args.Extra.SolanaExtra.RecentBlockhash)
+	if err != nil {
+		return nil, fmt.Errorf("wrong recent blockhash: %w", err)
+	}
+	msg, err := NewMessage(feePayer, []*Instruction{ins}, recentBlockhash)
+	if err != nil {
+		return nil, err
+	}
+	tx := &Transaction{Message: msg}
+
+	log.Info("build tx success",
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "to", args.To, "bind", args.Bind,
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"recentBlockhash", *args.Extra.SolanaExtra.RecentBlockhash,
+		"replaceNum", args.GetReplaceNum())
+
+	return tx, nil
+}
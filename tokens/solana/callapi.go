@@ -0,0 +1,159 @@
+package solana
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errEmptyURLs = errors.New("empty URLs")
+)
+
+// commitment levels
+const (
+	CommitmentFinalized = "finalized"
+	CommitmentConfirmed = "confirmed"
+)
+
+// TransactionMeta meta of transaction
+type TransactionMeta struct {
+	Err         interface{} `json:"err"`
+	Fee         uint64      `json:"fee"`
+	LogMessages []string    `json:"logMessages"`
+}
+
+// TransactionResult result of 'getTransaction'
+type TransactionResult struct {
+	Slot        uint64           `json:"slot"`
+	BlockTime   *int64           `json:"blockTime"`
+	Meta        *TransactionMeta `json:"meta"`
+	Transaction struct {
+		Signatures []string `json:"signatures"`
+		Message    struct {
+			AccountKeys []string `json:"accountKeys"`
+		} `json:"message"`
+	} `json:"transaction"`
+}
+
+// IsStatusOk impl tokens.StatusInterface
+func (r *TransactionResult) IsStatusOk() bool {
+	return r.Meta != nil && r.Meta.Err == nil
+}
+
+// GetTimestamp get block time in seconds
+func (r *TransactionResult) GetTimestamp() uint64 {
+	if r.BlockTime == nil || *r.BlockTime < 0 {
+		return 0
+	}
+	return uint64(*r.BlockTime)
+}
+
+// LatestBlockhash result of 'getLatestBlockhash'
+type LatestBlockhash struct {
+	Value struct {
+		Blockhash            string `json:"blockhash"`
+		LastValidBlockHeight uint64 `json:"lastValidBlockHeight"`
+	} `json:"value"`
+}
+
+func (b *Bridge) rpcCall(result interface{}, method string, params ...interface{}) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, result, url, method, params...)
+		if err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, method, params...)
+}
+
+func commitmentConfig(commitment string) map[string]interface{} {
+	return map[string]interface{}{"commitment": commitment}
+}
+
+// GetLatestBlockNumberOf get finalized slot of specified url
+func (b *Bridge) GetLatestBlockNumberOf(url string) (slot uint64, err error) {
+	err = client.RPCPostWithTimeout(b.RPCClientTimeout, &slot, url, "getSlot", commitmentConfig(CommitmentFinalized))
+	if err != nil {
+		return 0, wrapRPCQueryError(err, "getSlot")
+	}
+	return slot, nil
+}
+
+// GetLatestBlockNumber get finalized slot
+func (b *Bridge) GetLatestBlockNumber() (slot uint64, err error) {
+	err = b.rpcCall(&slot, "getSlot", commitmentConfig(CommitmentFinalized))
+	return slot, err
+}
+
+// GetTransactionByHash get confirmed transaction by signature
+func (b *Bridge) GetTransactionByHash(txHash string) (*TransactionResult, error) {
+	var result *TransactionResult
+	err := b.rpcCall(&result, "getTransaction", txHash, map[string]interface{}{
+		"encoding":                       "json",
+		"commitment":                     CommitmentConfirmed,
+		"maxSupportedTransactionVersion": 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, tokens.ErrTxNotFound
+	}
+	return result, nil
+}
+
+// GetLatestBlockhash get finalized recent blockhash
+func (b *Bridge) GetLatestBlockhash() (string, error) {
+	var result LatestBlockhash
+	err := b.rpcCall(&result, "getLatestBlockhash", commitmentConfig(CommitmentFinalized))
+	if err != nil {
+		return "", err
+	}
+	if result.Value.Blockhash == "" {
+		return "", wrapRPCQueryError(nil, "getLatestBlockhash")
+	}
+	return result.Value.Blockhash, nil
+}
+
+// GetLamportsBalance get balance in lamports
+func (b *Bridge) GetLamportsBalance(account string) (uint64, error) {
+	var result struct {
+		Value uint64 `json:"value"`
+	}
+	err := b.rpcCall(&result, "getBalance", account, commitmentConfig(CommitmentFinalized))
+	return result.Value, err
+}
+
+// SendRawTransaction send serialized transaction to all gateways
+func (b *Bridge) SendRawTransaction(rawTx []byte) (txHash string, err error) {
+	urls := append([]string{}, b.GatewayConfig.APIAddress...)
+	urls = append(urls, b.GatewayConfig.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	encoded := base64.StdEncoding.EncodeToString(rawTx)
+	config := map[string]interface{}{
+		"encoding":            "base64",
+		"preflightCommitment": CommitmentConfirmed,
+	}
+	for _, url := range urls {
+		var result string
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "sendTransaction", encoded, config)
+		if err == nil && result != "" {
+			txHash = result
+		}
+	}
+	if txHash != "" {
+		return txHash, nil
+	}
+	return "", wrapRPCQueryError(err, "sendTransaction")
+}
@@ -0,0 +1,29 @@
+package solana
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*Transaction)
+	if !ok || len(tx.Signatures) == 0 {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	rawTx := tx.Serialize()
+	txHash, err = b.SendRawTransaction(rawTx)
+	if err != nil {
+		log.Info("SendTransaction failed", "hash", tx.TxHash(), "err", err)
+	} else {
+		log.Info("SendTransaction success", "hash", txHash)
+	}
+	if params.IsDebugMode() {
+		log.Infof("SendTransaction rawtx is %v", base64.StdEncoding.EncodeToString(rawTx))
+	}
+	return txHash, err
+}
@@ -0,0 +1,144 @@
+package solana
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// VerifyMsgHash verify msg hash (the serialized message is signed directly)
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Message == nil {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	sigHash := common.ToHex(tx.Message.Serialize())
+	if !strings.EqualFold(sigHash, msgHashes[0]) {
+		log.Trace("message hash mismatch", "want", msgHashes[0], "have", sigHash)
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, args *tokens.BuildTxArgs) (*Transaction, error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Message == nil || len(tx.Message.Instructions) != 1 {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	checkIns, err := b.buildSwapInInstruction(args)
+	if err != nil {
+		return nil, err
+	}
+	checkMsg, err := NewMessage(checkIns.Accounts[0].PublicKey, []*Instruction{checkIns}, tx.Message.RecentBlockhash)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tx.Message.Serialize(), checkMsg.Serialize()) {
+		return nil, errors.New("[sign] tx instruction mismatch")
+	}
+	return tx, nil
+}
+
+// MPCSignTransaction mpc sign raw tx
+func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, err := b.verifyTransactionReceiver(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SignWithPrivateKey() {
+		priKey := params.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+	}
+
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	pubKey := common.FromHex(mpcPubkey)
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, "", errWrongPublicKeyLength
+	}
+
+	msgHash := common.ToHex(tx.Message.Serialize())
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash)
+	keyID, rsvs, err := mpc.DoSignOneED(mpcPubkey, msgHash, msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash)
+
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many",
+			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
+		return nil, "", errors.New("get sign status require one rsv but return many")
+	}
+
+	signature := common.FromHex(rsvs[0])
+	if len(signature) != SignatureLength {
+		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", SignatureLength)
+		return nil, "", errors.New("wrong signature length")
+	}
+
+	signedTx, err := signTxWithSignature(tx, pubKey, signature)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash = signedTx.TxHash()
+	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash)
+	return signedTx, txHash, nil
+}
+
+// SignTransactionWithPrivateKey sign tx with ed25519 private key (hex of 32 bytes seed)
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Message == nil {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	seed := common.FromHex(priKey)
+	if len(seed) != ed25519.SeedSize {
+		return nil, "", errors.New("wrong ed25519 private key seed length")
+	}
+	edPriKey := ed25519.NewKeyFromSeed(seed)
+	pubKey := edPriKey.Public().(ed25519.PublicKey)
+	signature := ed25519.Sign(edPriKey, tx.Message.Serialize())
+	signedTx, err := signTxWithSignature(tx, pubKey, signature)
+	if err != nil {
+		return nil, "", err
+	}
+	return signedTx, signedTx.TxHash(), nil
+}
+
+// signTxWithSignature verify and attach the fee payer signature
+func signTxWithSignature(tx *Transaction, pubKey, signature []byte) (*Transaction, error) {
+	msg := tx.Message
+	if msg.Header.NumRequiredSignatures != 1 || !bytes.Equal(msg.AccountKeys[0][:], pubKey) {
+		return nil, errors.New("signer public key mismatch")
+	}
+	if !ed25519.Verify(pubKey, msg.Serialize(), signature) {
+		return nil, errors.New("verify signature failed")
+	}
+	return &Transaction{Signatures: [][]byte{signature}, Message: msg}, nil
+}
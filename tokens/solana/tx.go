@@ -0,0 +1,201 @@
+package solana
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/mr-tron/base58"
+)
+
+// SignatureLength length of ed25519 signature
+const SignatureLength = 64
+
+var errTooManyAccounts = errors.New("too many accounts in message")
+
+// AccountMeta account used by instruction
+type AccountMeta struct {
+	PublicKey  PublicKey
+	IsSigner   bool
+	IsWritable bool
+}
+
+// Instruction instruction to call program
+type Instruction struct {
+	ProgramID PublicKey
+	Accounts  []*AccountMeta
+	Data      []byte
+}
+
+// CompiledInstruction instruction with account indexes in message
+type CompiledInstruction struct {
+	ProgramIDIndex uint8
+	Accounts       []uint8
+	Data           []byte
+}
+
+// MessageHeader message header
+type MessageHeader struct {
+	NumRequiredSignatures       uint8
+	NumReadonlySignedAccounts   uint8
+	NumReadonlyUnsignedAccounts uint8
+}
+
+// Message legacy transaction message (the data to be signed)
+type Message struct {
+	Header          MessageHeader
+	AccountKeys     []PublicKey
+	RecentBlockhash PublicKey
+	Instructions    []*CompiledInstruction
+}
+
+// NewMessage compile instructions to message, fee payer is the first signer.
+// accounts are ordered as: writable signers, readonly signers,
+// writable non-signers, readonly non-signers.
+func NewMessage(feePayer PublicKey, instructions []*Instruction, recentBlockhash PublicKey) (*Message, error) {
+	metas := []*AccountMeta{{PublicKey: feePayer, IsSigner: true, IsWritable: true}}
+	indexes := map[PublicKey]int{feePayer: 0}
+	addMeta := func(meta *AccountMeta) {
+		if i, exist := indexes[meta.PublicKey]; exist {
+			metas[i].IsSigner = metas[i].IsSigner || meta.IsSigner
+			metas[i].IsWritable = metas[i].IsWritable || meta.IsWritable
+			return
+		}
+		indexes[meta.PublicKey] = len(metas)
+		metas = append(metas, &AccountMeta{PublicKey: meta.PublicKey, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable})
+	}
+	for _, ins := range instructions {
+		for _, meta := range ins.Accounts {
+			addMeta(meta)
+		}
+		addMeta(&AccountMeta{PublicKey: ins.ProgramID})
+	}
+
+	msg := &Message{RecentBlockhash: recentBlockhash}
+	groups := []func(*AccountMeta) bool{
+		func(m *AccountMeta) bool { return m.IsSigner && m.IsWritable },
+		func(m *AccountMeta) bool { return m.IsSigner && !m.IsWritable },
+		func(m *AccountMeta) bool { return !m.IsSigner && m.IsWritable },
+		func(m *AccountMeta) bool { return !m.IsSigner && !m.IsWritable },
+	}
+	for i, inGroup := range groups {
+		for _, meta := range metas {
+			if !inGroup(meta) {
+				continue
+			}
+			msg.AccountKeys = append(msg.AccountKeys, meta.PublicKey)
+			switch i {
+			case 0:
+				msg.Header.NumRequiredSignatures++
+			case 1:
+				msg.Header.NumRequiredSignatures++
+				msg.Header.NumReadonlySignedAccounts++
+			case 3:
+				msg.Header.NumReadonlyUnsignedAccounts++
+			}
+		}
+	}
+	if len(msg.AccountKeys) > 256 {
+		return nil, errTooManyAccounts
+	}
+
+	keyIndexes := make(map[PublicKey]uint8, len(msg.AccountKeys))
+	for i, key := range msg.AccountKeys {
+		keyIndexes[key] = uint8(i)
+	}
+	for _, ins := range instructions {
+		compiled := &CompiledInstruction{
+			ProgramIDIndex: keyIndexes[ins.ProgramID],
+			Accounts:       make([]uint8, len(ins.Accounts)),
+			Data:           ins.Data,
+		}
+		for i, meta := range ins.Accounts {
+			compiled.Accounts[i] = keyIndexes[meta.PublicKey]
+		}
+		msg.Instructions = append(msg.Instructions, compiled)
+	}
+	return msg, nil
+}
+
+// appendCompactU16 append compact-u16 (shortvec) length
+func appendCompactU16(buf []byte, n int) []byte {
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
+
+// Serialize serialize message
+func (msg *Message) Serialize() []byte {
+	buf := []byte{
+		msg.Header.NumRequiredSignatures,
+		msg.Header.NumReadonlySignedAccounts,
+		msg.Header.NumReadonlyUnsignedAccounts,
+	}
+	buf = appendCompactU16(buf, len(msg.AccountKeys))
+	for _, key := range msg.AccountKeys {
+		buf = append(buf, key[:]...)
+	}
+	buf = append(buf, msg.RecentBlockhash[:]...)
+	buf = appendCompactU16(buf, len(msg.Instructions))
+	for _, ins := range msg.Instructions {
+		buf = append(buf, ins.ProgramIDIndex)
+		buf = appendCompactU16(buf, len(ins.Accounts))
+		buf = append(buf, ins.Accounts...)
+		buf = appendCompactU16(buf, len(ins.Data))
+		buf = append(buf, ins.Data...)
+	}
+	return buf
+}
+
+// Transaction transaction with signatures
+type Transaction struct {
+	Signatures [][]byte
+	Message    *Message
+}
+
+// Serialize serialize transaction
+func (tx *Transaction) Serialize() []byte {
+	buf := appendCompactU16(nil, len(tx.Signatures))
+	for _, sig := range tx.Signatures {
+		buf = append(buf, sig...)
+	}
+	return append(buf, tx.Message.Serialize()...)
+}
+
+// TxHash tx hash is the base58 of the first signature
+func (tx *Transaction) TxHash() string {
+	if len(tx.Signatures) == 0 {
+		return ""
+	}
+	return base58.Encode(tx.Signatures[0])
+}
+
+// GetInstructionDiscriminator anchor instruction discriminator
+func GetInstructionDiscriminator(name string) []byte {
+	hash := sha256.Sum256([]byte("global:" + name))
+	return hash[:8]
+}
+
+// borsh encoders of instruction arguments
+
+func appendBorshString(buf []byte, s string) []byte {
+	buf = appendBorshUint32(buf, uint32(len(s)))
+	return append(buf, s...)
+}
+
+func appendBorshUint32(buf []byte, v uint32) []byte {
+	var tmp [4]byte
+	binary.LittleEndian.PutUint32(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
+
+func appendBorshUint64(buf []byte, v uint64) []byte {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
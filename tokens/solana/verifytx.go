@@ -0,0 +1,246 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const programLogPrefix = "Program log: "
+
+// GetProgramLogs get logs emitted directly by the program (not by its inner invoked programs)
+func GetProgramLogs(logMessages []string, programID string) (logs []string) {
+	var stack []string
+	for _, msg := range logMessages {
+		switch {
+		case strings.HasPrefix(msg, programLogPrefix):
+			if len(stack) > 0 && stack[len(stack)-1] == programID {
+				logs = append(logs, strings.TrimPrefix(msg, programLogPrefix))
+			}
+		case strings.HasPrefix(msg, "Program "):
+			fields := strings.Fields(msg)
+			if len(fields) < 3 {
+				continue
+			}
+			switch {
+			case fields[2] == "invoke":
+				stack = append(stack, fields[1])
+			case fields[2] == "success" || strings.HasPrefix(fields[2], "failed"):
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+	}
+	return logs
+}
+
+// SwapOutLog swapout log of router program, format is
+// 'SwapOut token=<mint> from=<account> to=<bind> amount=<amount> toChainID=<chainID>'
+type SwapOutLog struct {
+	Token     string
+	From      string
+	To        string
+	Amount    *big.Int
+	ToChainID *big.Int
+}
+
+// ParseSwapOutLog parse swapout log
+func ParseSwapOutLog(logMsg string) (*SwapOutLog, error) {
+	if !strings.HasPrefix(logMsg, SwapOutLogPrefix) {
+		return nil, tokens.ErrSwapoutLogNotFound
+	}
+	fields := make(map[string]string)
+	for _, field := range strings.Fields(strings.TrimPrefix(logMsg, SwapOutLogPrefix)) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("wrong swapout log field '%v'", field)
+		}
+		fields[parts[0]] = parts[1]
+	}
+	swapoutLog := &SwapOutLog{
+		Token: fields["token"],
+		From:  fields["from"],
+		To:    fields["to"],
+	}
+	var ok bool
+	if swapoutLog.Amount, ok = new(big.Int).SetString(fields["amount"], 10); !ok {
+		return nil, fmt.Errorf("wrong swapout log amount '%v'", fields["amount"])
+	}
+	if swapoutLog.ToChainID, ok = new(big.Int).SetString(fields["toChainID"], 10); !ok {
+		return nil, fmt.Errorf("wrong swapout log toChainID '%v'", fields["toChainID"])
+	}
+	if swapoutLog.Token == "" || swapoutLog.From == "" || swapoutLog.To == "" {
+		return nil, fmt.Errorf("swapout log miss fields '%v'", logMsg)
+	}
+	return swapoutLog, nil
+}
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.GetTransactionByHash(txHash)
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	txStatus := &tokens.TxStatus{
+		Receipt:     tx,
+		BlockHeight: tx.Slot,
+		BlockTime:   tx.GetTimestamp(),
+	}
+	if latest, errt := b.GetLatestBlockNumber(); errt == nil && latest >= tx.Slot {
+		txStatus.Confirmations = latest - tx.Slot + 1
+	}
+	return txStatus, nil
+}
+
+// VerifyTransaction api
+func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	return b.verifySwapoutTx(txHash, args.LogIndex, args.AllowUnstable)
+}
+
+func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ERC20SwapType // SwapType
+	swapInfo.Hash = txHash                   // Hash (base58 is case sensitive)
+	swapInfo.LogIndex = logIndex             // LogIndex
+
+	tx, err := b.getStableSwapTx(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.parseSwapoutLog(swapInfo, tx.Meta.LogMessages)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapoutInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify router swap tx stable pass",
+			"identifier", params.GetIdentifier(),
+			"from", swapInfo.From, "to", swapInfo.To,
+			"bind", swapInfo.Bind, "value", swapInfo.Value,
+			"txid", txHash, "logIndex", logIndex,
+			"height", swapInfo.Height, "timestamp", swapInfo.Timestamp,
+			"fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID,
+			"token", swapInfo.ERC20SwapInfo.Token, "tokenID", swapInfo.ERC20SwapInfo.TokenID)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) getStableSwapTx(swapInfo *tokens.SwapTxInfo, allowUnstable bool) (*TransactionResult, error) {
+	tx, err := b.GetTransactionByHash(swapInfo.Hash)
+	if err != nil {
+		log.Error("get tx failed", "hash", swapInfo.Hash, "err", err)
+		return nil, err
+	}
+	if tx.Slot < b.ChainConfig.InitialHeight {
+		return nil, tokens.ErrTxBeforeInitialHeight
+	}
+	if !tx.IsStatusOk() {
+		return nil, tokens.ErrTxWithWrongStatus
+	}
+
+	swapInfo.Height = tx.Slot              // Height
+	swapInfo.Timestamp = tx.GetTimestamp() // Timestamp
+
+	if !allowUnstable {
+		latest, errt := b.GetLatestBlockNumber()
+		if errt != nil {
+			return nil, errt
+		}
+		if latest < tx.Slot || latest-tx.Slot+1 < b.ChainConfig.Confirmations {
+			return nil, tokens.ErrTxNotStable
+		}
+	}
+	return tx, nil
+}
+
+// parseSwapoutLog parse the nth (logIndex) swapout log of router program
+func (b *Bridge) parseSwapoutLog(swapInfo *tokens.SwapTxInfo, logMessages []string) error {
+	routerProgram := b.ChainConfig.RouterContract
+	var swapoutLogs []string
+	for _, logMsg := range GetProgramLogs(logMessages, routerProgram) {
+		if strings.HasPrefix(logMsg, SwapOutLogPrefix) {
+			swapoutLogs = append(swapoutLogs, logMsg)
+		}
+	}
+	if len(swapoutLogs) == 0 {
+		return tokens.ErrSwapoutLogNotFound
+	}
+	if swapInfo.LogIndex >= len(swapoutLogs) {
+		return tokens.ErrLogIndexOutOfRange
+	}
+	swapoutLog, err := ParseSwapOutLog(swapoutLogs[swapInfo.LogIndex])
+	if err != nil {
+		log.Info(b.ChainConfig.BlockChain+" parse swapout log failed", "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", err)
+		return tokens.ErrSwapoutLogNotFound
+	}
+	tokenCfg := b.GetTokenConfig(swapoutLog.Token)
+	if tokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+
+	swapInfo.TxTo = routerProgram                     // TxTo
+	swapInfo.To = routerProgram                       // To
+	swapInfo.From = swapoutLog.From                   // From
+	swapInfo.Bind = swapoutLog.To                     // Bind
+	swapInfo.Value = swapoutLog.Amount                // Value
+	swapInfo.FromChainID = b.ChainConfig.GetChainID() // FromChainID
+	swapInfo.ToChainID = swapoutLog.ToChainID         // ToChainID
+
+	swapInfo.ERC20SwapInfo.Token = swapoutLog.Token   // Token
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID // TokenID
+	return nil
+}
+
+func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
+	if swapInfo.FromChainID.Cmp(swapInfo.ToChainID) == 0 {
+		return tokens.ErrSameFromAndToChainID
+	}
+	erc20SwapInfo := swapInfo.ERC20SwapInfo
+	fromTokenCfg := b.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil || erc20SwapInfo.TokenID == "" {
+		return tokens.ErrMissTokenConfig
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, swapInfo.ToChainID.String())
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", swapInfo.ToChainID, "txid", swapInfo.Hash)
+		return tokens.ErrMissTokenConfig
+	}
+	toBridge := router.GetBridgeByChainID(swapInfo.ToChainID.String())
+	if toBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	toTokenCfg := toBridge.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", swapInfo.ToChainID, "token", multichainToken)
+		return tokens.ErrMissTokenConfig
+	}
+	if !tokens.CheckTokenSwapValue(swapInfo, fromTokenCfg.Decimals, toTokenCfg.Decimals) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !toBridge.IsValidAddress(swapInfo.Bind) {
+		log.Warn("wrong bind address in swapout", "txid", swapInfo.Hash, "bind", swapInfo.Bind)
+		return tokens.ErrWrongBindAddress
+	}
+	return nil
+}
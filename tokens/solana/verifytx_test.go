@@ -0,0 +1,179 @@
+package solana
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	tRouterProgram = "Router1111111111111111111111111111111111111"
+	tTokenProgram  = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	tMint          = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+)
+
+func TestGetProgramLogsOfInvokeStack(t *testing.T) {
+	testCases := []struct {
+		name        string
+		logMessages []string
+		want        []string
+	}{
+		{
+			"logs of inner program are excluded",
+			[]string{
+				"Program " + tRouterProgram + " invoke [1]",
+				"Program log: a",
+				"Program " + tTokenProgram + " invoke [2]",
+				"Program log: inner",
+				"Program " + tTokenProgram + " success",
+				"Program log: b",
+				"Program " + tRouterProgram + " success",
+			},
+			[]string{"a", "b"},
+		},
+		{
+			"router invoked by other program",
+			[]string{
+				"Program Other11111111111111111111111111111111111111 invoke [1]",
+				"Program log: outer",
+				"Program " + tRouterProgram + " invoke [2]",
+				"Program log: a",
+				"Program " + tRouterProgram + " consumed 4000 of 200000 compute units",
+				"Program " + tRouterProgram + " success",
+				"Program log: outer",
+				"Program Other11111111111111111111111111111111111111 success",
+			},
+			[]string{"a"},
+		},
+		{
+			"inner program failed",
+			[]string{
+				"Program " + tRouterProgram + " invoke [1]",
+				"Program " + tTokenProgram + " invoke [2]",
+				"Program log: inner",
+				"Program " + tTokenProgram + " failed: custom program error: 0x1",
+				"Program log: a",
+				"Program " + tRouterProgram + " success",
+			},
+			[]string{"a"},
+		},
+		{
+			"logs outside of any invoke",
+			[]string{
+				"Program log: fake",
+				"Program " + tRouterProgram + " invoke [1]",
+				"Program " + tRouterProgram + " success",
+				"Program log: fake",
+			},
+			nil,
+		},
+		{
+			"data logs are not program logs",
+			[]string{
+				"Program " + tRouterProgram + " invoke [1]",
+				"Program data: U3dhcE91dA==",
+				"Program return: " + tRouterProgram + " AQ==",
+				"Program " + tRouterProgram + " success",
+			},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		have := GetProgramLogs(tc.logMessages, tRouterProgram)
+		if len(have) != len(tc.want) {
+			t.Errorf("%v: want %v, have %v", tc.name, tc.want, have)
+			continue
+		}
+		for i := range have {
+			if have[i] != tc.want[i] {
+				t.Errorf("%v: want %v, have %v", tc.name, tc.want, have)
+				break
+			}
+		}
+	}
+}
+
+func TestParseSwapOutLog(t *testing.T) {
+	testCases := []struct {
+		logMsg  string
+		wantErr bool
+	}{
+		{"SwapOut token=a from=b to=c amount=10 toChainID=56", false},
+		{"SwapOut toChainID=56 amount=10 to=c from=b token=a", false},
+		{"SwapOut  token=a from=b   to=c amount=10 toChainID=56 ", false},
+		{"SwapOut token=a from=b to=c=d amount=10 toChainID=56", false},
+		{"SwapIn token=a from=b to=c amount=10 toChainID=56", true},
+		{"swapout token=a from=b to=c amount=10 toChainID=56", true},
+		{"SwapOut token=a from=b to=c amount=10", true},
+		{"SwapOut token=a from=b to=c amount=-x toChainID=56", true},
+		{"SwapOut token=a from=b to=c amount=10 toChainID=0x38", true},
+		{"SwapOut token=a from=b amount=10 toChainID=56", true},
+		{"SwapOut token=a from=b to= amount=10 toChainID=56", true},
+		{"SwapOut token=a from=b to=c amount=10 toChainID=56 extra", true},
+	}
+	for _, tc := range testCases {
+		swapoutLog, err := ParseSwapOutLog(tc.logMsg)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parse swapout log '%v': want error %v, have %v", tc.logMsg, tc.wantErr, err)
+			continue
+		}
+		if err == nil && (swapoutLog.Token != "a" || swapoutLog.From != "b" ||
+			swapoutLog.Amount.Uint64() != 10 || swapoutLog.ToChainID.Uint64() != 56) {
+			t.Errorf("parse swapout log '%v': wrong result %+v", tc.logMsg, swapoutLog)
+		}
+	}
+}
+
+func TestParseSwapoutLogOfLogIndex(t *testing.T) {
+	b := newTestBridge(tFromChain, "", tRouterProgram, tMint)
+	logMessages := []string{
+		"Program " + tRouterProgram + " invoke [1]",
+		"Program log: Instruction: SwapOut",
+		"Program log: SwapOut token=" + tMint + " from=user1 to=0xbind1 amount=1000 toChainID=56",
+		"Program " + tTokenProgram + " invoke [2]",
+		"Program log: SwapOut token=" + tMint + " from=fake to=fake amount=1 toChainID=1",
+		"Program " + tTokenProgram + " success",
+		"Program log: SwapOut token=" + tMint + " from=user2 to=0xbind2 amount=2000 toChainID=137",
+		"Program log: SwapOut token=unknown from=user3 to=0xbind3 amount=3000 toChainID=1",
+		"Program log: SwapOut token=" + tMint + " from=user4 to=0xbind4 amount=x toChainID=1",
+		"Program " + tRouterProgram + " success",
+	}
+	testCases := []struct {
+		logIndex  int
+		wantErr   error
+		wantFrom  string
+		wantBind  string
+		wantValue uint64
+		wantTo    uint64
+	}{
+		{0, nil, "user1", "0xbind1", 1000, 56},
+		{1, nil, "user2", "0xbind2", 2000, 137},
+		{2, tokens.ErrMissTokenConfig, "", "", 0, 0},
+		{3, tokens.ErrSwapoutLogNotFound, "", "", 0, 0},
+		{4, tokens.ErrLogIndexOutOfRange, "", "", 0, 0},
+	}
+	for _, tc := range testCases {
+		swapInfo := &tokens.SwapTxInfo{
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}},
+			LogIndex: tc.logIndex,
+		}
+		err := b.parseSwapoutLog(swapInfo, logMessages)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("log index %v: want error %v, have %v", tc.logIndex, tc.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if swapInfo.From != tc.wantFrom || swapInfo.Bind != tc.wantBind ||
+			swapInfo.Value.Uint64() != tc.wantValue || swapInfo.ToChainID.Uint64() != tc.wantTo ||
+			swapInfo.To != tRouterProgram || swapInfo.ERC20SwapInfo.TokenID != tTokenID {
+			t.Errorf("log index %v: wrong swap info %+v", tc.logIndex, swapInfo)
+		}
+	}
+
+	if err := b.parseSwapoutLog(&tokens.SwapTxInfo{}, logMessages[3:6]); !errors.Is(err, tokens.ErrSwapoutLogNotFound) {
+		t.Errorf("parse swapout log of inner program only, want error %v, have %v", tokens.ErrSwapoutLogNotFound, err)
+	}
+}
@@ -0,0 +1,121 @@
+package substrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// PublicKeyLength length of ed25519 public key (aka. account id)
+	PublicKeyLength = 32
+
+	ss58ChecksumLength = 2
+)
+
+var (
+	ss58Prefix = []byte("SS58PRE")
+
+	errWrongAddressLength   = errors.New("wrong address length")
+	errWrongAddressChecksum = errors.New("wrong address checksum")
+	errWrongAddressFormat   = errors.New("wrong address format")
+	errWrongPublicKeyLength = errors.New("wrong public key length")
+)
+
+func encodeSS58Format(format uint16) []byte {
+	if format < 64 {
+		return []byte{byte(format)}
+	}
+	return []byte{
+		byte((format&0x00fc)>>2) | 0x40,
+		byte(format>>8) | byte((format&0x0003)<<6),
+	}
+}
+
+func ss58Checksum(data []byte) []byte {
+	hash := blake2b.Sum512(append(append([]byte{}, ss58Prefix...), data...))
+	return hash[:ss58ChecksumLength]
+}
+
+// EncodeSS58Address encode account id to ss58 address with the specified address format
+func EncodeSS58Address(accountID []byte, format uint16) (string, error) {
+	if len(accountID) != PublicKeyLength {
+		return "", errWrongPublicKeyLength
+	}
+	data := append(encodeSS58Format(format), accountID...)
+	data = append(data, ss58Checksum(data)...)
+	return base58.Encode(data), nil
+}
+
+// DecodeSS58Address decode ss58 address to account id and address format
+func DecodeSS58Address(address string) (accountID []byte, format uint16, err error) {
+	data, err := base58.Decode(address)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(data) == 0 {
+		return nil, 0, errWrongAddressLength
+	}
+	formatLen := 1
+	if data[0]&0x40 != 0 {
+		formatLen = 2
+	}
+	if len(data) != formatLen+PublicKeyLength+ss58ChecksumLength {
+		return nil, 0, errWrongAddressLength
+	}
+	switch formatLen {
+	case 1:
+		if data[0] >= 64 {
+			return nil, 0, errWrongAddressFormat
+		}
+		format = uint16(data[0])
+	default:
+		lower := (data[0]<<2)&0xfc | data[1]>>6
+		upper := data[1] & 0x3f
+		format = uint16(lower) | uint16(upper)<<8
+	}
+	payloadLen := len(data) - ss58ChecksumLength
+	if !bytes.Equal(ss58Checksum(data[:payloadLen]), data[payloadLen:]) {
+		return nil, 0, errWrongAddressChecksum
+	}
+	return data[formatLen:payloadLen], format, nil
+}
+
+// GetAccountID get account id of address of this chain
+func (b *Bridge) GetAccountID(address string) ([]byte, error) {
+	accountID, format, err := DecodeSS58Address(address)
+	if err != nil {
+		return nil, err
+	}
+	if format != b.SS58Format {
+		return nil, fmt.Errorf("address '%v' has format %v, want %v", address, format, b.SS58Format)
+	}
+	return accountID, nil
+}
+
+// IsValidAddress check address
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := b.GetAccountID(address)
+	return err == nil
+}
+
+// PublicKeyToAddress returns ss58 address of ed25519 public key
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	return EncodeSS58Address(common.FromHex(pubKeyHex), b.SS58Format)
+}
+
+// VerifyMPCPubKey verify mpc address and public key is matching
+func (b *Bridge) VerifyMPCPubKey(mpcAddress, mpcPubkey string) error {
+	address, err := b.PublicKeyToAddress(mpcPubkey)
+	if err != nil {
+		return err
+	}
+	if address != mpcAddress {
+		return fmt.Errorf("mpc address %v and public key address %v is not match", mpcAddress, address)
+	}
+	return nil
+}
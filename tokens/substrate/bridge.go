@@ -0,0 +1,173 @@
+// Package substrate implements the bridge interfaces for substrate based chains
+// which swap tokens through the `anyswap` pallet.
+//
+// Node json-rpc (GatewayConfig.APIAddress) is used to query chain state and
+// submit extrinsics, while the decoded blocks and events are queried from
+// the substrate api sidecar service (config by custom key 'sidecar').
+package substrate
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ensure Bridge impl tokens.CrossChainBridge
+	_ tokens.IBridge = &Bridge{}
+)
+
+const (
+	// PalletName name of anyswap pallet
+	PalletName = "anyswap"
+	// SwapOutEvent event emitted when user swapout
+	SwapOutEvent = "SwapOut"
+	// SwapInEvent event emitted when mpc swapin
+	SwapInEvent = "SwapIn"
+
+	// DefaultSS58Format default ss58 address format (generic substrate)
+	DefaultSS58Format uint16 = 42
+	// DefaultEraPeriod default period (in blocks) of mortal extrinsic
+	DefaultEraPeriod uint64 = 64
+)
+
+func init() {
+	tokens.RegisterBridge("substrate", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	}, "kusama", "polkadot", "parachain")
+}
+
+// Bridge substrate bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	RPCClientTimeout int
+	SidecarURLs      []string
+	SS58Format       uint16
+	PalletIndex      byte
+	SwapInCallIndex  byte
+	EraPeriod        uint64
+
+	genesisHash common.Hash
+
+	// extrinsic hash => location, and extrinsic hash => next block to scan
+	txLocations sync.Map
+	scanCursors sync.Map
+}
+
+// NewCrossChainBridge new bridge
+func NewCrossChainBridge() *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(),
+		RPCClientTimeout:     client.GetDefaultTimeout(false),
+		SS58Format:           DefaultSS58Format,
+		EraPeriod:            DefaultEraPeriod,
+	}
+}
+
+// InitAfterConfig init variables (ie. extra members) after loading config
+func (b *Bridge) InitAfterConfig() {
+	isReload := router.IsReloading
+	logErrFunc := log.GetLogFuncOr(isReload, log.Error, log.Fatal)
+	chainID := b.ChainConfig.ChainID
+
+	if sidecar := params.GetCustom(chainID, "sidecar"); sidecar != "" {
+		b.SidecarURLs = strings.Split(sidecar, ",")
+	} else {
+		logErrFunc("substrate bridge must config custom 'sidecar'", "chainID", chainID)
+		return
+	}
+	palletIndex, err := parseUintCustom(chainID, "palletIndex", 8)
+	if err != nil {
+		logErrFunc("substrate bridge must config custom 'palletIndex'", "chainID", chainID, "err", err)
+		return
+	}
+	b.PalletIndex = byte(palletIndex)
+	if swapInCallIndex, errt := parseUintCustom(chainID, "swapInCallIndex", 8); errt == nil {
+		b.SwapInCallIndex = byte(swapInCallIndex)
+	}
+	if ss58Format, errt := parseUintCustom(chainID, "ss58Format", 14); errt == nil {
+		b.SS58Format = uint16(ss58Format)
+	}
+	if eraPeriod, errt := parseUintCustom(chainID, "eraPeriod", 32); errt == nil && eraPeriod > 0 {
+		b.EraPeriod = eraPeriod
+	}
+	if timeout := params.GetRPCClientTimeout(chainID); timeout != 0 {
+		b.RPCClientTimeout = timeout
+	}
+	log.Info("init substrate bridge success", "chainID", chainID, "blockChain", b.ChainConfig.BlockChain,
+		"ss58Format", b.SS58Format, "palletIndex", b.PalletIndex, "swapInCallIndex", b.SwapInCallIndex, "eraPeriod", b.EraPeriod)
+}
+
+func parseUintCustom(chainID, key string, bitSize int) (uint64, error) {
+	custom := params.GetCustom(chainID, key)
+	if custom == "" {
+		return 0, fmt.Errorf("custom '%v' is not configed", key)
+	}
+	return strconv.ParseUint(custom, 10, bitSize)
+}
+
+// InitRouterInfo init router info.
+// the router contract of substrate chain is the mpc account which is allowed to call swapin.
+func (b *Bridge) InitRouterInfo(routerContract string) (err error) {
+	if routerContract == "" {
+		return nil
+	}
+	chainID := b.ChainConfig.ChainID
+	log.Info(fmt.Sprintf("[%5v] start init router info", chainID), "routerContract", routerContract)
+	if !b.IsValidAddress(routerContract) {
+		return fmt.Errorf("wrong router mpc address '%v'", routerContract)
+	}
+	routerMPC := routerContract
+	routerMPCPubkey, err := router.GetMPCPubkey(routerMPC)
+	if err != nil {
+		log.Warn("get mpc public key failed", "mpc", routerMPC, "err", err)
+		return err
+	}
+	if err = b.VerifyMPCPubKey(routerMPC, routerMPCPubkey); err != nil {
+		log.Warn("verify mpc public key failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
+		return err
+	}
+	router.SetRouterInfo(
+		routerContract,
+		&router.SwapRouterInfo{
+			RouterMPC: routerMPC,
+		},
+	)
+	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+
+	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
+		"routerContract", routerContract, "routerMPC", routerMPC)
+	return nil
+}
+
+// GetLatestBlockNumber impl (finalized height)
+func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
+	return b.GetFinalizedHeight()
+}
+
+// GetLatestBlockNumberOf impl (finalized height)
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	return b.GetFinalizedHeightOf(url)
+}
+
+// GetBalance get free balance
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	info, err := b.GetSidecarBalanceInfo(account)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := new(big.Int).SetString(info.Free, 10)
+	if !ok {
+		return nil, fmt.Errorf("wrong free balance '%v'", info.Free)
+	}
+	return balance, nil
+}
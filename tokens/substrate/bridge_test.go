@@ -0,0 +1,341 @@
+package substrate
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	tTokenID       = "KSM"
+	tToken         = "native"
+	tFromChain     = "1000"
+	tToChain       = "2000"
+	tFinalized     = 200
+	tFinalizedHash = "0x00000000000000000000000000000000000000000000000000000000000000c8"
+	tGenesisHash   = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	tSwapoutBlock  = 150
+)
+
+func TestSS58Address(t *testing.T) {
+	alice := common.FromHex("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d")
+	for format, want := range map[uint16]string{
+		42: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		0:  "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5",
+	} {
+		address, err := EncodeSS58Address(alice, format)
+		if err != nil || address != want {
+			t.Fatalf("encode ss58 address failed. format %v have %v want %v err %v", format, address, want, err)
+		}
+		accountID, decodedFormat, err := DecodeSS58Address(address)
+		if err != nil || decodedFormat != format || hex.EncodeToString(accountID) != hex.EncodeToString(alice) {
+			t.Fatalf("decode ss58 address failed. format %v err %v", format, err)
+		}
+	}
+	// two bytes format
+	address, _ := EncodeSS58Address(alice, 1284)
+	if _, format, err := DecodeSS58Address(address); err != nil || format != 1284 {
+		t.Fatalf("decode two bytes format address failed. format %v err %v", format, err)
+	}
+	if _, _, err := DecodeSS58Address("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ"); err == nil {
+		t.Errorf("decode address with wrong checksum should fail")
+	}
+}
+
+func TestScaleEncode(t *testing.T) {
+	for value, want := range map[uint64]string{
+		0:             "00",
+		1:             "04",
+		63:            "fc",
+		64:            "0101",
+		16383:         "fdff",
+		16384:         "02000100",
+		1073741823:    "feffffff",
+		1073741824:    "0300000040",
+		1<<64 - 1:     "13ffffffffffffffff",
+		1000000000000: "070010a5d4e8",
+	} {
+		if have := hex.EncodeToString(EncodeCompact(value)); have != want {
+			t.Errorf("encode compact %v failed. have %v want %v", value, have, want)
+		}
+	}
+	if have := hex.EncodeToString(EncodeMortalEra(42, 64)); have != "a502" {
+		t.Errorf("encode mortal era failed. have %v want a502", have)
+	}
+	if have := hex.EncodeToString(EncodeBytes([]byte("abc"))); have != "0c616263" {
+		t.Errorf("encode bytes failed. have %v", have)
+	}
+}
+
+// stubNode serves node json-rpc (POST) and sidecar api (GET)
+type stubNode struct {
+	results map[string]interface{}
+}
+
+func (s *stubNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		if result, exist := s.results[r.URL.Path]; exist {
+			_ = json.NewEncoder(w).Encode(result)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var req struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if result, exist := s.results[req.Method]; exist {
+		resp["result"] = result
+	} else {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+type testEnv struct {
+	node       *stubNode
+	server     *httptest.Server
+	srcBridge  *Bridge
+	dstBridge  *Bridge
+	mpcKey     ed25519.PrivateKey
+	mpcAddress string
+	userAddr   string
+}
+
+func newTestBridge(chainID, url, routerContract string) *Bridge {
+	b := NewCrossChainBridge()
+	b.SidecarURLs = []string{url}
+	b.PalletIndex = 60
+	b.SwapInCallIndex = 1
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{url}})
+	chainCfg := &tokens.ChainConfig{
+		ChainID:        chainID,
+		BlockChain:     "kusama",
+		RouterContract: routerContract,
+		Confirmations:  3,
+	}
+	_ = chainCfg.CheckConfig()
+	b.SetChainConfig(chainCfg)
+	b.SetTokenConfig(tToken, &tokens.TokenConfig{
+		TokenID:         tTokenID,
+		Decimals:        12,
+		ContractAddress: tToken,
+	})
+	return b
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	env := &testEnv{node: &stubNode{results: make(map[string]interface{})}}
+	env.server = httptest.NewServer(env.node)
+	_, env.mpcKey, _ = ed25519.GenerateKey(nil)
+	userPub, _, _ := ed25519.GenerateKey(nil)
+	env.mpcAddress, _ = EncodeSS58Address(env.mpcKey.Public().(ed25519.PublicKey), DefaultSS58Format)
+	env.userAddr, _ = EncodeSS58Address(userPub, DefaultSS58Format)
+
+	env.srcBridge = newTestBridge(tFromChain, env.server.URL, env.mpcAddress)
+	env.dstBridge = newTestBridge(tToChain, env.server.URL, env.mpcAddress)
+	router.SetBridge(tFromChain, env.srcBridge)
+	router.SetBridge(tToChain, env.dstBridge)
+	router.SetMultichainToken(tTokenID, tFromChain, tToken)
+	router.SetMultichainToken(tTokenID, tToChain, tToken)
+	router.SetRouterInfo(env.mpcAddress, &router.SwapRouterInfo{RouterMPC: env.mpcAddress})
+
+	tokens.InitRouterSwapType("erc20swap")
+	oneKSM := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	swapCfg := &tokens.SwapConfig{
+		MaximumSwap:           new(big.Int).Mul(oneKSM, big.NewInt(100)),
+		MinimumSwap:           new(big.Int).Div(oneKSM, big.NewInt(10000)),
+		BigValueThreshold:     new(big.Int).Mul(oneKSM, big.NewInt(10)),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        new(big.Int).Div(oneKSM, big.NewInt(100)),
+		MinimumSwapFee:        new(big.Int).Div(oneKSM, big.NewInt(100000)),
+	}
+	swapConfig := new(sync.Map)
+	swapConfig.Store(tFromChain, swapCfg)
+	swapConfig.Store(tToChain, swapCfg)
+	swapConfigs := new(sync.Map)
+	swapConfigs.Store(tTokenID, swapConfig)
+	tokens.SetSwapConfigs(swapConfigs)
+
+	env.node.results["chain_getFinalizedHead"] = tFinalizedHash
+	env.node.results["chain_getHeader"] = map[string]interface{}{"number": fmt.Sprintf("0x%x", tFinalized)}
+	env.node.results["chain_getBlockHash"] = tFinalizedHash
+	env.node.results["state_getRuntimeVersion"] = map[string]interface{}{"specName": "kusama", "specVersion": 9100, "transactionVersion": 5}
+	env.node.results["system_accountNextIndex"] = 7
+	return env
+}
+
+func (env *testEnv) addSwapoutBlock(extrinsics ...map[string]interface{}) {
+	timestamp := map[string]interface{}{
+		"method":  map[string]interface{}{"pallet": "timestamp", "method": "set"},
+		"args":    map[string]interface{}{"now": "1600000000000"},
+		"success": true,
+	}
+	env.node.results[fmt.Sprintf("/blocks/%d", tSwapoutBlock)] = map[string]interface{}{
+		"number":     fmt.Sprint(tSwapoutBlock),
+		"hash":       "0x0000000000000000000000000000000000000000000000000000000000000096",
+		"extrinsics": append([]map[string]interface{}{timestamp}, extrinsics...),
+	}
+}
+
+func swapoutExtrinsic(from, bind, amount string, toChainID int, success bool) map[string]interface{} {
+	return map[string]interface{}{
+		"method":    map[string]interface{}{"pallet": PalletName, "method": "swapOut"},
+		"signature": map[string]interface{}{"signature": "0x", "signer": map[string]interface{}{"id": from}},
+		"hash":      "0x1111111111111111111111111111111111111111111111111111111111111111",
+		"success":   success,
+		"events": []map[string]interface{}{
+			{
+				"method": map[string]interface{}{"pallet": PalletName, "method": SwapOutEvent},
+				"data":   []interface{}{common.ToHex([]byte(tToken)), from, common.ToHex([]byte(bind)), amount, fmt.Sprint(toChainID)},
+			},
+			{
+				"method": map[string]interface{}{"pallet": "system", "method": "ExtrinsicSuccess"},
+				"data":   []interface{}{},
+			},
+		},
+	}
+}
+
+func TestRegisterAndVerifySwap(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.server.Close()
+
+	env.addSwapoutBlock(
+		swapoutExtrinsic(env.userAddr, env.userAddr, "1000000000000", 2000, true),
+		swapoutExtrinsic(env.userAddr, env.userAddr, "1000000000000", 2000, false),
+	)
+	txid := fmt.Sprintf("%d-1", tSwapoutBlock)
+
+	swapInfos, errs := env.srcBridge.RegisterSwap(txid, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if len(swapInfos) != 1 || len(errs) != 1 || errs[0] != nil {
+		t.Fatalf("register swap failed. %v", errs)
+	}
+	swapInfo := swapInfos[0]
+	if swapInfo.Bind != env.userAddr || swapInfo.ToChainID.String() != tToChain ||
+		swapInfo.Value.String() != "1000000000000" || swapInfo.From != env.userAddr ||
+		swapInfo.To != env.mpcAddress || swapInfo.Height != tSwapoutBlock ||
+		swapInfo.Timestamp != 1600000000 || swapInfo.ERC20SwapInfo.TokenID != tTokenID {
+		t.Fatalf("register swap with wrong info %+v", swapInfo)
+	}
+
+	if _, err := env.srcBridge.VerifyTransaction(txid, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType}); err != nil {
+		t.Fatalf("verify swap failed. %v", err)
+	}
+	if _, err := env.srcBridge.VerifyTransaction(txid, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: 1}); err != tokens.ErrLogIndexOutOfRange {
+		t.Errorf("verify with log index 1 want error %v but got %v", tokens.ErrLogIndexOutOfRange, err)
+	}
+	failedTxid := fmt.Sprintf("%d-2", tSwapoutBlock)
+	if _, err := env.srcBridge.VerifyTransaction(failedTxid, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType}); err != tokens.ErrTxWithWrongStatus {
+		t.Errorf("verify failed extrinsic want error %v but got %v", tokens.ErrTxWithWrongStatus, err)
+	}
+}
+
+func TestBuildAndSignTransaction(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.server.Close()
+
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: tToken, TokenID: tTokenID}},
+			SwapID:      fmt.Sprintf("%d-1", tSwapoutBlock),
+			SwapType:    tokens.ERC20SwapType,
+			Bind:        env.userAddr,
+			FromChainID: big.NewInt(1000),
+			ToChainID:   big.NewInt(2000),
+		},
+		From:        env.mpcAddress,
+		OriginValue: big.NewInt(1000000000000),
+	}
+	env.node.results["chain_getBlockHash"] = tGenesisHash
+	_, err := env.dstBridge.GetGenesisHash()
+	if err != nil {
+		t.Fatalf("get genesis hash failed. %v", err)
+	}
+	env.node.results["chain_getBlockHash"] = tFinalizedHash
+
+	rawTx, err := env.dstBridge.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatalf("build tx failed. %v", err)
+	}
+	tx := rawTx.(*Extrinsic)
+	extra := args.Extra.SubstrateExtra
+	if *args.Extra.Sequence != 7 || *extra.BlockNumber != tFinalized || *extra.BlockHash != tFinalizedHash ||
+		*extra.SpecVersion != 9100 || *extra.TxVersion != 5 || *extra.EraPeriod != DefaultEraPeriod {
+		t.Fatalf("build tx with wrong extra %+v", extra)
+	}
+	// one KSM (12 decimals) with fee rate 0.1%
+	if args.SwapValue.String() != "999000000000" {
+		t.Fatalf("build tx with wrong swap value %v", args.SwapValue)
+	}
+	if tx.Call[0] != 60 || tx.Call[1] != 1 || tx.GenesisHash != common.HexToHash(tGenesisHash) {
+		t.Fatalf("build tx with wrong call or genesis hash")
+	}
+
+	msgHash := common.ToHex(tx.SigningPayload())
+
+	// rebuild with the same extra args (as oracles do) must have the same msg hash
+	env.node.results["system_accountNextIndex"] = 8
+	rebuildArgs := &tokens.BuildTxArgs{
+		SwapArgs:    args.SwapArgs,
+		From:        args.From,
+		OriginValue: args.OriginValue,
+		Extra:       args.Extra,
+	}
+	rebuildTx, err := env.dstBridge.BuildRawTransaction(rebuildArgs)
+	if err != nil {
+		t.Fatalf("rebuild tx failed. %v", err)
+	}
+	if err = env.dstBridge.VerifyMsgHash(rebuildTx, []string{msgHash}); err != nil {
+		t.Fatalf("verify msg hash failed. %v", err)
+	}
+
+	signedTx, txHash, err := env.dstBridge.SignTransactionWithPrivateKey(rawTx, hex.EncodeToString(env.mpcKey.Seed()))
+	if err != nil {
+		t.Fatalf("sign tx failed. %v", err)
+	}
+	encoded, err := signedTx.(*Extrinsic).Encode()
+	if err != nil {
+		t.Fatalf("encode signed tx failed. %v", err)
+	}
+	// length prefix, version, address type, account id, signature type, signature, era, nonce, tip, call
+	body := encoded[2:]
+	if body[0] != 0x84 || hex.EncodeToString(body[2:34]) != hex.EncodeToString(env.mpcKey.Public().(ed25519.PublicKey)) {
+		t.Fatalf("encode signed tx with wrong signer")
+	}
+	if !ed25519.Verify(env.mpcKey.Public().(ed25519.PublicKey), tx.SigningPayload(), body[35:99]) {
+		t.Fatalf("encode signed tx with wrong signature")
+	}
+
+	env.node.results["author_submitExtrinsic"] = txHash
+	sentHash, err := env.dstBridge.SendTransaction(signedTx)
+	if err != nil || sentHash != txHash {
+		t.Fatalf("send tx failed. hash %v err %v", sentHash, err)
+	}
+
+	// the sent extrinsic is found by scanning blocks from the era checkpoint
+	env.node.results[fmt.Sprintf("/blocks/%d", tFinalized)] = map[string]interface{}{
+		"number": fmt.Sprint(tFinalized),
+		"hash":   tFinalizedHash,
+		"extrinsics": []map[string]interface{}{
+			{"method": map[string]interface{}{"pallet": PalletName, "method": "swapIn"}, "hash": txHash, "success": true},
+		},
+	}
+	txStatus, err := env.dstBridge.GetTransactionStatus(txHash)
+	if err != nil || txStatus.BlockHeight != tFinalized || txStatus.Confirmations != 1 || txStatus.IsSwapTxOnChainAndFailed() {
+		t.Fatalf("get sent tx status failed. status %+v err %v", txStatus, err)
+	}
+}
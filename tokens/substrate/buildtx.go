@@ -0,0 +1,201 @@
+package substrate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
+		return nil, tokens.ErrToChainIDMismatch
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if args.From != routerMPC {
+		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
+		return nil, tokens.ErrSenderMismatch
+	}
+
+	amount, err := b.getSwapAmount(args)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("wrong swap value %v", amount)
+	}
+	args.To = args.Bind     // to
+	args.SwapValue = amount // swapValue
+
+	call, err := b.buildSwapInCall(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args, call)
+}
+
+func (b *Bridge) getMultichainToken(args *tokens.BuildTxArgs) (string, error) {
+	erc20SwapInfo := args.ERC20SwapInfo
+	if erc20SwapInfo == nil || erc20SwapInfo.TokenID == "" {
+		return "", errors.New("build router swaptx without tokenID")
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, b.ChainConfig.ChainID)
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", b.ChainConfig.ChainID)
+		return "", tokens.ErrMissTokenConfig
+	}
+	return multichainToken, nil
+}
+
+func (b *Bridge) getSwapAmount(args *tokens.BuildTxArgs) (*big.Int, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	if !b.IsValidAddress(args.Bind) {
+		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
+		return nil, errors.New("can not swapout to empty or invalid receiver")
+	}
+	erc20SwapInfo := args.ERC20SwapInfo
+	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
+	if fromBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := fromBridge.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", args.FromChainID, "token", erc20SwapInfo.Token)
+		return nil, tokens.ErrMissTokenConfig
+	}
+	toTokenCfg := b.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	amount, swapFeeInfo := tokens.CalcSwapValueWithFee(erc20SwapInfo.TokenID, b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo, args.OriginTime)
+	args.SwapFeeInfo = swapFeeInfo
+	return amount, nil
+}
+
+// buildSwapInCall encode call data of
+// anyswap.swap_in(swap_id: Vec<u8>, token: Vec<u8>, to: AccountId, amount: Compact<Balance>, from_chain_id: Compact<u64>)
+func (b *Bridge) buildSwapInCall(args *tokens.BuildTxArgs) ([]byte, error) {
+	multichainToken, err := b.getMultichainToken(args)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := b.GetAccountID(args.Bind)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := EncodeCompactBig(args.SwapValue)
+	if err != nil {
+		return nil, err
+	}
+	fromChainID, err := EncodeCompactBig(args.FromChainID)
+	if err != nil {
+		return nil, err
+	}
+	call := []byte{b.PalletIndex, b.SwapInCallIndex}
+	call = append(call, EncodeBytes([]byte(args.SwapID))...)
+	call = append(call, EncodeBytes([]byte(multichainToken))...)
+	call = append(call, receiver...)
+	call = append(call, amount...)
+	call = append(call, fromChainID...)
+	return call, nil
+}
+
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (err error) {
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	if args.Extra.SubstrateExtra == nil {
+		args.Extra.SubstrateExtra = &tokens.SubstrateExtraArgs{}
+	}
+	if args.Extra.Sequence == nil {
+		nonce, errt := b.GetAccountNextIndex(args.From)
+		if errt != nil {
+			return errt
+		}
+		args.Extra.Sequence = &nonce
+	}
+	extra := args.Extra.SubstrateExtra
+	if extra.BlockNumber == nil || extra.BlockHash == nil {
+		blockNumber, errt := b.GetFinalizedHeight()
+		if errt != nil {
+			return errt
+		}
+		blockHash, errt := b.GetBlockHash(blockNumber)
+		if errt != nil {
+			return errt
+		}
+		blockHashStr := blockHash.Hex()
+		extra.BlockNumber = &blockNumber
+		extra.BlockHash = &blockHashStr
+	}
+	if extra.EraPeriod == nil {
+		eraPeriod := b.EraPeriod
+		extra.EraPeriod = &eraPeriod
+	}
+	if extra.SpecVersion == nil || extra.TxVersion == nil {
+		runtimeVersion, errt := b.GetRuntimeVersion()
+		if errt != nil {
+			return errt
+		}
+		extra.SpecVersion = &runtimeVersion.SpecVersion
+		extra.TxVersion = &runtimeVersion.TransactionVersion
+	}
+	return nil
+}
+
+func (b *Bridge) buildTx(args *tokens.BuildTxArgs, call []byte) (rawTx interface{}, err error) {
+	genesisHash, err := b.GetGenesisHash()
+	if err != nil {
+		return nil, err
+	}
+	extra := args.Extra.SubstrateExtra
+	tx := &Extrinsic{
+		Call:        call,
+		Era:         EncodeMortalEra(*extra.BlockNumber, *extra.EraPeriod),
+		Nonce:       *args.Extra.Sequence,
+		Tip:         big.NewInt(0),
+		SpecVersion: *extra.SpecVersion,
+		TxVersion:   *extra.TxVersion,
+		GenesisHash: genesisHash,
+		BlockHash:   common.HexToHash(*extra.BlockHash),
+		BlockNumber: *extra.BlockNumber,
+	}
+
+	log.Info("build tx success",
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "to", args.To, "bind", args.Bind, "nonce", tx.Nonce,
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"blockNumber", *extra.BlockNumber, "blockHash", *extra.BlockHash,
+		"specVersion", tx.SpecVersion, "txVersion", tx.TxVersion,
+		"replaceNum", args.GetReplaceNum())
+
+	return tx, nil
+}
@@ -0,0 +1,200 @@
+package substrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/callapi"
+)
+
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errEmptyURLs = errors.New("empty URLs")
+)
+
+// RuntimeVersion result of 'state_getRuntimeVersion'
+type RuntimeVersion struct {
+	SpecName           string `json:"specName"`
+	SpecVersion        uint32 `json:"specVersion"`
+	TransactionVersion uint32 `json:"transactionVersion"`
+}
+
+// SidecarMethod pallet and method name
+type SidecarMethod struct {
+	Pallet string `json:"pallet"`
+	Method string `json:"method"`
+}
+
+// Is check pallet and method name
+func (m *SidecarMethod) Is(pallet, method string) bool {
+	return strings.EqualFold(m.Pallet, pallet) && strings.EqualFold(m.Method, method)
+}
+
+// SidecarEvent decoded event
+type SidecarEvent struct {
+	Method SidecarMethod     `json:"method"`
+	Data   []json.RawMessage `json:"data"`
+}
+
+// SidecarSignature signature of extrinsic
+type SidecarSignature struct {
+	Signature string `json:"signature"`
+	Signer    struct {
+		ID string `json:"id"`
+	} `json:"signer"`
+}
+
+// SidecarExtrinsic decoded extrinsic
+type SidecarExtrinsic struct {
+	Method    SidecarMethod              `json:"method"`
+	Signature *SidecarSignature          `json:"signature"`
+	Nonce     string                     `json:"nonce"`
+	Args      map[string]json.RawMessage `json:"args"`
+	Hash      string                     `json:"hash"`
+	Events    []*SidecarEvent            `json:"events"`
+	Success   bool                       `json:"success"`
+}
+
+// SidecarBlock result of sidecar '/blocks/{blockId}'
+type SidecarBlock struct {
+	Number     string              `json:"number"`
+	Hash       string              `json:"hash"`
+	ParentHash string              `json:"parentHash"`
+	Extrinsics []*SidecarExtrinsic `json:"extrinsics"`
+	Finalized  bool                `json:"finalized"`
+}
+
+// SidecarBalanceInfo result of sidecar '/accounts/{accountId}/balance-info'
+type SidecarBalanceInfo struct {
+	Nonce string `json:"nonce"`
+	Free  string `json:"free"`
+}
+
+func (b *Bridge) rpcCall(result interface{}, method string, params ...interface{}) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, result, url, method, params...)
+		if err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, method, params...)
+}
+
+func (b *Bridge) sidecarGet(result interface{}, path string) (err error) {
+	urls := b.SidecarURLs
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = client.RPCGetWithTimeout(result, strings.TrimSuffix(url, "/")+path, b.RPCClientTimeout)
+		if err == nil {
+			return nil
+		}
+	}
+	return wrapRPCQueryError(err, "sidecar "+path)
+}
+
+// GetFinalizedHeight get height of finalized head
+func (b *Bridge) GetFinalizedHeight() (latest uint64, err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return 0, errEmptyURLs
+	}
+	for _, url := range urls {
+		latest, err = b.GetFinalizedHeightOf(url)
+		if err == nil {
+			return latest, nil
+		}
+	}
+	return 0, err
+}
+
+// GetFinalizedHeightOf get height of finalized head of specified url
+func (b *Bridge) GetFinalizedHeightOf(url string) (uint64, error) {
+	return callapi.KsmGetLatestBlockNumberOf(url, b.GatewayConfig, b.RPCClientTimeout)
+}
+
+// GetBlockHash call chain_getBlockHash
+func (b *Bridge) GetBlockHash(number uint64) (result common.Hash, err error) {
+	err = b.rpcCall(&result, "chain_getBlockHash", number)
+	return result, err
+}
+
+// GetGenesisHash get (and cache) genesis block hash
+func (b *Bridge) GetGenesisHash() (common.Hash, error) {
+	if b.genesisHash != (common.Hash{}) {
+		return b.genesisHash, nil
+	}
+	genesisHash, err := b.GetBlockHash(0)
+	if err != nil {
+		return genesisHash, err
+	}
+	if genesisHash == (common.Hash{}) {
+		return genesisHash, wrapRPCQueryError(nil, "chain_getBlockHash", 0)
+	}
+	b.genesisHash = genesisHash
+	return genesisHash, nil
+}
+
+// GetRuntimeVersion call state_getRuntimeVersion
+func (b *Bridge) GetRuntimeVersion() (result *RuntimeVersion, err error) {
+	err = b.rpcCall(&result, "state_getRuntimeVersion")
+	if err == nil && result == nil {
+		err = wrapRPCQueryError(nil, "state_getRuntimeVersion")
+	}
+	return result, err
+}
+
+// GetAccountNextIndex call system_accountNextIndex (includes txs in pool)
+func (b *Bridge) GetAccountNextIndex(address string) (result uint64, err error) {
+	err = b.rpcCall(&result, "system_accountNextIndex", address)
+	return result, err
+}
+
+// SubmitExtrinsic call author_submitExtrinsic (broadcast to all gateways)
+func (b *Bridge) SubmitExtrinsic(rawHex string) (txHash string, err error) {
+	urls := append([]string{}, b.GatewayConfig.APIAddress...)
+	urls = append(urls, b.GatewayConfig.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	var result common.Hash
+	for _, url := range urls {
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &result, url, "author_submitExtrinsic", rawHex)
+		if err == nil {
+			txHash = result.Hex()
+		}
+	}
+	if txHash != "" {
+		return txHash, nil
+	}
+	return "", wrapRPCQueryError(err, "author_submitExtrinsic")
+}
+
+// GetSidecarBlock get decoded block from sidecar
+func (b *Bridge) GetSidecarBlock(number uint64) (result *SidecarBlock, err error) {
+	err = b.sidecarGet(&result, fmt.Sprintf("/blocks/%d", number))
+	if err == nil && result == nil {
+		err = tokens.ErrTxNotFound
+	}
+	return result, err
+}
+
+// GetSidecarBalanceInfo get account balance info from sidecar
+func (b *Bridge) GetSidecarBalanceInfo(address string) (result *SidecarBalanceInfo, err error) {
+	err = b.sidecarGet(&result, fmt.Sprintf("/accounts/%s/balance-info", address))
+	if err == nil && result == nil {
+		err = wrapRPCQueryError(nil, "balance-info", address)
+	}
+	return result, err
+}
@@ -0,0 +1,141 @@
+package substrate
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ExtrinsicVersion extrinsic format version
+	ExtrinsicVersion = 4
+	// SignatureLength length of ed25519 signature
+	SignatureLength = 64
+
+	signedExtrinsicFlag   = 0x80
+	multiAddressID        = 0x00
+	multiSignatureED25519 = 0x00
+
+	// signed payloads longer than this are hashed before signing
+	maxUnhashedPayloadLength = 256
+)
+
+var (
+	errWrongSignatureLength = errors.New("wrong signature length")
+	errExtrinsicNotSigned   = errors.New("extrinsic is not signed")
+)
+
+// EncodeMortalEra encode mortal era which is valid in [checkpoint, checkpoint+period)
+func EncodeMortalEra(checkpoint, period uint64) []byte {
+	// period is rounded to power of two in range [4, 65536]
+	if period < 4 {
+		period = 4
+	}
+	if period > 1<<16 {
+		period = 1 << 16
+	}
+	if period&(period-1) != 0 {
+		period = 1 << bits.Len64(period)
+	}
+	phase := checkpoint % period
+	quantizeFactor := period >> 12
+	if quantizeFactor == 0 {
+		quantizeFactor = 1
+	}
+	low := uint64(bits.TrailingZeros64(period)) - 1
+	if low > 15 {
+		low = 15
+	}
+	encoded := uint16(low) | uint16(phase/quantizeFactor)<<4
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, encoded)
+	return buf
+}
+
+// Extrinsic signed extrinsic of version 4
+type Extrinsic struct {
+	Call        []byte
+	Era         []byte
+	Nonce       uint64
+	Tip         *big.Int
+	SpecVersion uint32
+	TxVersion   uint32
+	GenesisHash common.Hash
+	BlockHash   common.Hash
+	BlockNumber uint64 // era checkpoint, not encoded
+
+	Signer    []byte // account id
+	Signature []byte // ed25519 signature
+}
+
+func (tx *Extrinsic) encodeExtra() []byte {
+	tip := tx.Tip
+	if tip == nil {
+		tip = big.NewInt(0)
+	}
+	encodedTip, _ := EncodeCompactBig(tip)
+	extra := make([]byte, 0, len(tx.Era)+9+len(encodedTip))
+	extra = append(extra, tx.Era...)
+	extra = append(extra, EncodeCompact(tx.Nonce)...)
+	extra = append(extra, encodedTip...)
+	return extra
+}
+
+// SigningPayload get the message to be signed
+func (tx *Extrinsic) SigningPayload() []byte {
+	payload := make([]byte, 0, len(tx.Call)+128)
+	payload = append(payload, tx.Call...)
+	payload = append(payload, tx.encodeExtra()...)
+	payload = append(payload, EncodeUint32(tx.SpecVersion)...)
+	payload = append(payload, EncodeUint32(tx.TxVersion)...)
+	payload = append(payload, tx.GenesisHash.Bytes()...)
+	payload = append(payload, tx.BlockHash.Bytes()...)
+	if len(payload) > maxUnhashedPayloadLength {
+		hash := blake2b.Sum256(payload)
+		return hash[:]
+	}
+	return payload
+}
+
+// WithSignature returns a copy of extrinsic with signer and signature
+func (tx *Extrinsic) WithSignature(signer, signature []byte) (*Extrinsic, error) {
+	if len(signer) != PublicKeyLength {
+		return nil, errWrongPublicKeyLength
+	}
+	if len(signature) != SignatureLength {
+		return nil, errWrongSignatureLength
+	}
+	signedTx := *tx
+	signedTx.Signer = common.CopyBytes(signer)
+	signedTx.Signature = common.CopyBytes(signature)
+	return &signedTx, nil
+}
+
+// Encode encode signed extrinsic with length prefix
+func (tx *Extrinsic) Encode() ([]byte, error) {
+	if len(tx.Signer) != PublicKeyLength || len(tx.Signature) != SignatureLength {
+		return nil, errExtrinsicNotSigned
+	}
+	body := make([]byte, 0, len(tx.Call)+128)
+	body = append(body, signedExtrinsicFlag|ExtrinsicVersion)
+	body = append(body, multiAddressID)
+	body = append(body, tx.Signer...)
+	body = append(body, multiSignatureED25519)
+	body = append(body, tx.Signature...)
+	body = append(body, tx.encodeExtra()...)
+	body = append(body, tx.Call...)
+	return EncodeBytes(body), nil
+}
+
+// Hash get hash of signed extrinsic
+func (tx *Extrinsic) Hash() (common.Hash, error) {
+	encoded, err := tx.Encode()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return blake2b.Sum256(encoded), nil
+}
@@ -0,0 +1,18 @@
+package substrate
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// RegisterSwap api
+func (b *Bridge) RegisterSwap(txHash string, args *tokens.RegisterArgs) ([]*tokens.SwapTxInfo, []error) {
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, []error{tokens.ErrSwapTypeNotSupported}
+	}
+	swapInfo, err := b.verifySwapoutTx(txHash, args.LogIndex, true)
+	if err != nil {
+		log.Debug(b.ChainConfig.BlockChain+" register router swap error", "txHash", txHash, "logIndex", args.LogIndex, "err", err)
+	}
+	return []*tokens.SwapTxInfo{swapInfo}, []error{err}
+}
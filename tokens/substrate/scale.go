@@ -0,0 +1,75 @@
+package substrate
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+var (
+	maxCompactValue = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 536), big.NewInt(1))
+
+	errCompactOverflow = errors.New("compact value overflow")
+	errNegativeCompact = errors.New("compact value is negative")
+)
+
+// EncodeCompact scale encode unsigned integer in compact mode
+func EncodeCompact(value uint64) []byte {
+	return encodeCompactBig(new(big.Int).SetUint64(value))
+}
+
+// EncodeCompactBig scale encode big unsigned integer in compact mode
+func EncodeCompactBig(value *big.Int) ([]byte, error) {
+	if value.Sign() < 0 {
+		return nil, errNegativeCompact
+	}
+	if value.Cmp(maxCompactValue) > 0 {
+		return nil, errCompactOverflow
+	}
+	return encodeCompactBig(value), nil
+}
+
+func encodeCompactBig(value *big.Int) []byte {
+	if value.IsUint64() {
+		v := value.Uint64()
+		switch {
+		case v < 1<<6:
+			return []byte{byte(v << 2)}
+		case v < 1<<14:
+			buf := make([]byte, 2)
+			binary.LittleEndian.PutUint16(buf, uint16(v<<2)|0x01)
+			return buf
+		case v < 1<<30:
+			buf := make([]byte, 4)
+			binary.LittleEndian.PutUint32(buf, uint32(v<<2)|0x02)
+			return buf
+		}
+	}
+	// big integer mode: the upper six bits of the first byte is (bytes length - 4)
+	bigEndian := value.Bytes()
+	length := len(bigEndian)
+	if length < 4 {
+		length = 4
+	}
+	buf := make([]byte, 1+length)
+	buf[0] = byte((length-4)<<2) | 0x03
+	for i, b := range bigEndian {
+		buf[len(bigEndian)-i] = b
+	}
+	return buf
+}
+
+// EncodeBytes scale encode bytes with compact length prefix
+func EncodeBytes(data []byte) []byte {
+	prefix := EncodeCompact(uint64(len(data)))
+	result := make([]byte, 0, len(prefix)+len(data))
+	result = append(result, prefix...)
+	return append(result, data...)
+}
+
+// EncodeUint32 scale encode uint32 in little endian
+func EncodeUint32(value uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, value)
+	return buf
+}
@@ -0,0 +1,33 @@
+package substrate
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*Extrinsic)
+	if !ok {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	encoded, err := tx.Encode()
+	if err != nil {
+		return "", err
+	}
+	rawHex := common.ToHex(encoded)
+	txHash, err = b.SubmitExtrinsic(rawHex)
+	if err != nil {
+		log.Info("SendTransaction failed", "nonce", tx.Nonce, "err", err)
+	} else {
+		log.Info("SendTransaction success", "hash", txHash, "nonce", tx.Nonce)
+	}
+	if params.IsDebugMode() {
+		log.Infof("SendTransaction rawtx is %v", rawHex)
+	}
+	return txHash, err
+}
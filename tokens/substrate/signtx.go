@@ -0,0 +1,142 @@
+package substrate
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Extrinsic)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	sigHash := common.ToHex(tx.SigningPayload())
+	if !strings.EqualFold(sigHash, msgHashes[0]) {
+		log.Trace("message hash mismatch", "want", msgHashes[0], "have", sigHash)
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, args *tokens.BuildTxArgs) (*Extrinsic, error) {
+	tx, ok := rawTx.(*Extrinsic)
+	if !ok {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	checkCall, err := b.buildSwapInCall(args)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tx.Call, checkCall) {
+		return nil, errors.New("[sign] tx call mismatch")
+	}
+	return tx, nil
+}
+
+// MPCSignTransaction mpc sign raw tx
+func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, err := b.verifyTransactionReceiver(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SignWithPrivateKey() {
+		priKey := params.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+	}
+
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	pubKey := common.FromHex(mpcPubkey)
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, "", errWrongPublicKeyLength
+	}
+
+	payload := tx.SigningPayload()
+	msgHash := common.ToHex(payload)
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash)
+	keyID, rsvs, err := mpc.DoSignOneED(mpcPubkey, msgHash, msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash)
+
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many",
+			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
+		return nil, "", errors.New("get sign status require one rsv but return many")
+	}
+
+	signature := common.FromHex(rsvs[0])
+	if len(signature) != SignatureLength {
+		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", SignatureLength)
+		return nil, "", errWrongSignatureLength
+	}
+
+	signedTx, txHash, err := b.signTxWithSignature(tx, pubKey, signature)
+	if err != nil {
+		return nil, "", err
+	}
+	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash, "nonce", tx.Nonce)
+	return signedTx, txHash, nil
+}
+
+// SignTransactionWithPrivateKey sign tx with ed25519 private key (hex of 32 bytes seed)
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Extrinsic)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	seed := common.FromHex(priKey)
+	if len(seed) != ed25519.SeedSize {
+		return nil, "", errors.New("wrong ed25519 private key seed length")
+	}
+	edPriKey := ed25519.NewKeyFromSeed(seed)
+	pubKey := edPriKey.Public().(ed25519.PublicKey)
+	signature := ed25519.Sign(edPriKey, tx.SigningPayload())
+	return b.signTxWithSignature(tx, pubKey, signature)
+}
+
+func (b *Bridge) signTxWithSignature(tx *Extrinsic, pubKey, signature []byte) (*Extrinsic, string, error) {
+	if !ed25519.Verify(pubKey, tx.SigningPayload(), signature) {
+		return nil, "", errors.New("verify signature failed")
+	}
+	signedTx, err := tx.WithSignature(pubKey, signature)
+	if err != nil {
+		return nil, "", err
+	}
+	hash, err := signedTx.Hash()
+	if err != nil {
+		return nil, "", err
+	}
+	txHash := hash.Hex()
+	// the extrinsic is valid since the era checkpoint, scan from there to find it
+	b.scanCursors.LoadOrStore(strings.ToLower(txHash), tx.BlockNumber)
+	return signedTx, txHash, nil
+}
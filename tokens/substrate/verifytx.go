@@ -0,0 +1,320 @@
+package substrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// TxLocation location of extrinsic
+type TxLocation struct {
+	BlockNumber uint64
+	Index       int
+}
+
+// ParseExtrinsicID parse extrinsic id of format '<blockNumber>-<extrinsicIndex>'
+func ParseExtrinsicID(extrinsicID string) (*TxLocation, error) {
+	parts := strings.Split(extrinsicID, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("wrong extrinsic id '%v'", extrinsicID)
+	}
+	blockNumber, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wrong extrinsic id '%v'", extrinsicID)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("wrong extrinsic id '%v'", extrinsicID)
+	}
+	return &TxLocation{BlockNumber: blockNumber, Index: int(index)}, nil
+}
+
+// IsStatusOk impl tokens.StatusInterface
+func (ext *SidecarExtrinsic) IsStatusOk() bool {
+	return ext.Success
+}
+
+// GetTimestamp get block timestamp (in seconds) from the 'timestamp.set' inherent
+func (block *SidecarBlock) GetTimestamp() uint64 {
+	for _, ext := range block.Extrinsics {
+		if !ext.Method.Is("timestamp", "set") {
+			continue
+		}
+		var now string
+		if err := json.Unmarshal(ext.Args["now"], &now); err == nil {
+			if millis, err := strconv.ParseUint(now, 10, 64); err == nil {
+				return millis / 1000
+			}
+		}
+	}
+	return 0
+}
+
+// locateTx find block number and index of extrinsic.
+// txHash can be extrinsic id (blockNumber-index) or extrinsic hash.
+// extrinsic hash can not be queried directly, so we scan finalized blocks
+// starting from the era checkpoint of the signed extrinsic (or the recent
+// era period blocks if the checkpoint is unknown), and remember the progress.
+func (b *Bridge) locateTx(txHash string) (*TxLocation, *SidecarBlock, error) {
+	if !common.IsHexHash(txHash) {
+		location, err := ParseExtrinsicID(txHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		block, err := b.GetSidecarBlock(location.BlockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		if location.Index >= len(block.Extrinsics) {
+			return nil, nil, tokens.ErrTxNotFound
+		}
+		return location, block, nil
+	}
+
+	txHash = strings.ToLower(txHash)
+	if location, exist := b.txLocations.Load(txHash); exist {
+		loc := location.(*TxLocation)
+		block, err := b.GetSidecarBlock(loc.BlockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		if loc.Index < len(block.Extrinsics) && strings.EqualFold(block.Extrinsics[loc.Index].Hash, txHash) {
+			return loc, block, nil
+		}
+		b.txLocations.Delete(txHash)
+	}
+
+	finalized, err := b.GetFinalizedHeight()
+	if err != nil {
+		return nil, nil, err
+	}
+	var start uint64
+	if cursor, exist := b.scanCursors.Load(txHash); exist {
+		start = cursor.(uint64)
+	} else if finalized >= b.EraPeriod {
+		start = finalized - b.EraPeriod + 1
+	}
+	for height := start; height <= finalized; height++ {
+		block, errt := b.GetSidecarBlock(height)
+		if errt != nil {
+			return nil, nil, errt
+		}
+		for i, ext := range block.Extrinsics {
+			if strings.EqualFold(ext.Hash, txHash) {
+				location := &TxLocation{BlockNumber: height, Index: i}
+				b.txLocations.Store(txHash, location)
+				b.scanCursors.Delete(txHash)
+				return location, block, nil
+			}
+		}
+		b.scanCursors.Store(txHash, height+1)
+	}
+	return nil, nil, tokens.ErrTxNotFound
+}
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	location, block, err := b.locateTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Extrinsics[location.Index], nil
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	location, block, err := b.locateTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	ext := block.Extrinsics[location.Index]
+	txStatus := &tokens.TxStatus{
+		Receipt:     ext,
+		BlockHeight: location.BlockNumber,
+		BlockHash:   block.Hash,
+		BlockTime:   block.GetTimestamp(),
+	}
+	if ext.Signature != nil {
+		txStatus.Sender = ext.Signature.Signer.ID
+	}
+	if finalized, errt := b.GetFinalizedHeight(); errt == nil && finalized >= location.BlockNumber {
+		txStatus.Confirmations = finalized - location.BlockNumber + 1
+	}
+	return txStatus, nil
+}
+
+// VerifyTransaction api
+func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	if args.SwapType != tokens.ERC20SwapType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	return b.verifySwapoutTx(txHash, args.LogIndex, args.AllowUnstable)
+}
+
+func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool) (*tokens.SwapTxInfo, error) {
+	swapInfo := &tokens.SwapTxInfo{SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}}}
+	swapInfo.SwapType = tokens.ERC20SwapType // SwapType
+	swapInfo.Hash = strings.ToLower(txHash)  // Hash
+	swapInfo.LogIndex = logIndex             // LogIndex
+
+	ext, err := b.getStableSwapTx(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.parseSwapoutEvent(swapInfo, ext)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapoutInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify router swap tx stable pass",
+			"identifier", params.GetIdentifier(),
+			"from", swapInfo.From, "to", swapInfo.To,
+			"bind", swapInfo.Bind, "value", swapInfo.Value,
+			"txid", txHash, "logIndex", logIndex,
+			"height", swapInfo.Height, "timestamp", swapInfo.Timestamp,
+			"fromChainID", swapInfo.FromChainID, "toChainID", swapInfo.ToChainID,
+			"token", swapInfo.ERC20SwapInfo.Token, "tokenID", swapInfo.ERC20SwapInfo.TokenID)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) getStableSwapTx(swapInfo *tokens.SwapTxInfo, allowUnstable bool) (*SidecarExtrinsic, error) {
+	txStatus, err := b.GetTransactionStatus(swapInfo.Hash)
+	if err != nil {
+		log.Error("get tx status failed", "hash", swapInfo.Hash, "err", err)
+		return nil, err
+	}
+	if txStatus.BlockHeight < b.ChainConfig.InitialHeight {
+		return nil, tokens.ErrTxBeforeInitialHeight
+	}
+
+	swapInfo.Height = txStatus.BlockHeight  // Height
+	swapInfo.Timestamp = txStatus.BlockTime // Timestamp
+
+	if !allowUnstable && txStatus.Confirmations < b.ChainConfig.Confirmations {
+		return nil, tokens.ErrTxNotStable
+	}
+
+	ext, _ := txStatus.Receipt.(*SidecarExtrinsic)
+	if ext == nil || !ext.IsStatusOk() {
+		return nil, tokens.ErrTxWithWrongStatus
+	}
+	return ext, nil
+}
+
+func (b *Bridge) parseSwapoutEvent(swapInfo *tokens.SwapTxInfo, ext *SidecarExtrinsic) (err error) {
+	var swapoutEvent *SidecarEvent
+	count := 0
+	for _, event := range ext.Events {
+		if !event.Method.Is(PalletName, SwapOutEvent) {
+			continue
+		}
+		if count == swapInfo.LogIndex {
+			swapoutEvent = event
+			break
+		}
+		count++
+	}
+	if swapoutEvent == nil {
+		if count == 0 {
+			return tokens.ErrSwapoutLogNotFound
+		}
+		return tokens.ErrLogIndexOutOfRange
+	}
+
+	// SwapOut(token: Vec<u8>, from: AccountId, to: Vec<u8>, amount: Balance, to_chain_id: u64)
+	fields, err := parseEventData(swapoutEvent, 5)
+	if err != nil {
+		log.Warn("parse swapout event failed", "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex, "err", err)
+		return tokens.ErrTxWithWrongTopics
+	}
+	token := string(common.FromHex(fields[0]))
+	amount, ok := new(big.Int).SetString(fields[3], 10)
+	if !ok {
+		return tokens.ErrTxWithWrongValue
+	}
+	toChainID, ok := new(big.Int).SetString(fields[4], 10)
+	if !ok {
+		return tokens.ErrTxWithWrongTopics
+	}
+
+	tokenCfg := b.GetTokenConfig(token)
+	if tokenCfg == nil {
+		return tokens.ErrMissTokenConfig
+	}
+
+	routerContract := b.ChainConfig.RouterContract
+	swapInfo.TxTo = routerContract                    // TxTo
+	swapInfo.To = routerContract                      // To
+	swapInfo.From = fields[1]                         // From
+	swapInfo.Bind = string(common.FromHex(fields[2])) // Bind
+	swapInfo.Value = amount                           // Value
+	swapInfo.FromChainID = b.ChainConfig.GetChainID() // FromChainID
+	swapInfo.ToChainID = toChainID                    // ToChainID
+	swapInfo.ERC20SwapInfo.Token = token              // Token
+	swapInfo.ERC20SwapInfo.TokenID = tokenCfg.TokenID // TokenID
+	return nil
+}
+
+func parseEventData(event *SidecarEvent, count int) ([]string, error) {
+	if len(event.Data) != count {
+		return nil, fmt.Errorf("event data count mismatch, have %v want %v", len(event.Data), count)
+	}
+	fields := make([]string, count)
+	for i, data := range event.Data {
+		if err := json.Unmarshal(data, &fields[i]); err != nil {
+			// numbers may be rendered as json number
+			fields[i] = string(data)
+		}
+	}
+	return fields, nil
+}
+
+func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
+	if swapInfo.FromChainID.Cmp(swapInfo.ToChainID) == 0 {
+		return tokens.ErrSameFromAndToChainID
+	}
+	erc20SwapInfo := swapInfo.ERC20SwapInfo
+	fromTokenCfg := b.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil || erc20SwapInfo.TokenID == "" {
+		return tokens.ErrMissTokenConfig
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, swapInfo.ToChainID.String())
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", swapInfo.ToChainID, "txid", swapInfo.Hash)
+		return tokens.ErrMissTokenConfig
+	}
+	toBridge := router.GetBridgeByChainID(swapInfo.ToChainID.String())
+	if toBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	toTokenCfg := toBridge.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		log.Warn("get token config failed", "chainID", swapInfo.ToChainID, "token", multichainToken)
+		return tokens.ErrMissTokenConfig
+	}
+	if !tokens.CheckTokenSwapValue(swapInfo, fromTokenCfg.Decimals, toTokenCfg.Decimals) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !toBridge.IsValidAddress(swapInfo.Bind) {
+		log.Warn("wrong bind address in swapout", "txid", swapInfo.Hash, "bind", swapInfo.Bind)
+		return tokens.ErrWrongBindAddress
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package substrate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const tSwapoutExtrinsic = `{
+	"method": {"pallet": "anyswap", "method": "swapOut"},
+	"success": true,
+	"events": [
+		{"method": {"pallet": "balances", "method": "Withdraw"}, "data": ["5Grw", "1000"]},
+		{"method": {"pallet": "anyswap", "method": "SwapOut"},
+		 "data": ["0x6e6174697665", "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", "0x62696e6431", "1000000000000", "2000"]},
+		{"method": {"pallet": "Anyswap", "method": "swapout"},
+		 "data": ["0x6e6174697665", "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY", "0x62696e6432", 2500, 3000]},
+		{"method": {"pallet": "anyswap", "method": "SwapIn"}, "data": ["0x6e6174697665"]},
+		{"method": {"pallet": "system", "method": "ExtrinsicSuccess"}, "data": []}
+	]
+}`
+
+func newTestSwapoutExtrinsic(t *testing.T, events ...string) *SidecarExtrinsic {
+	var ext SidecarExtrinsic
+	if err := json.Unmarshal([]byte(tSwapoutExtrinsic), &ext); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) > 0 {
+		ext.Events = nil
+		for _, event := range events {
+			var e SidecarEvent
+			if err := json.Unmarshal([]byte(event), &e); err != nil {
+				t.Fatal(err)
+			}
+			ext.Events = append(ext.Events, &e)
+		}
+	}
+	return &ext
+}
+
+func TestParseSwapoutEvent(t *testing.T) {
+	b := newTestBridge(tFromChain, "", "router")
+
+	testCases := []struct {
+		name      string
+		ext       *SidecarExtrinsic
+		logIndex  int
+		wantErr   error
+		wantBind  string
+		wantValue string
+		wantTo    string
+	}{
+		{"first swapout event", newTestSwapoutExtrinsic(t), 0, nil, "bind1", "1000000000000", "2000"},
+		{"second swapout event with json numbers", newTestSwapoutExtrinsic(t), 1, nil, "bind2", "2500", "3000"},
+		{"log index out of range", newTestSwapoutExtrinsic(t), 2, tokens.ErrLogIndexOutOfRange, "", "", ""},
+		{"no swapout event", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "anyswap", "method": "SwapIn"}, "data": ["0x6e6174697665"]}`),
+			0, tokens.ErrSwapoutLogNotFound, "", "", ""},
+		{"swapout event of other pallet", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "other", "method": "SwapOut"}, "data": ["0x6e6174697665", "a", "0x62", "1", "2000"]}`),
+			0, tokens.ErrSwapoutLogNotFound, "", "", ""},
+		{"wrong data count", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "anyswap", "method": "SwapOut"}, "data": ["0x6e6174697665", "a", "0x62", "1"]}`),
+			0, tokens.ErrTxWithWrongTopics, "", "", ""},
+		{"wrong amount", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "anyswap", "method": "SwapOut"}, "data": ["0x6e6174697665", "a", "0x62", "0x10", "2000"]}`),
+			0, tokens.ErrTxWithWrongValue, "", "", ""},
+		{"wrong to chainID", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "anyswap", "method": "SwapOut"}, "data": ["0x6e6174697665", "a", "0x62", "1", "bsc"]}`),
+			0, tokens.ErrTxWithWrongTopics, "", "", ""},
+		{"unknown token", newTestSwapoutExtrinsic(t,
+			`{"method": {"pallet": "anyswap", "method": "SwapOut"}, "data": ["0x6f74686572", "a", "0x62", "1", "2000"]}`),
+			0, tokens.ErrMissTokenConfig, "", "", ""},
+	}
+	for _, tc := range testCases {
+		swapInfo := &tokens.SwapTxInfo{
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{}},
+			LogIndex: tc.logIndex,
+		}
+		err := b.parseSwapoutEvent(swapInfo, tc.ext)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%v: want error %v, have %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if swapInfo.Bind != tc.wantBind || swapInfo.Value.String() != tc.wantValue ||
+			swapInfo.ToChainID.String() != tc.wantTo || swapInfo.FromChainID.String() != tFromChain ||
+			swapInfo.From != "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY" || swapInfo.To != "router" ||
+			swapInfo.ERC20SwapInfo.Token != tToken || swapInfo.ERC20SwapInfo.TokenID != tTokenID {
+			t.Errorf("%v: wrong swap info %+v", tc.name, swapInfo)
+		}
+	}
+}
+
+func TestParseExtrinsicID(t *testing.T) {
+	testCases := []struct {
+		extrinsicID string
+		wantErr     bool
+		wantBlock   uint64
+		wantIndex   int
+	}{
+		{"150-2", false, 150, 2},
+		{"0-0", false, 0, 0},
+		{"150", true, 0, 0},
+		{"150-2-1", true, 0, 0},
+		{"abc-2", true, 0, 0},
+		{"150--1", true, 0, 0},
+		{"0x1111111111111111111111111111111111111111111111111111111111111111", true, 0, 0},
+	}
+	for _, tc := range testCases {
+		loc, err := ParseExtrinsicID(tc.extrinsicID)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parse extrinsic id '%v': want error %v, have %v", tc.extrinsicID, tc.wantErr, err)
+			continue
+		}
+		if err == nil && (loc.BlockNumber != tc.wantBlock || loc.Index != tc.wantIndex) {
+			t.Errorf("parse extrinsic id '%v': have %+v", tc.extrinsicID, loc)
+		}
+	}
+}
+
+func TestGetBlockTimestamp(t *testing.T) {
+	var block SidecarBlock
+	err := json.Unmarshal([]byte(`{
+		"number": "150",
+		"extrinsics": [
+			{"method": {"pallet": "parachainSystem", "method": "setValidationData"}, "args": {}},
+			{"method": {"pallet": "timestamp", "method": "set"}, "args": {"now": "1600000000123"}}
+		]
+	}`), &block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := block.GetTimestamp(); have != 1600000000 {
+		t.Errorf("get block timestamp, want 1600000000, have %v", have)
+	}
+	block.Extrinsics = block.Extrinsics[:1]
+	if have := block.GetTimestamp(); have != 0 {
+		t.Errorf("get block timestamp without inherent, want 0, have %v", have)
+	}
+}
@@ -21,6 +21,13 @@ type Bridge struct {
 	*ethpro.Bridge
 }
 
+// register test module (replaces the product `eth` bridge)
+func init() {
+	tokens.RegisterBridge("eth", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	})
+}
+
 // NewCrossChainBridge new bridge instance
 func NewCrossChainBridge() *Bridge {
 	return &Bridge{
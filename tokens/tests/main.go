@@ -13,9 +13,11 @@ import (
 	rpcserver "github.com/anyswap/CrossChain-Router/v3/rpc/server"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/tests/config"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/tests/eth"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/tests/template"
 	"github.com/urfave/cli/v2"
+
+	// register test modules
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/tests/eth"
+	_ "github.com/anyswap/CrossChain-Router/v3/tokens/tests/template"
 )
 
 var (
@@ -74,13 +76,14 @@ func initRouter() {
 	params.EnableSignWithPrivateKey()
 	params.SetDebugMode(testCfg.IsDebugMode)
 
-	switch testCfg.Module {
-	case "eth":
-		bridge = eth.NewCrossChainBridge()
-	case "template":
-		bridge = template.NewCrossChainBridge()
-	default:
-		log.Fatalf("unimplemented test module '%v'", testCfg.Module)
+	module := testCfg.Module
+	if module == "" {
+		module = tokens.GetBridgeFamily(testCfg.Chain.GetChainID(), testCfg.Chain.BlockChain)
+	}
+	var err error
+	bridge, err = tokens.NewBridgeOfFamily(module)
+	if err != nil {
+		log.Fatalf("unimplemented test module '%v'", module)
 	}
 
 	bridge.SetGatewayConfig(testCfg.Gateway)
@@ -194,6 +197,7 @@ func process(opts map[string]string) error {
 		From:        testCfg.SignerAddress,
 		OriginFrom:  swapInfo.From,
 		OriginTxTo:  swapInfo.TxTo,
+		OriginTime:  swapInfo.Timestamp,
 		OriginValue: swapInfo.Value,
 	}
 	rawTx, err := bridge.BuildRawTransaction(args)
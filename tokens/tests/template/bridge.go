@@ -14,6 +14,13 @@ type Bridge struct {
 	*tokens.CrossChainBridgeBase
 }
 
+// register test module
+func init() {
+	tokens.RegisterBridge("template", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	})
+}
+
 // NewCrossChainBridge new bridge instance
 func NewCrossChainBridge() *Bridge {
 	return &Bridge{
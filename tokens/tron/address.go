@@ -0,0 +1,73 @@
+package tron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// AddressPrefix prefix byte of tron address
+const AddressPrefix = byte(0x41)
+
+// AddressLength length of tron address bytes (with prefix)
+const AddressLength = 1 + common.AddressLength
+
+// ToEthAddress convert tron address to eth address.
+// supports base58check format (eg. 'T...'), hex format with '41' prefix,
+// and hex format of eth address (with '0x' prefix).
+func ToEthAddress(address string) (common.Address, error) {
+	if strings.HasPrefix(address, "T") {
+		decoded, version, err := base58.CheckDecode(address)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("wrong base58 address '%v': %w", address, err)
+		}
+		if version != AddressPrefix || len(decoded) != common.AddressLength {
+			return common.Address{}, fmt.Errorf("wrong base58 address '%v'", address)
+		}
+		return common.BytesToAddress(decoded), nil
+	}
+	hexAddr := address
+	if len(hexAddr) == 2*AddressLength && strings.HasPrefix(hexAddr, "41") {
+		hexAddr = "0x" + hexAddr[2:]
+	}
+	if !strings.HasPrefix(hexAddr, "0x") || !common.IsHexAddress(hexAddr) {
+		return common.Address{}, fmt.Errorf("wrong hex address '%v'", address)
+	}
+	return common.HexToAddress(hexAddr), nil
+}
+
+// ToEthHexAddress convert tron address to lower case hex of eth address
+func ToEthHexAddress(address string) (string, error) {
+	ethAddr, err := ToEthAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return ethAddr.LowerHex(), nil
+}
+
+// ToTronAddress convert eth address to tron address bytes (with prefix)
+func ToTronAddress(ethAddr common.Address) []byte {
+	return append([]byte{AddressPrefix}, ethAddr.Bytes()...)
+}
+
+// ToBase58Address convert eth address to base58check format tron address
+func ToBase58Address(ethAddr common.Address) string {
+	return base58.CheckEncode(ethAddr.Bytes(), AddressPrefix)
+}
+
+// IsValidAddress check address
+func (b *Bridge) IsValidAddress(address string) bool {
+	ethAddr, err := ToEthAddress(address)
+	return err == nil && ethAddr != (common.Address{})
+}
+
+// PublicKeyToAddress public key to base58 address
+func (b *Bridge) PublicKeyToAddress(pubKey string) (string, error) {
+	ethAddr, err := b.evm.PublicKeyToAddress(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return ToBase58Address(common.HexToAddress(ethAddr)), nil
+}
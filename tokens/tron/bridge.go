@@ -0,0 +1,173 @@
+// Package tron implements the bridge interfaces for tron chain.
+//
+// Tron executes the same router contract as evm chains, so log decoding and
+// swap verifying are delegated to the eth bridge through the eth compatible
+// json rpc of tron node (the 'APIAddress' of gateway config, eg.
+// 'https://api.trongrid.io/jsonrpc'). Addresses in router and token configs
+// are in hex format of eth address, bind addresses can be base58check or hex.
+// Transactions are built and broadcasted through tron http api with fee
+// limit estimated from energy usage and energy price.
+package tron
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+)
+
+var (
+	// ensure Bridge impl tokens.CrossChainBridge
+	_ tokens.IBridge = &Bridge{}
+
+	defTxExpiration    = int64(600)        // seconds
+	defFeeLimitPercent = uint64(30)        // percent
+	defMaxFeeLimit     = int64(1000000000) // 1000 TRX
+)
+
+func init() {
+	tokens.RegisterBridge("tron", func() tokens.IBridge {
+		return NewCrossChainBridge()
+	})
+}
+
+// Bridge tron bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	evm *eth.Bridge
+
+	HTTPAPIs        []string
+	TxExpiration    int64
+	FeeLimitPercent uint64
+	MaxFeeLimit     int64
+}
+
+// NewCrossChainBridge new bridge
+func NewCrossChainBridge() *Bridge {
+	evm := eth.NewCrossChainBridge()
+	return &Bridge{
+		CrossChainBridgeBase: evm.CrossChainBridgeBase,
+		evm:                  evm,
+		TxExpiration:         defTxExpiration,
+		FeeLimitPercent:      defFeeLimitPercent,
+		MaxFeeLimit:          defMaxFeeLimit,
+	}
+}
+
+// InitAfterConfig init variables (ie. extra members) after loading config
+func (b *Bridge) InitAfterConfig() {
+	isReload := router.IsReloading
+	logErrFunc := log.GetLogFuncOr(isReload, log.Error, log.Fatal)
+	err := b.evm.InitExtraCustoms()
+	if err == nil {
+		err = b.initExtraCustoms()
+	}
+	if err != nil {
+		logErrFunc("init extra custons failed",
+			"chainID", b.ChainConfig.ChainID,
+			"blockChain", b.ChainConfig.BlockChain,
+			"err", err)
+		return
+	}
+	log.Info("init tron bridge success", "chainID", b.ChainConfig.ChainID,
+		"httpAPIs", b.HTTPAPIs, "txExpiration", b.TxExpiration,
+		"feeLimitPercent", b.FeeLimitPercent, "maxFeeLimit", b.MaxFeeLimit)
+}
+
+func (b *Bridge) initExtraCustoms() error {
+	chainID := b.ChainConfig.ChainID
+
+	b.HTTPAPIs = nil
+	if httpAPIs := params.GetCustom(chainID, "httpAPI"); httpAPIs != "" {
+		for _, api := range strings.Split(httpAPIs, ",") {
+			if api = strings.TrimSpace(api); api != "" {
+				b.HTTPAPIs = append(b.HTTPAPIs, strings.TrimSuffix(api, "/"))
+			}
+		}
+	} else {
+		for _, api := range b.GatewayConfig.APIAddress {
+			b.HTTPAPIs = append(b.HTTPAPIs, strings.TrimSuffix(strings.TrimSuffix(api, "/"), "/jsonrpc"))
+		}
+	}
+
+	if expiration := params.GetCustom(chainID, "txExpiration"); expiration != "" {
+		value, err := common.GetUint64FromStr(expiration)
+		if err != nil || value == 0 {
+			return fmt.Errorf("wrong custom txExpiration '%v'", expiration)
+		}
+		b.TxExpiration = int64(value)
+	}
+
+	if percent := params.GetCustom(chainID, "feeLimitPercent"); percent != "" {
+		value, err := common.GetUint64FromStr(percent)
+		if err != nil {
+			return fmt.Errorf("wrong custom feeLimitPercent '%v'", percent)
+		}
+		b.FeeLimitPercent = value
+	}
+
+	if maxFeeLimit := params.GetCustom(chainID, "maxFeeLimit"); maxFeeLimit != "" {
+		value, err := common.GetUint64FromStr(maxFeeLimit)
+		if err != nil || value == 0 || value > math.MaxInt64 {
+			return fmt.Errorf("wrong custom maxFeeLimit '%v'", maxFeeLimit)
+		}
+		b.MaxFeeLimit = int64(value)
+	}
+	return nil
+}
+
+// InitRouterInfo init router info (router contract is in hex format)
+func (b *Bridge) InitRouterInfo(routerContract string) error {
+	return b.evm.InitRouterInfo(routerContract)
+}
+
+// RegisterSwap api
+func (b *Bridge) RegisterSwap(txHash string, args *tokens.RegisterArgs) ([]*tokens.SwapTxInfo, []error) {
+	return b.evm.RegisterSwap(txHash, args)
+}
+
+// VerifyTransaction api
+func (b *Bridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	return b.evm.VerifyTransaction(txHash, args)
+}
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.evm.GetTransaction(txHash)
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	return b.evm.GetTransactionStatus(txHash)
+}
+
+// GetLatestBlockNumber impl
+func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
+	return b.evm.GetLatestBlockNumber()
+}
+
+// GetLatestBlockNumberOf impl
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	return b.evm.GetLatestBlockNumberOf(url)
+}
+
+// ScanSwapTxs impl (by json rpc eth_getLogs)
+func (b *Bridge) ScanSwapTxs(fromHeight, toHeight uint64) ([]string, error) {
+	return b.evm.ScanSwapTxs(fromHeight, toHeight)
+}
+
+// GetBalance get balance in sun
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	ethAddr, err := ToEthHexAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	return b.evm.GetBalance(ethAddr)
+}
@@ -0,0 +1,443 @@
+package tron
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/common/hexutil"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/eth/abicoder"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	tTokenID   = "USDT"
+	tFromChain = "1500"
+	tToChain   = "1600"
+	tSwapID    = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	tBlockHash = "0x0000000002faf0800123456789abcdef0123456789abcdef0123456789abcdef"
+	tTxID      = "0x2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func TestAddress(t *testing.T) {
+	b := NewCrossChainBridge()
+	base58Addr := "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	wantHex := "0xa614f803b6fd780986a42c78ec9c7f77e6ded13c"
+	for _, address := range []string{base58Addr, "41" + wantHex[2:], wantHex} {
+		if have, err := ToEthHexAddress(address); err != nil || have != wantHex {
+			t.Errorf("convert address %v failed. have %v want %v err %v", address, have, wantHex, err)
+		}
+		if !b.IsValidAddress(address) {
+			t.Errorf("address %v should be valid", address)
+		}
+	}
+	if have := ToBase58Address(common.HexToAddress(wantHex)); have != base58Addr {
+		t.Errorf("convert to base58 address failed. have %v want %v", have, base58Addr)
+	}
+	for _, wrong := range []string{"", wantHex[2:], "42" + wantHex[2:], "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6T"} {
+		if b.IsValidAddress(wrong) {
+			t.Errorf("address '%v' should be invalid", wrong)
+		}
+	}
+
+	ecKey, _ := crypto.GenerateKey()
+	pubKey := common.ToHex(crypto.FromECDSAPub(&ecKey.PublicKey))
+	address, err := b.PublicKeyToAddress(pubKey)
+	if err != nil || address != ToBase58Address(crypto.PubkeyToAddress(ecKey.PublicKey)) {
+		t.Errorf("public key to address failed. have %v err %v", address, err)
+	}
+}
+
+func TestBase58CheckAddress(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		ethAddr := randomAddress()
+		base58Addr := ToBase58Address(ethAddr)
+		if len(base58Addr) != 34 || !strings.HasPrefix(base58Addr, "T") {
+			t.Fatalf("wrong base58 address %v of %v", base58Addr, ethAddr.LowerHex())
+		}
+		have, err := ToEthAddress(base58Addr)
+		if err != nil || have != ethAddr {
+			t.Fatalf("base58 address round trip failed. have %v want %v err %v", have.LowerHex(), ethAddr.LowerHex(), err)
+		}
+		if !bytes.Equal(ToTronAddress(ethAddr), append([]byte{AddressPrefix}, ethAddr.Bytes()...)) {
+			t.Fatalf("wrong tron address bytes %x", ToTronAddress(ethAddr))
+		}
+	}
+
+	ethAddr := randomAddress()
+	base58Addr := ToBase58Address(ethAddr)
+	lastChar := "1"
+	if strings.HasSuffix(base58Addr, lastChar) {
+		lastChar = "2"
+	}
+	testCases := []struct {
+		name    string
+		address string
+	}{
+		{"wrong checksum", base58Addr[:len(base58Addr)-1] + lastChar},
+		{"wrong version", base58.CheckEncode(ethAddr.Bytes(), 0x42)},
+		{"short payload", base58.CheckEncode(ethAddr.Bytes()[1:], AddressPrefix)},
+		{"long payload", base58.CheckEncode(append(ethAddr.Bytes(), 0x01), AddressPrefix)},
+		{"not base58", "T" + strings.Repeat("0", 33)},
+	}
+	for _, tc := range testCases {
+		if _, err := ToEthAddress(tc.address); err == nil {
+			t.Errorf("%v: address %v should be invalid", tc.name, tc.address)
+		}
+	}
+}
+
+func TestTransactionRawData(t *testing.T) {
+	var owner, contract common.Address
+	owner[0], contract[0] = 1, 2
+	tx := &Transaction{
+		RefBlockBytes: []byte{0x01, 0x02},
+		RefBlockHash:  common.FromHex("0x1111111111111111"),
+		Expiration:    1000,
+		Timestamp:     500,
+		FeeLimit:      100,
+		Contract: &TriggerSmartContract{
+			OwnerAddress:    ToTronAddress(owner),
+			ContractAddress: ToTronAddress(contract),
+			Data:            []byte{0xaa},
+		},
+	}
+	triggerHex := "0a15" + hex.EncodeToString(ToTronAddress(owner)) +
+		"1215" + hex.EncodeToString(ToTronAddress(contract)) + "2201aa"
+	paramHex := "0a31" + hex.EncodeToString([]byte(TriggerSmartContractTypeURL)) + "1231" + triggerHex
+	contractHex := "081f1266" + paramHex
+	wantHex := "0a020102" + "22081111111111111111" + "40e807" + "5a6a" + contractHex + "70f403" + "900164"
+	if have := hex.EncodeToString(tx.RawData()); have != wantHex {
+		t.Fatalf("marshal raw data failed.\nhave %v\nwant %v", have, wantHex)
+	}
+}
+
+// stubNode serves eth compatible json-rpc and tron http api
+type stubNode struct {
+	results map[string]interface{}
+	sent    string
+}
+
+func (s *stubNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/wallet/broadcasthex" {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.sent = req["transaction"]
+		_ = json.NewEncoder(w).Encode(&BroadcastResult{Result: true, Code: "SUCCESS", TxID: tTxID[2:]})
+		return
+	}
+	var req struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if result, exist := s.results[req.Method]; exist {
+		resp["result"] = result
+	} else {
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "not found " + req.Method}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func randomAddress() common.Address {
+	ecKey, _ := crypto.GenerateKey()
+	return crypto.PubkeyToAddress(ecKey.PublicKey)
+}
+
+func newTestBridge(chainID, url, routerContract, token string) *Bridge {
+	b := NewCrossChainBridge()
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{url + "/jsonrpc"}})
+	chainCfg := &tokens.ChainConfig{
+		ChainID:        chainID,
+		BlockChain:     "tron",
+		RouterContract: routerContract,
+		Confirmations:  1,
+	}
+	_ = chainCfg.CheckConfig()
+	b.SetChainConfig(chainCfg)
+	b.SetTokenConfig(token, &tokens.TokenConfig{
+		TokenID:         tTokenID,
+		Decimals:        6,
+		ContractAddress: token,
+		RouterContract:  routerContract,
+	})
+	_ = b.initExtraCustoms()
+	return b
+}
+
+// initTestRouter init router and swap configs of a swap from chain 'tFromChain'
+// to tron chain 'tToChain', returns the tron bridge with router contract and token.
+func initTestRouter(url, mpcAddress string) (b *Bridge, routerContract, token string) {
+	routerContract = randomAddress().LowerHex()
+	token = randomAddress().LowerHex()
+
+	srcBridge := newTestBridge(tFromChain, url, routerContract, token)
+	b = newTestBridge(tToChain, url, routerContract, token)
+	router.SetBridge(tFromChain, srcBridge)
+	router.SetBridge(tToChain, b)
+	router.SetMultichainToken(tTokenID, tFromChain, token)
+	router.SetMultichainToken(tTokenID, tToChain, token)
+	router.SetRouterInfo(routerContract, &router.SwapRouterInfo{RouterMPC: mpcAddress})
+
+	tokens.InitRouterSwapType("erc20swap")
+	oneUSDT := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	swapCfg := &tokens.SwapConfig{
+		MaximumSwap:           new(big.Int).Mul(oneUSDT, big.NewInt(100)),
+		MinimumSwap:           new(big.Int).Div(oneUSDT, big.NewInt(10000)),
+		BigValueThreshold:     new(big.Int).Mul(oneUSDT, big.NewInt(10)),
+		SwapFeeRatePerMillion: 1000,
+		MaximumSwapFee:        new(big.Int).Div(oneUSDT, big.NewInt(100)),
+		MinimumSwapFee:        new(big.Int).Div(oneUSDT, big.NewInt(100000)),
+	}
+	swapConfig := new(sync.Map)
+	swapConfig.Store(tFromChain, swapCfg)
+	swapConfig.Store(tToChain, swapCfg)
+	swapConfigs := new(sync.Map)
+	swapConfigs.Store(tTokenID, swapConfig)
+	tokens.SetSwapConfigs(swapConfigs)
+	return b, routerContract, token
+}
+
+func TestBuildAndSignTransaction(t *testing.T) {
+	node := &stubNode{results: map[string]interface{}{
+		"eth_getBlockByNumber": map[string]interface{}{
+			"hash":      tBlockHash,
+			"number":    "0x2faf080",
+			"timestamp": "0x615e4180",
+		},
+		"eth_estimateGas": "0x186a0", // 100000 energy
+		"eth_gasPrice":    "0x1a4",   // 420 sun
+	}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	mpcKey, _ := crypto.GenerateKey()
+	mpcAddress := crypto.PubkeyToAddress(mpcKey.PublicKey).LowerHex()
+	b, _, token := initTestRouter(server.URL, mpcAddress)
+	if len(b.HTTPAPIs) != 1 || b.HTTPAPIs[0] != server.URL {
+		t.Fatalf("wrong default http apis %v", b.HTTPAPIs)
+	}
+	receiver := randomAddress()
+
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			Identifier:  "test",
+			SwapID:      tSwapID,
+			SwapType:    tokens.ERC20SwapType,
+			Bind:        ToBase58Address(receiver),
+			FromChainID: big.NewInt(1500),
+			ToChainID:   big.NewInt(1600),
+			SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+				Token:   token,
+				TokenID: tTokenID,
+			}},
+		},
+		From:        mpcAddress,
+		OriginValue: big.NewInt(1000000),
+	}
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatalf("build tx failed. err %v", err)
+	}
+	tx := rawTx.(*Transaction)
+	extra := args.Extra.TronExtra
+	if args.SwapValue.String() != "999000" || *extra.RefBlockBytes != "0xf080" ||
+		*extra.RefBlockHash != "0x0123456789abcdef" || *extra.Expiration != (1633567104+defTxExpiration)*1000 {
+		t.Fatalf("wrong build args. extra %+v swapValue %v", extra, args.SwapValue)
+	}
+	if *extra.FeeLimit != 100000*420*130/100 {
+		t.Errorf("wrong fee limit %v", *extra.FeeLimit)
+	}
+	wantInput := append(common.CopyBytes(eth.AnySwapInFuncHash), common.HexToHash(tSwapID).Bytes()...)
+	if !strings.HasPrefix(common.ToHex(tx.Contract.Data), common.ToHex(wantInput)) ||
+		!strings.Contains(common.ToHex(tx.Contract.Data), receiver.LowerHex()[2:]) {
+		t.Errorf("wrong contract data %x", tx.Contract.Data)
+	}
+	if err = b.VerifyMsgHash(rawTx, []string{tx.TxHash()}); err != nil {
+		t.Errorf("verify msg hash failed. err %v", err)
+	}
+	if _, err = b.verifyTransactionReceiver(rawTx, args); err != nil {
+		t.Errorf("verify tx receiver failed. err %v", err)
+	}
+
+	signedTx, txHash, err := b.SignTransactionWithPrivateKey(rawTx, common.ToHex(crypto.FromECDSA(mpcKey)))
+	if err != nil {
+		t.Fatalf("sign tx failed. err %v", err)
+	}
+	signature := signedTx.(*Transaction).Signature
+	if txHash != tx.TxHash() || len(signature) != crypto.SignatureLength || signature[64] < 27 {
+		t.Errorf("wrong signed tx. hash %v signature %x", txHash, signature)
+	}
+	otherKey, _ := crypto.GenerateKey()
+	if _, _, err = b.SignTransactionWithPrivateKey(rawTx, common.ToHex(crypto.FromECDSA(otherKey))); err == nil {
+		t.Errorf("sign with key other than owner should fail")
+	}
+	sentHash, err := b.SendTransaction(signedTx)
+	if err != nil || sentHash != tTxID || node.sent == "" {
+		t.Errorf("send tx failed. hash %v err %v", sentHash, err)
+	}
+
+	b.MaxFeeLimit = 1000000
+	args.Input, args.Extra = nil, nil
+	if _, err = b.BuildRawTransaction(args); err != nil || *args.Extra.TronExtra.FeeLimit != b.MaxFeeLimit {
+		t.Errorf("fee limit should be capped by max fee limit. err %v", err)
+	}
+}
+
+func TestEstimateFeeLimit(t *testing.T) {
+	node := &stubNode{results: map[string]interface{}{
+		"eth_gasPrice": "0x1a4", // 420 sun
+	}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	b := newTestBridge(tToChain, server.URL, randomAddress().LowerHex(), randomAddress().LowerHex())
+	input := hexutil.Bytes{0xaa}
+	args := &tokens.BuildTxArgs{
+		From:  randomAddress().LowerHex(),
+		To:    randomAddress().LowerHex(),
+		Value: big.NewInt(0),
+		Input: &input,
+	}
+
+	if _, err := b.estimateFeeLimit(args); !errors.Is(err, tokens.ErrEstimateGasFailed) {
+		t.Errorf("estimate fee limit without energy, have error %v, want %v", err, tokens.ErrEstimateGasFailed)
+	}
+
+	testCases := []struct {
+		energy      string
+		percent     uint64
+		maxFeeLimit int64
+		want        int64
+	}{
+		{"0x186a0", 30, defMaxFeeLimit, 100000 * 420 * 130 / 100},
+		{"0x186a0", 0, defMaxFeeLimit, 100000 * 420},
+		{"0x186a0", 100, defMaxFeeLimit, 100000 * 420 * 2},
+		{"0x186a0", 30, 50000000, 50000000}, // capped by max fee limit
+		{"0x186a0", 30, 100000 * 420 * 130 / 100, 100000 * 420 * 130 / 100},
+		{"0x5208", 30, defMaxFeeLimit, 21000 * 420 * 130 / 100},
+	}
+	for i, tc := range testCases {
+		node.results["eth_estimateGas"] = tc.energy
+		b.FeeLimitPercent = tc.percent
+		b.MaxFeeLimit = tc.maxFeeLimit
+		have, err := b.estimateFeeLimit(args)
+		if err != nil || have != tc.want {
+			t.Errorf("test case %v: wrong fee limit. have %v want %v err %v", i, have, tc.want, err)
+		}
+	}
+}
+
+func TestRegisterAndVerifySwap(t *testing.T) {
+	node := &stubNode{results: map[string]interface{}{
+		"eth_blockNumber": "0x70",
+	}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	mpcAddress := randomAddress().LowerHex()
+	_, routerContract, token := initTestRouter(server.URL, mpcAddress)
+	srcBridge := router.GetBridgeByChainID(tFromChain).(*Bridge)
+
+	sender := common.HexToHash(randomAddress().LowerHex())
+	receiver := randomAddress()
+	amount := big.NewInt(1000000)
+	fromChainID, _ := new(big.Int).SetString(tFromChain, 10)
+	toChainID, _ := new(big.Int).SetString(tToChain, 10)
+	swapoutTopics := func(topic []byte, extra ...common.Hash) []string {
+		topics := []string{common.ToHex(topic), common.HexToHash(token).String(), sender.String()}
+		for _, hash := range extra {
+			topics = append(topics, hash.String())
+		}
+		return topics
+	}
+	otherLog := map[string]interface{}{
+		"address": token,
+		"data":    "0x",
+		"topics":  []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+	}
+	hexBindLog := map[string]interface{}{
+		"address": routerContract,
+		"data":    common.ToHex(abicoder.PackData(amount, fromChainID, toChainID)),
+		"topics":  swapoutTopics(eth.LogAnySwapOutTopic, common.BytesToHash(receiver.Bytes())),
+	}
+	base58BindLog := map[string]interface{}{
+		"address": routerContract,
+		"data":    common.ToHex(abicoder.PackData(ToBase58Address(receiver), amount, fromChainID, toChainID)),
+		"topics":  swapoutTopics(eth.LogAnySwapOut2Topic),
+	}
+	wrongBindLog := map[string]interface{}{
+		"address": routerContract,
+		"data":    common.ToHex(abicoder.PackData("T"+strings.Repeat("1", 33), amount, fromChainID, toChainID)),
+		"topics":  swapoutTopics(eth.LogAnySwapOut2Topic),
+	}
+	node.results["eth_getTransactionReceipt"] = map[string]interface{}{
+		"transactionHash":  tTxID,
+		"transactionIndex": "0x0",
+		"blockNumber":      "0x64",
+		"blockHash":        tBlockHash,
+		"status":           "0x1",
+		"from":             common.BytesToAddress(sender.Bytes()).LowerHex(),
+		"to":               routerContract,
+		"logs":             []interface{}{otherLog, hexBindLog, base58BindLog, wrongBindLog},
+	}
+	node.results["eth_getTransactionByHash"] = map[string]interface{}{
+		"hash":     tTxID,
+		"nonce":    "0x1",
+		"gas":      "0x186a0",
+		"gasPrice": "0x1a4",
+		"value":    "0x0",
+		"from":     common.BytesToAddress(sender.Bytes()).LowerHex(),
+		"to":       routerContract,
+		"input":    "0xedbdf5e2", // anySwapOutUnderlying
+	}
+
+	swapInfos, errs := srcBridge.RegisterSwap(tTxID, &tokens.RegisterArgs{SwapType: tokens.ERC20SwapType})
+	if len(swapInfos) != 3 || len(errs) != 3 {
+		t.Fatalf("register swap should return every swapout log, have %v logs", len(swapInfos))
+	}
+	testCases := []struct {
+		bind    string
+		wantErr error
+	}{
+		{receiver.LowerHex(), nil},
+		{ToBase58Address(receiver), nil},
+		{"T" + strings.Repeat("1", 33), tokens.ErrWrongBindAddress},
+	}
+	for i, tc := range testCases {
+		swapInfo := swapInfos[i]
+		if swapInfo.LogIndex != i+1 || swapInfo.Bind != tc.bind || swapInfo.Value.Cmp(amount) != 0 ||
+			swapInfo.ERC20SwapInfo.TokenID != tTokenID || swapInfo.ToChainID.String() != tToChain {
+			t.Errorf("wrong swap info of log %v: %+v", i+1, swapInfo)
+		}
+		if tc.wantErr == nil && errs[i] != nil || tc.wantErr != nil && !errors.Is(errs[i], tc.wantErr) {
+			t.Errorf("register log %v, have error %v, want %v", i+1, errs[i], tc.wantErr)
+		}
+
+		swapInfo, err := srcBridge.VerifyTransaction(tTxID, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: i + 1})
+		if tc.wantErr == nil && (err != nil || swapInfo.Bind != tc.bind) || tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+			t.Errorf("verify log %v, have error %v, want %v", i+1, err, tc.wantErr)
+		}
+	}
+
+	if _, err := srcBridge.VerifyTransaction(tTxID, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: 4}); !errors.Is(err, tokens.ErrLogIndexOutOfRange) {
+		t.Errorf("verify log out of range, have error %v, want %v", err, tokens.ErrLogIndexOutOfRange)
+	}
+	node.results["eth_blockNumber"] = "0x64"
+	if _, err := srcBridge.VerifyTransaction(tTxID, &tokens.VerifyArgs{SwapType: tokens.ERC20SwapType, LogIndex: 1}); !errors.Is(err, tokens.ErrTxNotStable) {
+		t.Errorf("verify unstable tx, have error %v, want %v", err, tokens.ErrTxNotStable)
+	}
+}
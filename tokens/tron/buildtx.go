@@ -0,0 +1,197 @@
+package tron
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
+		return nil, tokens.ErrToChainIDMismatch
+	}
+	if args.Input != nil {
+		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsEqualIgnoreCase(args.From, routerMPC) {
+		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
+		return nil, tokens.ErrSenderMismatch
+	}
+
+	err = b.buildSwapTxInput(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args)
+}
+
+// buildSwapTxInput build router contract call input by eth bridge
+// (with bind address converted to hex format)
+func (b *Bridge) buildSwapTxInput(args *tokens.BuildTxArgs) error {
+	if args.Extra != nil && args.Extra.FeeGasPrice != nil {
+		return errors.New("fee gas price is not supported")
+	}
+	evmArgs := *args
+	if bind, err := ToEthHexAddress(args.Bind); err == nil {
+		evmArgs.Bind = bind
+	}
+	err := b.evm.BuildSwapTxInput(&evmArgs)
+	if err != nil {
+		return err
+	}
+	args.Input = evmArgs.Input             // input
+	args.To = evmArgs.To                   // to
+	args.SwapValue = evmArgs.SwapValue     // swapValue
+	args.Extra = evmArgs.Extra             // extra (maybe with swap deadline)
+	args.SwapFeeInfo = evmArgs.SwapFeeInfo // charged swap fee
+	return nil
+}
+
+func getOrInitTronExtra(args *tokens.BuildTxArgs) *tokens.TronExtraArgs {
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{TronExtra: &tokens.TronExtraArgs{}}
+	} else if args.Extra.TronExtra == nil {
+		args.Extra.TronExtra = &tokens.TronExtraArgs{}
+	}
+	return args.Extra.TronExtra
+}
+
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (err error) {
+	if args.Value == nil {
+		args.Value = new(big.Int)
+	}
+	extra := getOrInitTronExtra(args)
+	if extra.RefBlockBytes == nil || extra.RefBlockHash == nil || extra.Expiration == nil {
+		err = b.setRefBlock(extra)
+		if err != nil {
+			return err
+		}
+	}
+	if extra.Timestamp == nil {
+		timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+		extra.Timestamp = &timestamp
+	}
+	if extra.FeeLimit == nil {
+		feeLimit, errf := b.estimateFeeLimit(args)
+		if errf != nil {
+			return errf
+		}
+		extra.FeeLimit = &feeLimit
+	}
+	return nil
+}
+
+// setRefBlock set reference block to the latest block
+func (b *Bridge) setRefBlock(extra *tokens.TronExtraArgs) error {
+	block, err := b.GetLatestBlock()
+	if err != nil {
+		return err
+	}
+	var number [8]byte
+	binary.BigEndian.PutUint64(number[:], block.Number.ToInt().Uint64())
+	refBlockBytes := common.ToHex(number[6:8])
+	refBlockHash := common.ToHex(block.Hash[8:16])
+	blockTime := block.Time.ToInt().Int64()
+	if blockTime < 1e12 { // seconds to milliseconds
+		blockTime *= 1000
+	}
+	expiration := blockTime + b.TxExpiration*1000
+
+	extra.RefBlockBytes = &refBlockBytes
+	extra.RefBlockHash = &refBlockHash
+	extra.Expiration = &expiration
+	return nil
+}
+
+// estimateFeeLimit fee limit is the estimated energy multiplies energy price (in sun)
+func (b *Bridge) estimateFeeLimit(args *tokens.BuildTxArgs) (int64, error) {
+	energy, err := b.evm.EstimateGas(args.From, args.To, args.Value, *args.Input)
+	if err != nil {
+		log.Error(fmt.Sprintf("build %s tx estimate energy failed", args.SwapType.String()),
+			"swapID", args.SwapID, "from", args.From, "to", args.To,
+			"value", args.Value, "data", *args.Input, "err", err)
+		return 0, tokens.ErrEstimateGasFailed
+	}
+	energyPrice, err := b.evm.SuggestPrice()
+	if err != nil {
+		return 0, err
+	}
+	feeLimit := new(big.Int).Mul(new(big.Int).SetUint64(energy), energyPrice)
+	feeLimit.Mul(feeLimit, new(big.Int).SetUint64(100+b.FeeLimitPercent))
+	feeLimit.Div(feeLimit, big.NewInt(100))
+	if feeLimit.Cmp(big.NewInt(b.MaxFeeLimit)) > 0 {
+		log.Warn("estimated fee limit exceeds max fee limit", "swapID", args.SwapID,
+			"energy", energy, "energyPrice", energyPrice, "feeLimit", feeLimit, "maxFeeLimit", b.MaxFeeLimit)
+		return b.MaxFeeLimit, nil
+	}
+	return feeLimit.Int64(), nil
+}
+
+func (b *Bridge) buildTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	owner, err := ToEthAddress(args.From)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := ToEthAddress(args.To)
+	if err != nil {
+		return nil, err
+	}
+	if !args.Value.IsInt64() {
+		return nil, fmt.Errorf("wrong call value %v", args.Value)
+	}
+	extra := args.Extra.TronExtra
+	refBlockBytes := common.FromHex(*extra.RefBlockBytes)
+	refBlockHash := common.FromHex(*extra.RefBlockHash)
+	if len(refBlockBytes) != 2 || len(refBlockHash) != 8 {
+		return nil, errors.New("wrong reference block")
+	}
+	if *extra.FeeLimit <= 0 || *extra.FeeLimit > b.MaxFeeLimit {
+		return nil, fmt.Errorf("wrong fee limit %v", *extra.FeeLimit)
+	}
+
+	tx := &Transaction{
+		RefBlockBytes: refBlockBytes,
+		RefBlockHash:  refBlockHash,
+		Expiration:    *extra.Expiration,
+		Timestamp:     *extra.Timestamp,
+		FeeLimit:      *extra.FeeLimit,
+		Contract: &TriggerSmartContract{
+			OwnerAddress:    ToTronAddress(owner),
+			ContractAddress: ToTronAddress(contract),
+			CallValue:       args.Value.Int64(),
+			Data:            *args.Input,
+		},
+	}
+
+	log.Info("build tx success",
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "to", args.To, "bind", args.Bind,
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"feeLimit", *extra.FeeLimit, "expiration", *extra.Expiration,
+		"replaceNum", args.GetReplaceNum())
+
+	return tx, nil
+}
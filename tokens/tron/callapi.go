@@ -0,0 +1,94 @@
+package tron
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/types"
+)
+
+var (
+	wrapRPCQueryError = tokens.WrapRPCQueryError
+
+	errEmptyURLs = errors.New("empty URLs")
+)
+
+// BroadcastResult result of '/wallet/broadcasthex'
+type BroadcastResult struct {
+	Result  bool   `json:"result"`
+	Code    string `json:"code"`
+	TxID    string `json:"txid"`
+	Message string `json:"message"`
+}
+
+// GetMessage get message (hex decoded if possible)
+func (r *BroadcastResult) GetMessage() string {
+	if msg, err := hex.DecodeString(r.Message); err == nil {
+		return string(msg)
+	}
+	return r.Message
+}
+
+func httpPostOf(result interface{}, url, path string, body interface{}, timeout int) error {
+	resp, err := client.HTTPPost(url+path, body, nil, nil, timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(data))
+	}
+	return json.Unmarshal(data, result)
+}
+
+// GetLatestBlock get latest block through eth compatible json rpc
+func (b *Bridge) GetLatestBlock() (*types.RPCBlock, error) {
+	block, err := b.evm.GetBlockByNumber(nil)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash == nil || block.Number == nil || block.Time == nil {
+		return nil, wrapRPCQueryError(nil, "eth_getBlockByNumber", "latest")
+	}
+	return block, nil
+}
+
+// BroadcastHex broadcast raw tx in hex format to all http apis
+func (b *Bridge) BroadcastHex(rawTx []byte) (txHash string, err error) {
+	if len(b.HTTPAPIs) == 0 {
+		return "", errEmptyURLs
+	}
+	req := map[string]string{
+		"transaction": hex.EncodeToString(rawTx),
+	}
+	for _, url := range b.HTTPAPIs {
+		var result BroadcastResult
+		err = httpPostOf(&result, url, "/wallet/broadcasthex", req, b.evm.RPCClientTimeout)
+		if err != nil {
+			continue
+		}
+		if !result.Result {
+			err = fmt.Errorf("broadcast tx failed. code %v, message %v", result.Code, result.GetMessage())
+			continue
+		}
+		txHash = common.ToHex(common.FromHex(result.TxID))
+	}
+	if txHash != "" {
+		return txHash, nil
+	}
+	return "", wrapRPCQueryError(err, "broadcasthex")
+}
@@ -0,0 +1,29 @@
+package tron
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*Transaction)
+	if !ok || tx.Contract == nil || len(tx.Signature) == 0 {
+		log.Printf("signed tx is %+v", signedTx)
+		return "", errors.New("wrong signed transaction type")
+	}
+	rawTx := tx.Marshal()
+	txHash, err = b.BroadcastHex(rawTx)
+	if err != nil {
+		log.Info("SendTransaction failed", "hash", tx.TxHash(), "err", err)
+	} else {
+		log.Info("SendTransaction success", "hash", txHash)
+	}
+	if params.IsDebugMode() {
+		log.Infof("SendTransaction rawtx is %v", hex.EncodeToString(rawTx))
+	}
+	return txHash, err
+}
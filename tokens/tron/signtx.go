@@ -0,0 +1,165 @@
+package tron
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+)
+
+// VerifyMsgHash verify msg hash (the txid is signed)
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Contract == nil {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	sigHash := tx.TxHash()
+	if !strings.EqualFold(sigHash, msgHashes[0]) {
+		log.Trace("message hash mismatch", "want", msgHashes[0], "have", sigHash)
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+func (b *Bridge) verifyTransactionReceiver(rawTx interface{}, args *tokens.BuildTxArgs) (*Transaction, error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Contract == nil {
+		return nil, errors.New("[sign] wrong raw tx param")
+	}
+	checkReceiver, err := router.GetTokenRouterContract(args.GetTokenID(), b.ChainConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	checkContract, err := ToEthAddress(checkReceiver)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tx.Contract.ContractAddress, ToTronAddress(checkContract)) {
+		return nil, fmt.Errorf("[sign] tx receiver mismatch. have %v want %v", common.ToHex(tx.Contract.ContractAddress), checkReceiver)
+	}
+	if tx.FeeLimit > b.MaxFeeLimit {
+		return nil, fmt.Errorf("[sign] tx fee limit %v exceeds max fee limit %v", tx.FeeLimit, b.MaxFeeLimit)
+	}
+	return tx, nil
+}
+
+// MPCSignTransaction mpc sign raw tx
+func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, err := b.verifyTransactionReceiver(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if params.SignWithPrivateKey() {
+		priKey := params.GetSignerPrivateKey(b.ChainConfig.ChainID)
+		return b.SignTransactionWithPrivateKey(rawTx, priKey)
+	}
+
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	signer, err := ToEthAddress(args.From)
+	if err != nil {
+		return nil, "", err
+	}
+
+	msgHash := tx.TxHash()
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	msgContext := string(jsondata)
+
+	txid := args.SwapID
+	logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
+	log.Info(logPrefix+"start", "txid", txid, "msghash", msgHash)
+	keyID, rsvs, err := mpc.DoSignOneEC(mpcPubkey, msgHash, msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			mpc.FailSignRequest(keyID, err)
+		}
+	}()
+	log.Info(logPrefix+"finished", "keyID", keyID, "txid", txid, "msghash", msgHash)
+
+	if len(rsvs) != 1 {
+		log.Warn("get sign status require one rsv but return many",
+			"rsvs", len(rsvs), "keyID", keyID, "txid", txid)
+		return nil, "", errors.New("get sign status require one rsv but return many")
+	}
+
+	rsv := rsvs[0]
+	log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
+	signature := common.FromHex(rsv)
+	if len(signature) != crypto.SignatureLength {
+		log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
+		return nil, "", errors.New("wrong signature length")
+	}
+
+	signedTx, err := signTxWithSignature(tx, signature, signer)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash = signedTx.TxHash()
+	log.Info(logPrefix+"success", "keyID", keyID, "txid", txid, "txhash", txHash)
+	return signedTx, txHash, nil
+}
+
+// SignTransactionWithPrivateKey sign tx with private key (use for testing)
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, priKey string) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok || tx.Contract == nil {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	privKey, err := crypto.ToECDSA(common.FromHex(priKey))
+	if err != nil {
+		return nil, "", err
+	}
+	signature, err := crypto.Sign(tx.TxID(), privKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("sign tx failed, %w", err)
+	}
+	signedTx, err := signTxWithSignature(tx, signature, crypto.PubkeyToAddress(privKey.PublicKey))
+	if err != nil {
+		return nil, "", err
+	}
+	return signedTx, signedTx.TxHash(), nil
+}
+
+// signTxWithSignature verify signer and attach signature (v is 27 or 28)
+func signTxWithSignature(tx *Transaction, signature []byte, signerAddr common.Address) (*Transaction, error) {
+	owner := tx.Contract.OwnerAddress
+	if !bytes.Equal(owner, ToTronAddress(signerAddr)) {
+		return nil, fmt.Errorf("tx owner %v is not the signer %v", common.ToHex(owner), signerAddr.LowerHex())
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	vPos := crypto.SignatureLength - 1
+	if sig[vPos] >= 27 {
+		sig[vPos] -= 27
+	}
+	txID := tx.TxID()
+	for i := 0; i < 2; i++ {
+		pubKey, err := crypto.SigToPub(txID, sig)
+		if err == nil && crypto.PubkeyToAddress(*pubKey) == signerAddr {
+			sig[vPos] += 27
+			signedTx := *tx
+			signedTx.Signature = sig
+			return &signedTx, nil
+		}
+		sig[vPos] ^= 0x1 // v can only be 0 or 1
+	}
+	return nil, errors.New("wrong sender address")
+}
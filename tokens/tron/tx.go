@@ -0,0 +1,82 @@
+package tron
+
+import (
+	"crypto/sha256"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tools/protobuf"
+)
+
+// contract type of trigger smart contract
+const (
+	TriggerSmartContractType    = 31
+	TriggerSmartContractTypeURL = "type.googleapis.com/protocol.TriggerSmartContract"
+)
+
+// TriggerSmartContract call smart contract
+type TriggerSmartContract struct {
+	OwnerAddress    []byte
+	ContractAddress []byte
+	CallValue       int64
+	Data            []byte
+}
+
+// Marshal marshal trigger smart contract
+func (c *TriggerSmartContract) Marshal() []byte {
+	p := &protobuf.Buffer{}
+	p.Bytes(1, c.OwnerAddress)
+	p.Bytes(2, c.ContractAddress)
+	p.Uint64(3, uint64(c.CallValue))
+	p.Bytes(4, c.Data)
+	return p.Result()
+}
+
+// Transaction tron transaction (only one trigger smart contract is supported)
+type Transaction struct {
+	RefBlockBytes []byte
+	RefBlockHash  []byte
+	Expiration    int64 // milliseconds
+	Timestamp     int64 // milliseconds
+	FeeLimit      int64 // sun
+	Contract      *TriggerSmartContract
+	Signature     []byte // 65 bytes r || s || v
+}
+
+// RawData marshal raw data of transaction
+func (tx *Transaction) RawData() []byte {
+	param := &protobuf.Buffer{}
+	param.String(1, TriggerSmartContractTypeURL)
+	param.Bytes(2, tx.Contract.Marshal())
+
+	contract := &protobuf.Buffer{}
+	contract.Uint64(1, TriggerSmartContractType)
+	contract.Message(2, param.Result())
+
+	p := &protobuf.Buffer{}
+	p.Bytes(1, tx.RefBlockBytes)
+	p.Bytes(4, tx.RefBlockHash)
+	p.Uint64(8, uint64(tx.Expiration))
+	p.Message(11, contract.Result())
+	p.Uint64(14, uint64(tx.Timestamp))
+	p.Uint64(18, uint64(tx.FeeLimit))
+	return p.Result()
+}
+
+// TxID sha256 hash of raw data
+func (tx *Transaction) TxID() []byte {
+	hash := sha256.Sum256(tx.RawData())
+	return hash[:]
+}
+
+// TxHash tx hash (lower case hex with '0x' prefix)
+func (tx *Transaction) TxHash() string {
+	return common.ToHex(tx.TxID())
+}
+
+// Marshal marshal transaction
+func (tx *Transaction) Marshal() []byte {
+	p := &protobuf.Buffer{}
+	p.Message(1, tx.RawData())
+	p.Bytes(2, tx.Signature)
+	return p.Result()
+}
@@ -191,20 +191,29 @@ type BuildTxArgs struct {
 	To          string         `json:"to,omitempty"`
 	OriginFrom  string         `json:"originFrom,omitempty"`
 	OriginTxTo  string         `json:"originTxTo,omitempty"`
+	OriginTime  uint64         `json:"originTime,omitempty"` // timestamp of source tx
 	OriginValue *big.Int       `json:"originValue,omitempty"`
 	SwapValue   *big.Int       `json:"swapValue,omitempty"`
 	Value       *big.Int       `json:"value,omitempty"`
 	Memo        string         `json:"memo,omitempty"`
 	Input       *hexutil.Bytes `json:"input,omitempty"`
 	Extra       *AllExtras     `json:"extra,omitempty"`
+
+	SwapFeeInfo *SwapFeeInfo `json:"-"` // charged swap fee (set when build tx)
 }
 
 // AllExtras struct
 type AllExtras struct {
-	EthExtra   *EthExtraArgs `json:"ethExtra,omitempty"`
-	ReplaceNum uint64        `json:"replaceNum,omitempty"`
-	Sequence   *uint64       `json:"sequence,omitempty"`
-	Fee        *string       `json:"fee,omitempty"`
+	EthExtra       *EthExtraArgs       `json:"ethExtra,omitempty"`
+	BtcExtra       *BtcExtraArgs       `json:"btcExtra,omitempty"`
+	SubstrateExtra *SubstrateExtraArgs `json:"substrateExtra,omitempty"`
+	SolanaExtra    *SolanaExtraArgs    `json:"solanaExtra,omitempty"`
+	TronExtra      *TronExtraArgs      `json:"tronExtra,omitempty"`
+	ReplaceNum     uint64              `json:"replaceNum,omitempty"`
+	Sequence       *uint64             `json:"sequence,omitempty"`
+	Fee            *string             `json:"fee,omitempty"`
+	PayAnyToken    bool                `json:"payAnyToken,omitempty"`
+	FeeGasPrice    *big.Int            `json:"feeGasPrice,omitempty"` // dest gas price used by swap fee
 }
 
 // EthExtraArgs struct
@@ -217,6 +226,42 @@ type EthExtraArgs struct {
 	Deadline  int64    `json:"deadline,omitempty"`
 }
 
+// BtcOutPoint struct
+type BtcOutPoint struct {
+	Hash  string `json:"hash"`
+	Index uint32 `json:"index"`
+}
+
+// BtcExtraArgs struct
+type BtcExtraArgs struct {
+	RelayFeePerKb     *int64         `json:"relayFeePerKb,omitempty"`
+	ChangeAddress     *string        `json:"changeAddress,omitempty"`
+	PreviousOutPoints []*BtcOutPoint `json:"previousOutPoints,omitempty"`
+}
+
+// SubstrateExtraArgs struct
+type SubstrateExtraArgs struct {
+	BlockNumber *uint64 `json:"blockNumber,omitempty"`
+	BlockHash   *string `json:"blockHash,omitempty"`
+	EraPeriod   *uint64 `json:"eraPeriod,omitempty"`
+	SpecVersion *uint32 `json:"specVersion,omitempty"`
+	TxVersion   *uint32 `json:"txVersion,omitempty"`
+}
+
+// SolanaExtraArgs struct
+type SolanaExtraArgs struct {
+	RecentBlockhash *string `json:"recentBlockhash,omitempty"`
+}
+
+// TronExtraArgs struct
+type TronExtraArgs struct {
+	RefBlockBytes *string `json:"refBlockBytes,omitempty"`
+	RefBlockHash  *string `json:"refBlockHash,omitempty"`
+	Expiration    *int64  `json:"expiration,omitempty"`
+	Timestamp     *int64  `json:"timestamp,omitempty"`
+	FeeLimit      *int64  `json:"feeLimit,omitempty"`
+}
+
 // GetReplaceNum get rplace swap count
 func (args *BuildTxArgs) GetReplaceNum() uint64 {
 	if args.Extra != nil {
@@ -225,6 +270,11 @@ func (args *BuildTxArgs) GetReplaceNum() uint64 {
 	return 0
 }
 
+// IsPayAnyToken is paying anyToken instead of underlying
+func (args *BuildTxArgs) IsPayAnyToken() bool {
+	return args.Extra != nil && args.Extra.PayAnyToken
+}
+
 // GetExtraArgs get extra args
 func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 	return &BuildTxArgs{
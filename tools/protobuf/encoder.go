@@ -0,0 +1,64 @@
+// Package protobuf implements a minimal protobuf encoder to marshal
+// transactions of chains which use protobuf encoding.
+package protobuf
+
+import (
+	"encoding/binary"
+)
+
+// protobuf wire types
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// Buffer minimal protobuf encoder (fields must be appended in field number order)
+type Buffer struct {
+	buf []byte
+}
+
+func (p *Buffer) appendVarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	p.buf = append(p.buf, tmp[:n]...)
+}
+
+func (p *Buffer) appendKey(field, wireType int) {
+	p.appendVarint(uint64(field<<3 | wireType))
+}
+
+// Uint64 append varint field (default value is omitted)
+func (p *Buffer) Uint64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	p.appendKey(field, wireVarint)
+	p.appendVarint(v)
+}
+
+// Bytes append length delimited field (default value is omitted)
+func (p *Buffer) Bytes(field int, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	p.appendKey(field, wireBytes)
+	p.appendVarint(uint64(len(data)))
+	p.buf = append(p.buf, data...)
+}
+
+// String append string field (default value is omitted)
+func (p *Buffer) String(field int, s string) {
+	p.Bytes(field, []byte(s))
+}
+
+// Message append embedded message field (always present)
+func (p *Buffer) Message(field int, data []byte) {
+	p.appendKey(field, wireBytes)
+	p.appendVarint(uint64(len(data)))
+	p.buf = append(p.buf, data...)
+}
+
+// Result get encoded bytes
+func (p *Buffer) Result() []byte {
+	return p.buf
+}
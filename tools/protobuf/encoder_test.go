@@ -0,0 +1,18 @@
+package protobuf
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	p := &Buffer{}
+	p.Uint64(1, 300)
+	p.Uint64(2, 0)
+	p.Message(3, nil)
+	p.String(4, "")
+	p.Bytes(5, []byte{0xab})
+	if have := hex.EncodeToString(p.Result()); have != "08ac021a002a01ab" {
+		t.Errorf("marshal proto failed. have %v", have)
+	}
+}
@@ -79,10 +79,12 @@ func (tx *RPCTransaction) GetAccountNonce() uint64 {
 
 // RPCLog struct
 type RPCLog struct {
-	Address *common.Address `json:"address"`
-	Topics  []common.Hash   `json:"topics"`
-	Data    *hexutil.Bytes  `json:"data"`
-	Removed *bool           `json:"removed"`
+	Address     *common.Address `json:"address"`
+	Topics      []common.Hash   `json:"topics"`
+	Data        *hexutil.Bytes  `json:"data"`
+	Removed     *bool           `json:"removed"`
+	TxHash      *common.Hash    `json:"transactionHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
 }
 
 // RPCTxReceipt struct
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
@@ -19,6 +20,7 @@ import (
 const (
 	acceptAgree    = "AGREE"
 	acceptDisagree = "DISAGREE"
+	acceptDiscard  = "DISCARD" // only used in decision audit log
 )
 
 var (
@@ -39,6 +41,8 @@ var (
 	errIdentifierMismatch = errors.New("cross chain bridge identifier mismatch")
 	errInitiatorMismatch  = errors.New("initiator mismatch")
 	errWrongMsgContext    = errors.New("wrong msg context")
+
+	errPayAnyTokenTooEarly = errors.New("pay anyToken before liquidity timeout")
 )
 
 // StartAcceptSignJob accept job
@@ -55,6 +59,7 @@ func StartAcceptSignJob() {
 	}
 
 	openLeveldb()
+	initAcceptPolicy()
 
 	go startAcceptProducer()
 
@@ -75,6 +80,7 @@ func startAcceptProducer() {
 			continue
 		}
 		i++
+		start := time.Now()
 		if i%7 == 0 {
 			logWorker("accept", "getCurNodeSignInfo", "count", len(signInfo))
 		}
@@ -93,6 +99,7 @@ func startAcceptProducer() {
 			logWorker("accept", "dispatch accept sign info", "keyID", keyID)
 			acceptInfoCh <- info // produce
 		}
+		metrics.ObserveJobLoop("accept", "", start)
 		time.Sleep(waitInterval)
 	}
 }
@@ -149,6 +156,10 @@ func processAcceptInfo(info *mpc.SignInfoData) {
 	}()
 
 	args, err := verifySignInfo(info)
+	var policyCtx *AcceptPolicyContext
+	if err == nil {
+		policyCtx, err = acceptPolicy.check(keyID, args)
+	}
 
 	ctx := []interface{}{
 		"keyID", keyID,
@@ -189,6 +200,7 @@ func processAcceptInfo(info *mpc.SignInfoData) {
 		if isPendingInvalidAccept {
 			ctx = append(ctx, "err", err)
 			logWorker("accept", "discard sign", ctx...)
+			addAcceptDecision(info, args, acceptDiscard, err, nil)
 			isProcessed = true
 			return
 		}
@@ -208,15 +220,18 @@ func processAcceptInfo(info *mpc.SignInfoData) {
 		ctx = append(ctx, "disgreeReason", disgreeReason)
 	}
 	ctx = append(ctx, "result", agreeResult)
+	metrics.AddAcceptSign(agreeResult)
 
-	res, err := mpc.DoAcceptSign(keyID, agreeResult, info.MsgHash, aggreeMsgContext)
-	if err != nil {
+	res, acceptErr := mpc.DoAcceptSign(keyID, agreeResult, info.MsgHash, aggreeMsgContext)
+	if acceptErr != nil {
 		ctx = append(ctx, "rpcResult", res)
-		logWorkerError("accept", "accept sign job failed", err, ctx...)
+		logWorkerError("accept", "accept sign job failed", acceptErr, ctx...)
 	} else {
 		logWorker("accept", "accept sign job finish", ctx...)
 		isProcessed = true
 	}
+	acceptPolicy.done(policyCtx, acceptErr == nil && agreeResult == acceptAgree)
+	addAcceptDecision(info, args, agreeResult, err, acceptErr)
 }
 
 func verifySignInfo(signInfo *mpc.SignInfoData) (*tokens.BuildTxArgs, error) {
@@ -242,8 +257,11 @@ func verifySignInfo(signInfo *mpc.SignInfoData) (*tokens.BuildTxArgs, error) {
 			return &args, err
 		}
 	}
-	err = rebuildAndVerifyMsgHash(signInfo.Key, msgHash, &args)
-	return &args, err
+	verifiedArgs, err := rebuildAndVerifyMsgHash(signInfo.Key, msgHash, &args)
+	if err != nil {
+		return &args, err
+	}
+	return verifiedArgs, nil
 }
 
 func getBridges(fromChainID, toChainID string) (srcBridge, dstBridge tokens.IBridge, err error) {
@@ -255,13 +273,14 @@ func getBridges(fromChainID, toChainID string) (srcBridge, dstBridge tokens.IBri
 	return
 }
 
-func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) (err error) {
+// rebuildAndVerifyMsgHash returns the rebuilt args from verified swap info
+func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) (buildTxArgs *tokens.BuildTxArgs, err error) {
 	if !args.SwapType.IsValidType() {
-		return fmt.Errorf("unknown router swap type %d", args.SwapType)
+		return nil, fmt.Errorf("unknown router swap type %d", args.SwapType)
 	}
 	srcBridge, dstBridge, err := getBridges(args.FromChainID.String(), args.ToChainID.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx := []interface{}{
@@ -285,16 +304,23 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	swapInfo, err := srcBridge.VerifyTransaction(txid, verifyArgs)
 	if err != nil {
 		logWorkerError("accept", "verifySignInfo failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	if !strings.EqualFold(args.Bind, swapInfo.Bind) {
-		return fmt.Errorf("bind mismatch: '%v' != '%v'", args.Bind, swapInfo.Bind)
+		return nil, fmt.Errorf("bind mismatch: '%v' != '%v'", args.Bind, swapInfo.Bind)
 	}
 	if args.ToChainID.Cmp(swapInfo.ToChainID) != 0 {
-		return fmt.Errorf("toChainID mismatch: '%v' != '%v'", args.ToChainID, swapInfo.ToChainID)
+		return nil, fmt.Errorf("toChainID mismatch: '%v' != '%v'", args.ToChainID, swapInfo.ToChainID)
+	}
+	if args.IsPayAnyToken() {
+		err = checkPayAnyTokenTimeout(swapInfo.Timestamp)
+		if err != nil {
+			logWorkerError("accept", "check pay anyToken failed", err, append(ctx, "txtime", swapInfo.Timestamp)...)
+			return nil, err
+		}
 	}
 
-	buildTxArgs := &tokens.BuildTxArgs{
+	buildTxArgs = &tokens.BuildTxArgs{
 		SwapArgs: tokens.SwapArgs{
 			SwapInfo:    swapInfo.SwapInfo,
 			Identifier:  params.GetIdentifier(),
@@ -309,23 +335,35 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		From:        args.From,
 		OriginFrom:  swapInfo.From,
 		OriginTxTo:  swapInfo.TxTo,
+		OriginTime:  swapInfo.Timestamp,
 		OriginValue: swapInfo.Value,
 		Extra:       args.Extra,
 	}
 	rawTx, err := dstBridge.BuildRawTransaction(buildTxArgs)
 	if err != nil {
 		logWorkerError("accept", "build raw tx failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	err = dstBridge.VerifyMsgHash(rawTx, msgHash)
 	if err != nil {
 		logWorkerError("accept", "verify message hash failed", err, ctx...)
-		return err
+		return nil, err
 	}
 	logWorker("accept", "verify message hash success", ctx...)
 	if lvldbHandle != nil && args.GetTxNonce() > 0 { // only for eth like chain
 		go saveAcceptRecord(dstBridge, keyID, buildTxArgs, rawTx, ctx)
 	}
+	return buildTxArgs, nil
+}
+
+// checkPayAnyTokenTimeout server pays anyToken only if the swap is queued
+// for lacking liquidity longer than the timeout. the swap is queued after
+// its source tx, so the source tx time is a lower bound of the queued time.
+func checkPayAnyTokenTimeout(txTime uint64) error {
+	timeout := params.GetOraclePayAnyTokenTimeout()
+	if timeout <= 0 || txTime == 0 || int64(txTime)+timeout > time.Now().Unix() {
+		return errPayAnyTokenTooEarly
+	}
 	return nil
 }
 
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+func TestCheckPayAnyTokenTimeout(t *testing.T) {
+	routerConfig := params.GetRouterConfig()
+	oldOracle := routerConfig.Oracle
+	defer func() { routerConfig.Oracle = oldOracle }()
+
+	nowTime := uint64(time.Now().Unix())
+	testCases := []struct {
+		timeout int64
+		txTime  uint64
+		wantErr bool
+	}{
+		{0, nowTime - 100000, true}, // never agree if not configed
+		{3600, 0, true},             // unknown tx time
+		{3600, nowTime - 60, true},
+		{3600, nowTime - 3590, true},
+		{3600, nowTime - 3610, false},
+	}
+	for _, tc := range testCases {
+		routerConfig.Oracle = &params.RouterOracleConfig{PayAnyTokenTimeout: tc.timeout}
+		err := checkPayAnyTokenTimeout(tc.txTime)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("timeout %v tx age %v: want error %v, have %v", tc.timeout, int64(nowTime)-int64(tc.txTime), tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	acceptDecisionPrefix      = "acceptdecision:"
+	acceptDecisionTxPrefix    = "acceptdecisiontx:"  // index by txid
+	acceptDecisionKeyIDPrefix = "acceptdecisionkey:" // index by keyID
+
+	defaultAcceptDecisionsLimit = 100
+	maxAcceptDecisionsLimit     = 1000
+)
+
+var (
+	errAcceptDBNotOpened = errors.New("accept database is not opened")
+	errStopIterate       = errors.New("stop iterate")
+)
+
+// AcceptDecision audit log of oracle decision on mpc sign info
+type AcceptDecision struct {
+	KeyID       string
+	Enode       string // self enode of this oracle
+	Initiator   string
+	Args        *tokens.BuildTxArgs `json:",omitempty"` // decoded msg context (verified args if passed verification)
+	MsgHash     []string
+	Decision    string // AGREE, DISAGREE or DISCARD
+	Rule        string `json:",omitempty"` // accept policy rule which rejects the sign
+	Error       string `json:",omitempty"` // verify error or disagree reason
+	AcceptError string `json:",omitempty"` // error of answering the accept rpc
+	Timestamp   int64
+}
+
+// AcceptDecisionFilter filter of querying accept decisions (empty field means no filter)
+type AcceptDecisionFilter struct {
+	KeyID       string
+	TxID        string
+	FromChainID string
+	LogIndex    *int
+	Decision    string
+	StartTime   int64 // unix seconds
+	EndTime     int64 // unix seconds
+}
+
+// AcceptDecisionsResult result of querying accept decisions
+type AcceptDecisionsResult struct {
+	Decisions  []*AcceptDecision
+	NextCursor string `json:",omitempty"`
+}
+
+func (f *AcceptDecisionFilter) match(d *AcceptDecision) bool {
+	if f.StartTime > 0 && d.Timestamp < f.StartTime {
+		return false
+	}
+	if f.Decision != "" && !strings.EqualFold(f.Decision, d.Decision) {
+		return false
+	}
+	if f.FromChainID == "" && f.LogIndex == nil {
+		return true
+	}
+	if d.Args == nil {
+		return false
+	}
+	if f.FromChainID != "" && (d.Args.FromChainID == nil || d.Args.FromChainID.String() != f.FromChainID) {
+		return false
+	}
+	if f.LogIndex != nil && d.Args.LogIndex != *f.LogIndex {
+		return false
+	}
+	return true
+}
+
+// addAcceptDecision record processed sign info, sign infos of other bridges and pending ones are not recorded.
+func addAcceptDecision(info *mpc.SignInfoData, args *tokens.BuildTxArgs, decision string, verifyErr, acceptErr error) {
+	if lvldbHandle == nil {
+		return
+	}
+	timestamp := time.Now()
+	d := &AcceptDecision{
+		KeyID:     info.Key,
+		Enode:     mpc.GetSelfEnode(),
+		Initiator: info.Account,
+		Args:      args,
+		MsgHash:   info.MsgHash,
+		Decision:  decision,
+		Timestamp: timestamp.Unix(),
+	}
+	if verifyErr != nil {
+		d.Error = verifyErr.Error()
+		var policyErr *AcceptPolicyError
+		if errors.As(verifyErr, &policyErr) {
+			d.Rule = policyErr.Rule
+		}
+	}
+	if acceptErr != nil {
+		d.AcceptError = acceptErr.Error()
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		logWorkerError("accept", "marshal accept decision failed", err, "keyID", info.Key)
+		return
+	}
+
+	suffix := fmt.Sprintf("%020d:%s", timestamp.UnixNano(), strings.ToLower(info.Key))
+	batch := lvldbHandle.NewBatch()
+	_ = batch.Put([]byte(acceptDecisionPrefix+suffix), data)
+	_ = batch.Put([]byte(acceptDecisionKeyIDPrefix+strings.ToLower(info.Key)+":"+suffix), nil)
+	if args != nil && args.SwapID != "" {
+		_ = batch.Put([]byte(acceptDecisionTxPrefix+strings.ToLower(args.SwapID)+":"+suffix), nil)
+	}
+	if err = batch.Write(); err != nil {
+		logWorkerError("accept", "save accept decision failed", err, "keyID", info.Key, "decision", decision)
+	}
+}
+
+// iterateAcceptDecisions iterate matched decisions in time order, starting after cursor
+func iterateAcceptDecisions(filter *AcceptDecisionFilter, cursor string, fn func(cursor string, d *AcceptDecision) error) error {
+	if lvldbHandle == nil {
+		return errAcceptDBNotOpened
+	}
+	var prefix string
+	isIndex := true
+	switch {
+	case filter.KeyID != "":
+		prefix = acceptDecisionKeyIDPrefix + strings.ToLower(filter.KeyID) + ":"
+	case filter.TxID != "":
+		prefix = acceptDecisionTxPrefix + strings.ToLower(filter.TxID) + ":"
+	default:
+		prefix = acceptDecisionPrefix
+		isIndex = false
+	}
+	start := cursor
+	if start == "" && filter.StartTime > 0 {
+		start = fmt.Sprintf("%020d", filter.StartTime*int64(time.Second))
+	}
+
+	iter := lvldbHandle.NewIterator([]byte(prefix), []byte(start))
+	defer iter.Release()
+	for iter.Next() {
+		suffix := string(iter.Key()[len(prefix):])
+		if suffix == cursor {
+			continue
+		}
+		data := iter.Value()
+		if isIndex {
+			var err error
+			data, err = lvldbHandle.Get([]byte(acceptDecisionPrefix + suffix))
+			if err != nil {
+				continue
+			}
+		}
+		var d AcceptDecision
+		if err := json.Unmarshal(data, &d); err != nil {
+			continue
+		}
+		if filter.EndTime > 0 && d.Timestamp > filter.EndTime {
+			break
+		}
+		if !filter.match(&d) {
+			continue
+		}
+		if err := fn(suffix, &d); err != nil {
+			if errors.Is(err, errStopIterate) {
+				return nil
+			}
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// FindAcceptDecisions find accept decisions with filter (paginated by cursor)
+func FindAcceptDecisions(filter *AcceptDecisionFilter, cursor string, limit int) (*AcceptDecisionsResult, error) {
+	if limit <= 0 {
+		limit = defaultAcceptDecisionsLimit
+	} else if limit > maxAcceptDecisionsLimit {
+		limit = maxAcceptDecisionsLimit
+	}
+	result := &AcceptDecisionsResult{Decisions: make([]*AcceptDecision, 0, 20)}
+	err := iterateAcceptDecisions(filter, cursor, func(cursor string, d *AcceptDecision) error {
+		result.Decisions = append(result.Decisions, d)
+		if len(result.Decisions) >= limit {
+			result.NextCursor = cursor
+			return errStopIterate
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportAcceptDecisions call fn with all matched accept decisions in time order
+func ExportAcceptDecisions(ctx context.Context, filter *AcceptDecisionFilter, fn func(d *AcceptDecision) error) error {
+	return iterateAcceptDecisions(filter, "", func(_ string, d *AcceptDecision) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(d)
+	})
+}
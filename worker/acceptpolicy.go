@@ -0,0 +1,439 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	dailyCapWindow       = int64(86400) // seconds
+	dailyCapRecordPrefix = "acceptpolicy:dailycap:"
+)
+
+var (
+	acceptPolicy *acceptPolicyEngine
+
+	acceptPolicyRuleCreators = map[string]AcceptPolicyRuleCreator{
+		"dailycap":    newDailyCapRule,
+		"confirm":     newConfirmRule,
+		"maxgasprice": newMaxGasPriceRule,
+		"timewindow":  newTimeWindowRule,
+	}
+)
+
+// AcceptPolicyContext context of sign info which passed verification
+type AcceptPolicyContext struct {
+	KeyID   string
+	SwapKey string              // same for all the signs of one swap (eg. retry, replace, reswap)
+	Args    *tokens.BuildTxArgs // verified args
+	TokenID string
+	Value   *big.Int // erc20 swap value in 18 decimals, nil for other swaps
+}
+
+// AcceptPolicyRule rule of oracle accept policy
+type AcceptPolicyRule interface {
+	Name() string
+	Check(ctx *AcceptPolicyContext) error
+}
+
+// AcceptPolicyReserver is implemented by rules which reserve quota in checking,
+// the reservation is committed if the sign is agreed, otherwise it's released.
+type AcceptPolicyReserver interface {
+	Commit(ctx *AcceptPolicyContext)
+	Release(ctx *AcceptPolicyContext)
+}
+
+// AcceptPolicyRuleCreator create accept policy rule from config
+type AcceptPolicyRuleCreator func(cfg *params.AcceptPolicyConfig) (AcceptPolicyRule, error)
+
+// RegisterAcceptPolicyRule register accept policy rule (should be called before starting accept job)
+func RegisterAcceptPolicyRule(name string, creator AcceptPolicyRuleCreator) {
+	acceptPolicyRuleCreators[strings.ToLower(name)] = creator
+}
+
+// AcceptPolicyError rejection of accept policy rule
+type AcceptPolicyError struct {
+	Rule string
+	Err  error
+}
+
+// Error impl error interface
+func (e *AcceptPolicyError) Error() string {
+	return fmt.Sprintf("accept policy rule '%v' rejects: %v", e.Rule, e.Err)
+}
+
+// Unwrap unwrap error
+func (e *AcceptPolicyError) Unwrap() error {
+	return e.Err
+}
+
+type acceptPolicyEngine struct {
+	rules []AcceptPolicyRule
+}
+
+func initAcceptPolicy() {
+	cfg := params.GetAcceptPolicyConfig()
+	if cfg == nil {
+		return
+	}
+	engine := &acceptPolicyEngine{}
+	for _, name := range cfg.Rules {
+		creator, exist := acceptPolicyRuleCreators[strings.ToLower(name)]
+		if !exist {
+			log.Fatal("unknown accept policy rule", "rule", name)
+		}
+		rule, err := creator(cfg)
+		if err != nil {
+			log.Fatal("create accept policy rule failed", "rule", name, "err", err)
+		}
+		engine.rules = append(engine.rules, rule)
+	}
+	acceptPolicy = engine
+	logWorker("accept", "init accept policy success", "rules", cfg.Rules)
+}
+
+// check evaluates the rule chain in order and stops at the first rejection
+func (e *acceptPolicyEngine) check(keyID string, args *tokens.BuildTxArgs) (*AcceptPolicyContext, error) {
+	if e == nil {
+		return nil, nil
+	}
+	ctx := newAcceptPolicyContext(keyID, args)
+	for i, rule := range e.rules {
+		err := rule.Check(ctx)
+		if err == nil {
+			continue
+		}
+		logWorkerWarn("accept", "accept policy rejects sign", "rule", rule.Name(), "keyID", keyID,
+			"fromChainID", args.FromChainID, "toChainID", args.ToChainID, "swapID", args.SwapID,
+			"logIndex", args.LogIndex, "tokenID", ctx.TokenID, "value", ctx.Value, "err", err)
+		e.release(ctx, e.rules[:i])
+		return nil, &AcceptPolicyError{Rule: rule.Name(), Err: err}
+	}
+	return ctx, nil
+}
+
+// done commits reservations if sign is agreed, otherwise releases them
+func (e *acceptPolicyEngine) done(ctx *AcceptPolicyContext, agreed bool) {
+	if e == nil || ctx == nil {
+		return
+	}
+	if !agreed {
+		e.release(ctx, e.rules)
+		return
+	}
+	for _, rule := range e.rules {
+		if reserver, ok := rule.(AcceptPolicyReserver); ok {
+			reserver.Commit(ctx)
+		}
+	}
+}
+
+func (e *acceptPolicyEngine) release(ctx *AcceptPolicyContext, rules []AcceptPolicyRule) {
+	for _, rule := range rules {
+		if reserver, ok := rule.(AcceptPolicyReserver); ok {
+			reserver.Release(ctx)
+		}
+	}
+}
+
+func newAcceptPolicyContext(keyID string, args *tokens.BuildTxArgs) *AcceptPolicyContext {
+	ctx := &AcceptPolicyContext{
+		KeyID:   keyID,
+		SwapKey: mongodb.GetRouterSwapKey(args.FromChainID.String(), args.SwapID, args.LogIndex),
+		Args:    args,
+		TokenID: args.GetTokenID(),
+	}
+	if args.SwapType != tokens.ERC20SwapType || args.ERC20SwapInfo == nil || args.OriginValue == nil {
+		return ctx
+	}
+	srcBridge := router.GetBridgeByChainID(args.FromChainID.String())
+	if srcBridge == nil {
+		return ctx
+	}
+	tokenCfg := srcBridge.GetTokenConfig(args.ERC20SwapInfo.Token)
+	if tokenCfg == nil {
+		return ctx
+	}
+	ctx.Value = tokens.ConvertTokenValue(args.OriginValue, tokenCfg.Decimals, 18)
+	return ctx
+}
+
+func parseTokenAmounts(amounts map[string]string) map[string]*big.Int {
+	result := make(map[string]*big.Int, len(amounts))
+	for tokenID, amount := range amounts {
+		result[strings.ToLower(tokenID)] = tokens.ToBits(amount, 18)
+	}
+	return result
+}
+
+// ----------------------------- daily cap rule -------------------------------------
+
+type dailyCapUsage struct {
+	Value     *big.Int
+	Timestamp int64
+	Committed bool `json:"-"`
+}
+
+// dailyCapRule refuse swaps exceeding daily approval caps of token
+type dailyCapRule struct {
+	caps map[string]*big.Int // key is lower tokenID
+
+	lock   sync.Mutex
+	usages map[string]map[string]*dailyCapUsage // tokenID -> swap key -> usage
+}
+
+func newDailyCapRule(cfg *params.AcceptPolicyConfig) (AcceptPolicyRule, error) {
+	if len(cfg.DailyCaps) == 0 {
+		return nil, errors.New("empty 'DailyCaps'")
+	}
+	rule := &dailyCapRule{
+		caps:   parseTokenAmounts(cfg.DailyCaps),
+		usages: make(map[string]map[string]*dailyCapUsage),
+	}
+	rule.loadUsages()
+	return rule, nil
+}
+
+func (r *dailyCapRule) Name() string {
+	return "dailycap"
+}
+
+func (r *dailyCapRule) Check(ctx *AcceptPolicyContext) error {
+	tokenID := strings.ToLower(ctx.TokenID)
+	limit, exist := r.caps[tokenID]
+	if !exist || ctx.Value == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	usages := r.usages[tokenID]
+	if usages == nil {
+		usages = make(map[string]*dailyCapUsage)
+		r.usages[tokenID] = usages
+	}
+	if usage := usages[ctx.SwapKey]; usage != nil && usage.Committed {
+		return nil // already agreed (re-sign of the same swap is not counted again)
+	}
+	septime := now() - dailyCapWindow
+	used := big.NewInt(0)
+	for swapKey, usage := range usages {
+		if usage.Timestamp < septime {
+			r.deleteUsage(tokenID, swapKey)
+			continue
+		}
+		if swapKey != ctx.SwapKey {
+			used.Add(used, usage.Value)
+		}
+	}
+	if new(big.Int).Add(used, ctx.Value).Cmp(limit) > 0 {
+		return fmt.Errorf("exceed daily cap of token %v, used %v value %v cap %v", ctx.TokenID, used, ctx.Value, limit)
+	}
+	usages[ctx.SwapKey] = &dailyCapUsage{Value: ctx.Value, Timestamp: now()}
+	return nil
+}
+
+func (r *dailyCapRule) Commit(ctx *AcceptPolicyContext) {
+	tokenID := strings.ToLower(ctx.TokenID)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	usage := r.usages[tokenID][ctx.SwapKey]
+	if usage == nil || usage.Committed {
+		return
+	}
+	usage.Committed = true
+	if lvldbHandle == nil {
+		return
+	}
+	data, _ := json.Marshal(usage)
+	if err := lvldbHandle.Put([]byte(dailyCapRecordPrefix+tokenID+":"+ctx.SwapKey), data); err != nil {
+		logWorkerError("accept", "save daily cap usage failed", err, "tokenID", ctx.TokenID, "swapKey", ctx.SwapKey, "keyID", ctx.KeyID)
+	}
+}
+
+func (r *dailyCapRule) Release(ctx *AcceptPolicyContext) {
+	tokenID := strings.ToLower(ctx.TokenID)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if usage := r.usages[tokenID][ctx.SwapKey]; usage != nil && !usage.Committed {
+		delete(r.usages[tokenID], ctx.SwapKey)
+	}
+}
+
+func (r *dailyCapRule) deleteUsage(tokenID, swapKey string) {
+	delete(r.usages[tokenID], swapKey)
+	if lvldbHandle != nil {
+		_ = lvldbHandle.Delete([]byte(dailyCapRecordPrefix + tokenID + ":" + swapKey))
+	}
+}
+
+// loadUsages load committed usages from accept database
+func (r *dailyCapRule) loadUsages() {
+	if lvldbHandle == nil {
+		return
+	}
+	prefix := []byte(dailyCapRecordPrefix)
+	iter := lvldbHandle.NewIterator(prefix, nil)
+	for iter.Next() {
+		parts := strings.SplitN(string(iter.Key()[len(prefix):]), ":", 2)
+		var usage dailyCapUsage
+		if len(parts) != 2 || json.Unmarshal(iter.Value(), &usage) != nil || usage.Value == nil {
+			continue
+		}
+		usage.Committed = true
+		tokenID, swapKey := parts[0], parts[1]
+		if r.usages[tokenID] == nil {
+			r.usages[tokenID] = make(map[string]*dailyCapUsage)
+		}
+		r.usages[tokenID][swapKey] = &usage
+	}
+	iter.Release()
+}
+
+// ----------------------------- confirm rule -------------------------------------
+
+// confirmRule refuse swaps above threshold without confirmation by independent gateways of source chain
+type confirmRule struct {
+	thresholds map[string]*big.Int // key is lower tokenID
+	gateways   map[string][]string // key is source chainID
+}
+
+func newConfirmRule(cfg *params.AcceptPolicyConfig) (AcceptPolicyRule, error) {
+	if len(cfg.ConfirmThresholds) == 0 {
+		return nil, errors.New("empty 'ConfirmThresholds'")
+	}
+	return &confirmRule{
+		thresholds: parseTokenAmounts(cfg.ConfirmThresholds),
+		gateways:   cfg.ConfirmGateways,
+	}, nil
+}
+
+func (r *confirmRule) Name() string {
+	return "confirm"
+}
+
+func (r *confirmRule) Check(ctx *AcceptPolicyContext) error {
+	threshold, exist := r.thresholds[strings.ToLower(ctx.TokenID)]
+	if !exist || ctx.Value == nil || ctx.Value.Cmp(threshold) <= 0 {
+		return nil
+	}
+	fromChainID := ctx.Args.FromChainID.String()
+	urls := r.gateways[fromChainID]
+	if len(urls) == 0 {
+		return fmt.Errorf("no confirm gateways of chain %v", fromChainID)
+	}
+	srcBridge := router.GetBridgeByChainID(fromChainID)
+	confirmer, ok := srcBridge.(interface {
+		ConfirmTransactionByGateways(txHash string, urls []string) error
+	})
+	if !ok {
+		return fmt.Errorf("chain %v does not support confirming by gateways", fromChainID)
+	}
+	return confirmer.ConfirmTransactionByGateways(ctx.Args.SwapID, urls)
+}
+
+// ----------------------------- max gas price rule -------------------------------------
+
+// maxGasPriceRule refuse swaps with too high gas price on dest chain
+type maxGasPriceRule struct {
+	maxGasPrices map[string]*big.Int // key is dest chainID
+}
+
+func newMaxGasPriceRule(cfg *params.AcceptPolicyConfig) (AcceptPolicyRule, error) {
+	if len(cfg.MaxGasPrices) == 0 {
+		return nil, errors.New("empty 'MaxGasPrices'")
+	}
+	rule := &maxGasPriceRule{maxGasPrices: make(map[string]*big.Int, len(cfg.MaxGasPrices))}
+	for chainID, value := range cfg.MaxGasPrices {
+		bi, err := common.GetBigIntFromStr(value)
+		if err != nil {
+			return nil, fmt.Errorf("wrong max gas price of chain %v", chainID)
+		}
+		rule.maxGasPrices[chainID] = bi
+	}
+	return rule, nil
+}
+
+func (r *maxGasPriceRule) Name() string {
+	return "maxgasprice"
+}
+
+func (r *maxGasPriceRule) Check(ctx *AcceptPolicyContext) error {
+	maxGasPrice, exist := r.maxGasPrices[ctx.Args.ToChainID.String()]
+	if !exist {
+		return nil
+	}
+	extra := ctx.Args.Extra
+	if extra == nil || extra.EthExtra == nil {
+		return nil
+	}
+	gasPrice := extra.EthExtra.GasPrice
+	if gasPrice == nil {
+		gasPrice = extra.EthExtra.GasFeeCap
+	}
+	if gasPrice != nil && gasPrice.Cmp(maxGasPrice) > 0 {
+		return fmt.Errorf("gas price %v exceeds max gas price %v", gasPrice, maxGasPrice)
+	}
+	return nil
+}
+
+// ----------------------------- time window rule -------------------------------------
+
+// timeWindowRule refuse swaps out of allowed UTC time windows
+type timeWindowRule struct {
+	windows [][2]int // minutes of day
+}
+
+func newTimeWindowRule(cfg *params.AcceptPolicyConfig) (AcceptPolicyRule, error) {
+	if len(cfg.AllowedTimeWindows) == 0 {
+		return nil, errors.New("empty 'AllowedTimeWindows'")
+	}
+	rule := &timeWindowRule{}
+	for _, window := range cfg.AllowedTimeWindows {
+		start, end, err := params.ParseTimeWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		rule.windows = append(rule.windows, [2]int{start, end})
+	}
+	return rule, nil
+}
+
+func (r *timeWindowRule) Name() string {
+	return "timewindow"
+}
+
+func (r *timeWindowRule) Check(ctx *AcceptPolicyContext) error {
+	utcNow := time.Now().UTC()
+	if r.isAllowed(utcNow.Hour()*60 + utcNow.Minute()) {
+		return nil
+	}
+	return fmt.Errorf("time %v is out of allowed time windows", utcNow.Format("15:04"))
+}
+
+// isAllowed is minutes of day in any of the allowed windows
+func (r *timeWindowRule) isAllowed(minutes int) bool {
+	for _, w := range r.windows {
+		start, end := w[0], w[1]
+		if start < end && minutes >= start && minutes < end {
+			return true
+		}
+		if start > end && (minutes >= start || minutes < end) { // cross midnight
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func newTestPolicyContext(keyID, swapID, tokenID, value string) *AcceptPolicyContext {
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      swapID,
+			SwapType:    tokens.ERC20SwapType,
+			FromChainID: big.NewInt(1),
+			ToChainID:   big.NewInt(56),
+		},
+	}
+	ctx := &AcceptPolicyContext{
+		KeyID:   keyID,
+		SwapKey: swapID,
+		Args:    args,
+		TokenID: tokenID,
+	}
+	if value != "" {
+		ctx.Value = tokens.ToBits(value, 18)
+	}
+	return ctx
+}
+
+func TestTimeWindowRule(t *testing.T) {
+	rule, err := newTimeWindowRule(&params.AcceptPolicyConfig{
+		AllowedTimeWindows: []string{"08:00-12:00", "22:30-02:00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeWindow := rule.(*timeWindowRule)
+
+	testCases := []struct {
+		hour, minute int
+		want         bool
+	}{
+		{7, 59, false},
+		{8, 0, true},
+		{11, 59, true},
+		{12, 0, false},
+		{22, 29, false},
+		{22, 30, true},
+		{23, 59, true},
+		{0, 0, true}, // cross midnight
+		{1, 59, true},
+		{2, 0, false},
+	}
+	for _, tc := range testCases {
+		if have := timeWindow.isAllowed(tc.hour*60 + tc.minute); have != tc.want {
+			t.Errorf("time %02d:%02d allowed: want %v, have %v", tc.hour, tc.minute, tc.want, have)
+		}
+	}
+
+	if _, err = newTimeWindowRule(&params.AcceptPolicyConfig{AllowedTimeWindows: []string{"10:00-10:00"}}); err == nil {
+		t.Errorf("empty time window should be rejected")
+	}
+}
+
+func TestDailyCapRule(t *testing.T) {
+	rule, err := newDailyCapRule(&params.AcceptPolicyConfig{
+		DailyCaps: map[string]string{"USDC": "1000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dailyCap := rule.(*dailyCapRule)
+
+	swap1 := newTestPolicyContext("key1", "swap1", "USDC", "600")
+	swap2 := newTestPolicyContext("key2", "swap2", "USDC", "600")
+
+	steps := []struct {
+		name    string
+		do      func() error
+		wantErr bool
+	}{
+		{"reserve swap1", func() error { return dailyCap.Check(swap1) }, false},
+		{"swap2 exceeds reserved", func() error { return dailyCap.Check(swap2) }, true},
+		{"release swap1", func() error { dailyCap.Release(swap1); return nil }, false},
+		{"swap2 after release", func() error { return dailyCap.Check(swap2) }, false},
+		{"commit swap2", func() error { dailyCap.Commit(swap2); return nil }, false},
+		{"swap1 exceeds committed", func() error { return dailyCap.Check(swap1) }, true},
+		{"release committed swap2", func() error { dailyCap.Release(swap2); return nil }, false},
+		{"swap1 still exceeds", func() error { return dailyCap.Check(swap1) }, true},
+		// re-sign of the same swap has a new keyID but the same swap key
+		{"re-sign swap2", func() error { return dailyCap.Check(newTestPolicyContext("key3", "swap2", "USDC", "600")) }, false},
+		{"untracked token", func() error { return dailyCap.Check(newTestPolicyContext("key4", "swap4", "USDT", "5000")) }, false},
+		{"non erc20 value", func() error { return dailyCap.Check(newTestPolicyContext("key5", "swap5", "USDC", "")) }, false},
+		{"small swap", func() error { return dailyCap.Check(newTestPolicyContext("key6", "swap6", "USDC", "400")) }, false},
+		{"cap is reached", func() error { return dailyCap.Check(newTestPolicyContext("key7", "swap7", "USDC", "1")) }, true},
+		{"expire usages", func() error {
+			for _, usage := range dailyCap.usages["usdc"] {
+				usage.Timestamp -= dailyCapWindow + 1
+			}
+			return nil
+		}, false},
+		{"swap1 after expiry", func() error { return dailyCap.Check(swap1) }, false},
+	}
+	for _, step := range steps {
+		if err := step.do(); (err != nil) != step.wantErr {
+			t.Fatalf("%v: want error %v, have %v", step.name, step.wantErr, err)
+		}
+	}
+	if len(dailyCap.usages["usdc"]) != 1 {
+		t.Errorf("expired usages are not deleted, have %v usages", len(dailyCap.usages["usdc"]))
+	}
+}
+
+func TestConfirmRule(t *testing.T) {
+	rule, err := newConfirmRule(&params.AcceptPolicyConfig{
+		ConfirmThresholds: map[string]string{"USDC": "1000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		tokenID string
+		value   string
+		wantErr bool
+	}{
+		{"USDC", "999", false},
+		{"USDC", "1000", false},  // not above threshold
+		{"usdc", "1000.5", true}, // no confirm gateways
+		{"USDT", "100000", false},
+		{"USDC", "", false},
+	}
+	for _, tc := range testCases {
+		err = rule.Check(newTestPolicyContext("key", "swap", tc.tokenID, tc.value))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("confirm %v %v: want error %v, have %v", tc.tokenID, tc.value, tc.wantErr, err)
+		}
+	}
+
+	if _, err = newConfirmRule(&params.AcceptPolicyConfig{}); err == nil {
+		t.Errorf("confirm rule without thresholds should be rejected")
+	}
+}
@@ -0,0 +1,224 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	defaultCircuitBreakerErrorWindow = int64(600) // seconds
+
+	chainBreakers     = make(map[string]*chainBreaker) // key is chainID
+	chainBreakersLock sync.Mutex
+
+	circuitBreakerTrips sync.Map // key is chainID, value is trip reason
+)
+
+// chainBreaker the anomalous events of a chain
+type chainBreaker struct {
+	consecutiveFailedSwaps int
+	sendTxErrorTimes       []int64
+	signDisagreeTimes      []int64
+	lastHeight             uint64
+	lastHeightTime         int64
+}
+
+func getChainBreaker(chainID string) *chainBreaker {
+	cb, exist := chainBreakers[chainID]
+	if !exist {
+		cb = &chainBreaker{lastHeightTime: now()}
+		chainBreakers[chainID] = cb
+	}
+	return cb
+}
+
+func getCircuitBreakerErrorWindow(cfg *params.CircuitBreakerConfig) int64 {
+	if cfg.ErrorWindow > 0 {
+		return cfg.ErrorWindow
+	}
+	return defaultCircuitBreakerErrorWindow
+}
+
+// addEventInWindow append event time and remove the events out of window
+func addEventInWindow(times []int64, timestamp, window int64) []int64 {
+	times = append(times, timestamp)
+	i := 0
+	for i < len(times) && times[i] <= timestamp-window {
+		i++
+	}
+	return times[i:]
+}
+
+// StartCircuitBreakerJob circuit breaker job
+func StartCircuitBreakerJob() {
+	logWorker("breaker", "start circuit breaker job")
+	cfg := params.GetCircuitBreakerConfig()
+	if cfg == nil {
+		logWorker("breaker", "stop circuit breaker job as disabled")
+		return
+	}
+
+	// pause chains tripped before restart until admin unpause them
+	trips, err := mongodb.FindCircuitBreakerTrips()
+	if err != nil {
+		logWorkerError("breaker", "find circuit breaker trips failed", err)
+	}
+	for _, trip := range trips {
+		logWorkerWarn("breaker", "pause chain tripped before", "chainID", trip.Key, "reason", trip.Reason, "timestamp", trip.Timestamp)
+		router.AddPausedChainIDs([]string{trip.Key})
+		circuitBreakerTrips.Store(trip.Key, trip.Reason)
+	}
+
+	if cfg.MaxHeightStallTime <= 0 {
+		return
+	}
+	mongodb.MgoWaitGroup.Add(1)
+	go doCheckHeightStallJob(cfg)
+}
+
+func doCheckHeightStallJob(cfg *params.CircuitBreakerConfig) {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			if utils.IsCleanuping() {
+				return false
+			}
+			checkHeightStall(k.(string), v.(tokens.IBridge), cfg)
+			return true
+		})
+		if utils.IsCleanuping() {
+			logWorker("breaker", "stop circuit breaker job")
+			return
+		}
+		restInJob(restIntervalInCircuitBreakerJob)
+	}
+}
+
+func checkHeightStall(chainID string, bridge tokens.IBridge, cfg *params.CircuitBreakerConfig) {
+	height, err := bridge.GetLatestBlockNumber()
+	if err != nil {
+		logWorkerWarn("breaker", "get latest block number failed", "chainID", chainID, "err", err)
+	}
+
+	chainBreakersLock.Lock()
+	cb := getChainBreaker(chainID)
+	nowTime := now()
+	if err == nil && height > cb.lastHeight {
+		cb.lastHeight = height
+		cb.lastHeightTime = nowTime
+	}
+	stallTime := nowTime - cb.lastHeightTime
+	lastHeight := cb.lastHeight
+	chainBreakersLock.Unlock()
+
+	if stallTime >= cfg.MaxHeightStallTime {
+		tripCircuitBreaker(chainID, fmt.Sprintf("gateway height stalls at %v for %v seconds", lastHeight, stallTime))
+	}
+}
+
+// recordSwapResultStable reset consecutive failed swaps of dest chain
+func recordSwapResultStable(toChainID string) {
+	if params.GetCircuitBreakerConfig() == nil {
+		return
+	}
+	chainBreakersLock.Lock()
+	defer chainBreakersLock.Unlock()
+	getChainBreaker(toChainID).consecutiveFailedSwaps = 0
+}
+
+// recordSwapResultFailed count consecutive failed swaps of dest chain
+func recordSwapResultFailed(toChainID string) {
+	cfg := params.GetCircuitBreakerConfig()
+	if cfg == nil || cfg.MaxConsecutiveFailedSwaps <= 0 {
+		return
+	}
+	chainBreakersLock.Lock()
+	cb := getChainBreaker(toChainID)
+	cb.consecutiveFailedSwaps++
+	count := cb.consecutiveFailedSwaps
+	chainBreakersLock.Unlock()
+
+	if count >= cfg.MaxConsecutiveFailedSwaps {
+		tripCircuitBreaker(toChainID, fmt.Sprintf("%v consecutive failed swaps", count))
+	}
+}
+
+// recordSendTxError count send tx errors of dest chain in window
+func recordSendTxError(toChainID string) {
+	cfg := params.GetCircuitBreakerConfig()
+	if cfg == nil || cfg.MaxSendTxErrors <= 0 {
+		return
+	}
+	window := getCircuitBreakerErrorWindow(cfg)
+	chainBreakersLock.Lock()
+	cb := getChainBreaker(toChainID)
+	cb.sendTxErrorTimes = addEventInWindow(cb.sendTxErrorTimes, now(), window)
+	count := len(cb.sendTxErrorTimes)
+	chainBreakersLock.Unlock()
+
+	if count >= cfg.MaxSendTxErrors {
+		tripCircuitBreaker(toChainID, fmt.Sprintf("%v send tx errors in %v seconds", count, window))
+	}
+}
+
+// recordSignDisagree count mpc sign disagreements of dest chain in window
+func recordSignDisagree(toChainID string) {
+	cfg := params.GetCircuitBreakerConfig()
+	if cfg == nil || cfg.MaxSignDisagrees <= 0 {
+		return
+	}
+	window := getCircuitBreakerErrorWindow(cfg)
+	chainBreakersLock.Lock()
+	cb := getChainBreaker(toChainID)
+	cb.signDisagreeTimes = addEventInWindow(cb.signDisagreeTimes, now(), window)
+	count := len(cb.signDisagreeTimes)
+	chainBreakersLock.Unlock()
+
+	if count >= cfg.MaxSignDisagrees {
+		tripCircuitBreaker(toChainID, fmt.Sprintf("%v mpc sign disagreements in %v seconds", count, window))
+	}
+}
+
+func tripCircuitBreaker(chainID, reason string) {
+	if router.IsChainIDPaused(chainID) {
+		return
+	}
+	logWorkerWarn("breaker", "ALERT: circuit breaker tripped, pause chain", "chainID", chainID, "reason", reason)
+	router.AddPausedChainIDs([]string{chainID})
+	circuitBreakerTrips.Store(chainID, reason)
+	_ = mongodb.AddCircuitBreakerTrip(chainID, reason)
+}
+
+// ResetCircuitBreakers reset circuit breakers of chains (called when admin unpause chains)
+func ResetCircuitBreakers(chainIDs []string) error {
+	chainBreakersLock.Lock()
+	for _, chainID := range chainIDs {
+		delete(chainBreakers, chainID)
+		circuitBreakerTrips.Delete(chainID)
+	}
+	chainBreakersLock.Unlock()
+
+	if params.GetCircuitBreakerConfig() == nil {
+		return nil
+	}
+	return mongodb.DeleteCircuitBreakerTrips(chainIDs)
+}
+
+// GetCircuitBreakerTrips get trip reasons of chains paused by circuit breaker
+func GetCircuitBreakerTrips() map[string]string {
+	var trips map[string]string
+	circuitBreakerTrips.Range(func(k, v interface{}) bool {
+		if trips == nil {
+			trips = make(map[string]string)
+		}
+		trips[k.(string)] = v.(string)
+		return true
+	})
+	return trips
+}
@@ -16,6 +16,37 @@ type MatchTx struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapFee    *mongodb.SwapFee
+}
+
+// AddRouterSwap add registered router swap
+func AddRouterSwap(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus, memo string) (err error) {
+	valueStr := "0"
+	if swapInfo.Value != nil {
+		valueStr = swapInfo.Value.String()
+	}
+	swap := &mongodb.MgoSwap{
+		SwapType:    uint32(swapInfo.SwapType),
+		TxID:        swapInfo.Hash,
+		TxTo:        swapInfo.TxTo,
+		From:        swapInfo.From,
+		Bind:        swapInfo.Bind,
+		Value:       valueStr,
+		LogIndex:    swapInfo.LogIndex,
+		FromChainID: swapInfo.FromChainID.String(),
+		ToChainID:   swapInfo.ToChainID.String(),
+		Status:      status,
+		Timestamp:   now(),
+		Memo:        memo,
+	}
+	swap.SwapInfo = mongodb.ConvertToSwapInfo(&swapInfo.SwapInfo)
+	err = mongodb.AddRouterSwap(swap)
+	if err != nil {
+		logWorkerWarn("add", "add router swap failed", "swap", swap, "err", err)
+	} else {
+		logWorker("add", "add router swap success", "swap", swap)
+	}
+	return err
 }
 
 // AddInitialSwapResult add initial result
@@ -52,6 +83,7 @@ func AddInitialSwapResult(swapInfo *tokens.SwapTxInfo, status mongodb.SwapStatus
 		logWorkerError("add", "addInitialSwapResult failed", err, "chainid", swapInfo.FromChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex)
 	} else {
 		logWorker("add", "addInitialSwapResult success", "chainid", swapInfo.FromChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex)
+		recordOutflowUsage(swapInfo)
 	}
 	return err
 }
@@ -64,6 +96,7 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 	if mtx.SwapHeight == 0 {
 		updates.SwapValue = mtx.SwapValue
 		updates.SwapNonce = mtx.SwapNonce
+		updates.SwapFee = mtx.SwapFee
 		updates.SwapHeight = 0
 		updates.SwapTime = 0
 		if mtx.SwapTx != "" {
@@ -79,7 +112,7 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 			updates.SwapTx = mtx.SwapTx
 		}
 	}
-	err = mongodb.UpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
+	err = dbUpdateRouterSwapResult(fromChainID, txid, logIndex, updates)
 	if err != nil {
 		logWorkerError("update", "updateSwapResult failed", err,
 			"chainid", fromChainID, "txid", txid, "logIndex", logIndex,
@@ -199,10 +232,13 @@ SENDTX_LOOP:
 	}
 
 	if err != nil {
+		recordSendTxError(args.ToChainID.String())
 		logWorkerError("sendtx", "send tx failed", err, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", swapTxNonce, "replaceNum", replaceNum)
 		return txHash, err
 	}
 
+	mongodb.AddSwapNotifyEvent(mongodb.NotifyEventSent, args.FromChainID.String(), args.SwapID, args.LogIndex, mongodb.MatchTxNotStable, txHash, "")
+
 	if params.GetRouterServerConfig().SendTxLoopCount[args.ToChainID.String()] >= 0 {
 		go sendTxLoopUntilSuccess(bridge, txHash, signedTx, args)
 	}
@@ -1,17 +1,32 @@
 // Package worker includes all the tasks and jobs to process router swaps.
 //
 // It contains the following main steps (concurrently):
+//	scan
+//		scan router logs of source chains to register swaps automatically.
 //	verify
-//		verify registered swaps.
+//		verify registered swaps, and hold swaps exceeding the rolling window outflow quotas.
 //	swap
 //		build swaptx, mpc sign the tx, and send the tx to blockchain.
+//	resume
+//		resume in-flight mpc sign requests after restart, the swap is not rebuilt until its sign request is finished.
+//		signed requests which can not be resumed (unsupported bridges) keep blocking the swap until `admin releasesign`.
 //	accept
-//		the `oracle` node do the accept job, agree or disagree the signing after verifying by oralce itself.
+//		the `oracle` node do the accept job, agree or disagree the signing after verifying by oralce itself and checking its accept policy rules, and record the decisions for auditing.
 //	stable
 //		mark swap status to `stabe` status.
 //	replace
 //		replace swap with the same tx nonce value when the sent swaptx is not packed into block because of lack fee or other reasons.
 //	passbigvalue
 //		pass big value swap if the swap value is too large.
+//	reorg
+//		recheck block hash of source and swap txs until finality, and revert swap status when chain reorg happens.
+//	breaker
+//		auto pause chain on anomalous failures (failed swaps, send tx errors, sign disagreements, height stalls) until admin unpause it.
+//	liquidity
+//		queue swaps when the dest anyToken vault lacks underlying, release them first-in first-out, or pay anyToken after timeout (oracles also check the timeout).
+//	webhook
+//		deliver hmac signed swap lifecycle events to webhook urls with retry and backoff, failed and held events also go to alert urls.
+//	metrics
+//		update prometheus metrics of swap counts, swap task channel depth, signer nonce gaps and balances.
 // Most the above jobs is assigned to the `server` node, the `oracle` node mainly do the `accept` job.
 package worker
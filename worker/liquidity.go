@@ -0,0 +1,235 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	errInsufficientLiquidity = errors.New("insufficient underlying liquidity")
+
+	vaultUnderlyings sync.Map // key is chainID:anyToken, value is underlying address
+)
+
+// liquidityBridge bridge which can query the underlying balance of anyToken vault
+type liquidityBridge interface {
+	GetUnderlyingAddress(contractAddr string) (string, error)
+	GetErc20Balance(contract, address string) (*big.Int, error)
+}
+
+// liquidityNeed the underlying liquidity needed by a swap
+type liquidityNeed struct {
+	bridge     liquidityBridge
+	toChainID  string
+	vault      string // anyToken address
+	underlying string
+	amount     *big.Int
+}
+
+func (n *liquidityNeed) key() string {
+	return strings.ToLower(n.toChainID + ":" + n.vault)
+}
+
+// vaultLiquidity the liquidity state of a vault in one round of releasing
+type vaultLiquidity struct {
+	balance *big.Int // remaining balance after released swaps
+	blocked bool     // stop releasing later swaps to keep first-in first-out
+}
+
+// StartLiquidityJob liquidity monitor job
+func StartLiquidityJob() {
+	logWorker("liquidity", "start liquidity monitor job")
+	cfg := params.GetLiquidityMonitorConfig()
+	if cfg == nil || !cfg.Enable {
+		logWorker("liquidity", "stop liquidity monitor job as disabled")
+		return
+	}
+	if !tokens.IsERC20Router() {
+		logWorker("liquidity", "stop liquidity monitor job as non erc20 swap")
+		return
+	}
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doLiquidityJob(cfg)
+}
+
+func doLiquidityJob(cfg *params.LiquidityMonitorConfig) {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		res, err := mongodb.FindRouterSwapsQueuedForLiquidity()
+		if err != nil {
+			logWorkerError("liquidity", "find queued swaps error", err)
+		}
+		if len(res) > 0 {
+			logWorker("liquidity", "find queued swaps to release", "count", len(res))
+		}
+		vaults := make(map[string]*vaultLiquidity)
+		for _, swap := range res {
+			if utils.IsCleanuping() {
+				logWorker("liquidity", "stop liquidity monitor job")
+				return
+			}
+			err = processQueuedSwap(swap, cfg, vaults)
+			switch {
+			case err == nil,
+				errors.Is(err, errInsufficientLiquidity):
+			default:
+				logWorkerError("liquidity", "process queued swap error", err, "chainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
+			}
+		}
+		if utils.IsCleanuping() {
+			logWorker("liquidity", "stop liquidity monitor job")
+			return
+		}
+		restInJob(restIntervalInLiquidityJob)
+	}
+}
+
+func processQueuedSwap(swap *mongodb.MgoSwap, cfg *params.LiquidityMonitorConfig, vaults map[string]*vaultLiquidity) error {
+	if swap.Status != mongodb.TxQueuedForLiquidity {
+		return nil
+	}
+	if cfg.PayAnyTokenTimeout > 0 && swap.InitTime/1000+cfg.PayAnyTokenTimeout <= now() { // init time is milli seconds
+		logWorkerWarn("liquidity", "pay anyToken for long queued swap", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "tokenID", swap.GetTokenID())
+		return mongodb.ReleaseQueuedRouterSwap(swap.FromChainID, swap.TxID, swap.LogIndex, true)
+	}
+
+	need, err := getLiquidityNeed(swap, cfg)
+	if err != nil {
+		return err
+	}
+	if need == nil {
+		// monitor is disabled on dest chain, or swap does not need underlying any more
+		return mongodb.ReleaseQueuedRouterSwap(swap.FromChainID, swap.TxID, swap.LogIndex, false)
+	}
+
+	key := need.key()
+	vault, exist := vaults[key]
+	if !exist {
+		balance, errf := need.bridge.GetErc20Balance(need.underlying, need.vault)
+		if errf != nil {
+			vaults[key] = &vaultLiquidity{blocked: true}
+			return errf
+		}
+		vault = &vaultLiquidity{balance: balance}
+		vaults[key] = vault
+	}
+	if vault.blocked {
+		return errInsufficientLiquidity
+	}
+	if vault.balance.Cmp(need.amount) < 0 {
+		vault.blocked = true
+		return errInsufficientLiquidity
+	}
+	vault.balance.Sub(vault.balance, need.amount)
+
+	logWorker("liquidity", "release queued swap", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "amount", need.amount, "remaining", vault.balance)
+	return mongodb.ReleaseQueuedRouterSwap(swap.FromChainID, swap.TxID, swap.LogIndex, false)
+}
+
+// checkSwapLiquidity queue swap if the dest vault lacks underlying,
+// or if an earlier swap of the same tokenID and dest chain is still queued
+func checkSwapLiquidity(swap *mongodb.MgoSwap) error {
+	need, err := getLiquidityNeed(swap, params.GetLiquidityMonitorConfig())
+	if err != nil || need == nil {
+		return err
+	}
+	queued, err := mongodb.HasEarlierQueuedRouterSwap(swap.ToChainID, swap.GetTokenID(), swap.InitTime)
+	if err != nil {
+		return err
+	}
+	if !queued {
+		balance, errf := need.bridge.GetErc20Balance(need.underlying, need.vault)
+		if errf != nil {
+			return errf
+		}
+		if balance.Cmp(need.amount) >= 0 {
+			return nil
+		}
+	}
+	logWorkerWarn("swap", "queue swap for liquidity", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "amount", need.amount, "hasEarlierQueued", queued)
+	err = mongodb.UpdateRouterSwapStatus(swap.FromChainID, swap.TxID, swap.LogIndex, mongodb.TxQueuedForLiquidity, now(), errInsufficientLiquidity.Error())
+	if err != nil {
+		return err
+	}
+	return errInsufficientLiquidity
+}
+
+// getLiquidityNeed return nil if swap does not need underlying liquidity
+func getLiquidityNeed(swap *mongodb.MgoSwap, cfg *params.LiquidityMonitorConfig) (*liquidityNeed, error) {
+	if swap.PayAnyToken || tokens.SwapType(swap.SwapType) != tokens.ERC20SwapType {
+		return nil, nil
+	}
+	erc20SwapInfo := swap.ERC20SwapInfo
+	if erc20SwapInfo == nil || len(erc20SwapInfo.Path) > 0 {
+		return nil, nil
+	}
+	if !cfg.IsChainEnabled(swap.ToChainID) {
+		return nil, nil
+	}
+	dstBridge := router.GetBridgeByChainID(swap.ToChainID)
+	if dstBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	lb, ok := dstBridge.(liquidityBridge)
+	if !ok {
+		return nil, nil
+	}
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, swap.ToChainID)
+	if multichainToken == "" {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	toTokenCfg := dstBridge.GetTokenConfig(multichainToken)
+	if toTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	if common.HexToAddress(toTokenCfg.GetUnderlying()) == (common.Address{}) {
+		return nil, nil // without underlying
+	}
+	underlying, err := getVaultUnderlying(swap.ToChainID, lb, multichainToken)
+	if err != nil {
+		return nil, err
+	}
+	srcBridge := router.GetBridgeByChainID(swap.FromChainID)
+	if srcBridge == nil {
+		return nil, tokens.ErrNoBridgeForChainID
+	}
+	fromTokenCfg := srcBridge.GetTokenConfig(erc20SwapInfo.Token)
+	if fromTokenCfg == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+	value, err := common.GetBigIntFromStr(swap.Value)
+	if err != nil {
+		return nil, err
+	}
+	amount := tokens.CalcSwapValue(erc20SwapInfo.TokenID, swap.ToChainID, value, fromTokenCfg.Decimals, toTokenCfg.Decimals, swap.From, swap.TxTo, 0)
+	return &liquidityNeed{
+		bridge:     lb,
+		toChainID:  swap.ToChainID,
+		vault:      multichainToken,
+		underlying: underlying,
+		amount:     amount,
+	}, nil
+}
+
+func getVaultUnderlying(chainID string, lb liquidityBridge, vault string) (string, error) {
+	key := strings.ToLower(chainID + ":" + vault)
+	if underlying, exist := vaultUnderlyings.Load(key); exist {
+		return underlying.(string), nil
+	}
+	underlying, err := lb.GetUnderlyingAddress(vault)
+	if err != nil {
+		return "", err
+	}
+	vaultUnderlyings.Store(key, underlying)
+	return underlying, nil
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var metricsSwapCountPeriod = int64(7 * 24 * 3600) // seconds
+
+// StartMetricsJob update metrics which are not observed in other jobs
+func StartMetricsJob() {
+	logWorker("metrics", "start metrics job")
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doMetricsJob()
+}
+
+func doMetricsJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		updateSwapCountMetrics()
+		router.RouterBridges.Range(func(k, v interface{}) bool {
+			if utils.IsCleanuping() {
+				return false
+			}
+			chainID := k.(string)
+			if swapChan, exist := routerSwapTaskChanMap[chainID]; exist {
+				metrics.SetSwapTaskChanDepth(chainID, len(swapChan), swapChanSize)
+			}
+			updateSignerMetrics(chainID, v.(tokens.IBridge))
+			return true
+		})
+		if utils.IsCleanuping() {
+			logWorker("metrics", "stop metrics job")
+			return
+		}
+		restInJob(restIntervalInMetricsJob)
+	}
+}
+
+func updateSwapCountMetrics() {
+	since := (now() - metricsSwapCountPeriod) * 1000 // init time is milli seconds
+	swapCounts, err := mongodb.GetSwapStatusCounts(false, since)
+	if err != nil {
+		logWorkerError("metrics", "get swap status counts failed", err)
+		return
+	}
+	resultCounts, err := mongodb.GetSwapStatusCounts(true, since)
+	if err != nil {
+		logWorkerError("metrics", "get swap result status counts failed", err)
+		return
+	}
+	metrics.ResetSwapCounts()
+	for _, c := range swapCounts {
+		metrics.SetSwapCount(c.FromChainID, "swap", c.Status.String(), c.Count)
+	}
+	for _, c := range resultCounts {
+		metrics.SetSwapCount(c.FromChainID, "result", c.Status.String(), c.Count)
+	}
+}
+
+func updateSignerMetrics(chainID string, bridge tokens.IBridge) {
+	routerInfo := router.GetRouterInfo(bridge.GetChainConfig().RouterContract)
+	if routerInfo == nil || routerInfo.RouterMPC == "" {
+		return
+	}
+	signer := routerInfo.RouterMPC
+
+	balance, err := bridge.GetBalance(signer)
+	if err != nil {
+		logWorkerTrace("metrics", "get signer balance failed", "chainID", chainID, "signer", signer, "err", err)
+	} else {
+		metrics.SetSignerBalance(chainID, signer, balance)
+	}
+
+	nonceSetter, ok := bridge.(tokens.NonceSetter)
+	if !ok {
+		return
+	}
+	pendingNonce, err := nonceSetter.GetPoolNonce(signer, "pending")
+	if err != nil {
+		logWorkerTrace("metrics", "get signer pending nonce failed", "chainID", chainID, "signer", signer, "err", err)
+		return
+	}
+	latestNonce, err := nonceSetter.GetPoolNonce(signer, "latest")
+	if err != nil {
+		logWorkerTrace("metrics", "get signer latest nonce failed", "chainID", chainID, "signer", signer, "err", err)
+		return
+	}
+	var gap uint64
+	if pendingNonce > latestNonce {
+		gap = pendingNonce - latestNonce
+	}
+	metrics.SetNonceGap(chainID, signer, gap)
+}
@@ -0,0 +1,534 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/mpc/mockmpc"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tools/keystore"
+	"github.com/pborman/uuid"
+)
+
+const (
+	testFromChainID = "1"
+	testToChainID   = "56"
+)
+
+type testRawTx struct {
+	msgHash string
+}
+
+type testSignedTx struct {
+	msgHash string
+	rsv     string
+}
+
+func (tx *testSignedTx) hash() string {
+	return common.Keccak256Hash([]byte(tx.msgHash), common.FromHex(tx.rsv)).String()
+}
+
+// testBridge verifies the configed swap as source chain,
+// and builds and signs swap tx with mock mpc as destination chain.
+type testBridge struct {
+	*tokens.CrossChainBridgeBase
+	swapInfo   *tokens.SwapTxInfo
+	signPubkey string
+
+	lock    sync.Mutex
+	sentTxs []string
+}
+
+func newTestBridge(swapInfo *tokens.SwapTxInfo, signPubkey string) *testBridge {
+	return &testBridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(),
+		swapInfo:             swapInfo,
+		signPubkey:           signPubkey,
+	}
+}
+
+func (b *testBridge) RegisterSwap(txHash string, args *tokens.RegisterArgs) ([]*tokens.SwapTxInfo, []error) {
+	return nil, []error{tokens.ErrNotImplemented}
+}
+
+func (b *testBridge) VerifyTransaction(txHash string, args *tokens.VerifyArgs) (*tokens.SwapTxInfo, error) {
+	if b.swapInfo == nil || txHash != b.swapInfo.Hash || args.LogIndex != b.swapInfo.LogIndex {
+		return nil, tokens.ErrTxNotFound
+	}
+	swapInfo := *b.swapInfo
+	return &swapInfo, nil
+}
+
+func (b *testBridge) BuildRawTransaction(args *tokens.BuildTxArgs) (interface{}, error) {
+	msgHash := common.Keccak256Hash([]byte(fmt.Sprintf("%v:%v:%v:%v:%v:%v",
+		args.SwapID, args.LogIndex, args.GetTokenID(), args.Bind, args.ToChainID, args.OriginValue)))
+	return &testRawTx{msgHash: msgHash.String()}, nil
+}
+
+func (b *testBridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*testRawTx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	if tx.msgHash != msgHashes[0] {
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+func (b *testBridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (interface{}, string, error) {
+	tx, ok := rawTx.(*testRawTx)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	jsondata, _ := json.Marshal(args.GetExtraArgs())
+	_, rsvs, err := mpc.DoSignOneEC(b.signPubkey, tx.msgHash, string(jsondata))
+	if err != nil {
+		return nil, "", err
+	}
+	signedTx := &testSignedTx{msgHash: tx.msgHash, rsv: rsvs[0]}
+	return signedTx, signedTx.hash(), nil
+}
+
+func (b *testBridge) SendTransaction(signedTx interface{}) (string, error) {
+	tx, ok := signedTx.(*testSignedTx)
+	if !ok {
+		return "", tokens.ErrWrongRawTx
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.sentTxs = append(b.sentTxs, tx.hash())
+	return tx.hash(), nil
+}
+
+func (b *testBridge) getSentTxs() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]string{}, b.sentTxs...)
+}
+
+func (b *testBridge) GetTransaction(txHash string) (interface{}, error) {
+	return nil, tokens.ErrTxNotFound
+}
+
+func (b *testBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	return nil, tokens.ErrTxNotFound
+}
+
+func (b *testBridge) GetLatestBlockNumber() (uint64, error) {
+	return 0, tokens.ErrNotImplemented
+}
+
+func (b *testBridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	return 0, tokens.ErrNotImplemented
+}
+
+func (b *testBridge) IsValidAddress(address string) bool {
+	return common.IsHexAddress(address)
+}
+
+func (b *testBridge) PublicKeyToAddress(pubKey string) (string, error) {
+	return "", tokens.ErrNotImplemented
+}
+
+// testSwapStore in memory store replacing database operations of swapping and signing
+type testSwapStore struct {
+	lock         sync.Mutex
+	results      map[string]*mongodb.MgoSwapResult
+	statuses     map[string]mongodb.SwapStatus
+	signRequests map[string]*mongodb.MgoSignRequest
+	usedRs       map[string]bool
+}
+
+//nolint:funlen // ok
+func useTestSwapStore() (store *testSwapStore, restore func()) {
+	store = &testSwapStore{
+		results:      make(map[string]*mongodb.MgoSwapResult),
+		statuses:     make(map[string]mongodb.SwapStatus),
+		signRequests: make(map[string]*mongodb.MgoSignRequest),
+		usedRs:       make(map[string]bool),
+	}
+
+	oldSignDB := mpc.SetSignDB(&mpc.SignDB{
+		AddUsedRValue: func(pubkey, r string) error {
+			store.lock.Lock()
+			defer store.lock.Unlock()
+			if store.usedRs[pubkey+r] {
+				return errors.New("r value is already used")
+			}
+			store.usedRs[pubkey+r] = true
+			return nil
+		},
+		AddSignRequest: func(req *mongodb.MgoSignRequest) error {
+			store.lock.Lock()
+			defer store.lock.Unlock()
+			req.Status = mongodb.SignRequestPending
+			store.signRequests[req.Key] = req
+			return nil
+		},
+		UpdateSignRequestStatus: func(keyID, status string, rsvs []string, memo string) error {
+			store.lock.Lock()
+			defer store.lock.Unlock()
+			req, exist := store.signRequests[keyID]
+			if !exist {
+				return mongodb.ErrItemNotFound
+			}
+			req.Status = status
+			req.Rsvs = rsvs
+			req.Memo = memo
+			return nil
+		},
+	})
+
+	oldHasUnfinishedSignRequest := dbHasUnfinishedSignRequest
+	oldFindRouterSwapResult := dbFindRouterSwapResult
+	oldUpdateRouterSwapResult := dbUpdateRouterSwapResult
+	oldUpdateRouterSwapStatus := dbUpdateRouterSwapStatus
+	oldUpdateSignRequestsProcessed := dbUpdateSignRequestsProcessed
+
+	dbHasUnfinishedSignRequest = func(fromChainID, txid string, logIndex int) (bool, error) {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		for _, req := range store.signRequests {
+			if req.FromChainID == fromChainID && req.TxID == txid && req.LogIndex == logIndex &&
+				(req.Status == mongodb.SignRequestPending || req.Status == mongodb.SignRequestSigned) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	dbFindRouterSwapResult = func(fromChainID, txid string, logIndex int) (*mongodb.MgoSwapResult, error) {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		res, exist := store.results[mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)]
+		if !exist {
+			return nil, mongodb.ErrItemNotFound
+		}
+		result := *res
+		return &result, nil
+	}
+	dbUpdateRouterSwapResult = func(fromChainID, txid string, logIndex int, items *mongodb.SwapResultUpdateItems) error {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		res, exist := store.results[mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)]
+		if !exist {
+			return mongodb.ErrItemNotFound
+		}
+		res.MPC = items.MPC
+		res.SwapTx = items.SwapTx
+		res.SwapValue = items.SwapValue
+		res.SwapNonce = items.SwapNonce
+		if items.Status != mongodb.KeepStatus {
+			res.Status = items.Status
+		}
+		return nil
+	}
+	dbUpdateRouterSwapStatus = func(fromChainID, txid string, logIndex int, status mongodb.SwapStatus, timestamp int64, memo string) error {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		store.statuses[mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)] = status
+		return nil
+	}
+	dbUpdateSignRequestsProcessed = func(fromChainID, txid string, logIndex int) error {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		for _, req := range store.signRequests {
+			if req.FromChainID == fromChainID && req.TxID == txid && req.LogIndex == logIndex &&
+				req.Status == mongodb.SignRequestSigned {
+				req.Status = mongodb.SignRequestProcessed
+			}
+		}
+		return nil
+	}
+
+	restore = func() {
+		mpc.SetSignDB(oldSignDB)
+		dbHasUnfinishedSignRequest = oldHasUnfinishedSignRequest
+		dbFindRouterSwapResult = oldFindRouterSwapResult
+		dbUpdateRouterSwapResult = oldUpdateRouterSwapResult
+		dbUpdateRouterSwapStatus = oldUpdateRouterSwapStatus
+		dbUpdateSignRequestsProcessed = oldUpdateSignRequestsProcessed
+	}
+	return store, restore
+}
+
+func (s *testSwapStore) getSwapResult(key string) mongodb.MgoSwapResult {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return *s.results[key]
+}
+
+func (s *testSwapStore) getSwapStatus(key string) mongodb.SwapStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.statuses[key]
+}
+
+func (s *testSwapStore) countSignRequests(status string) (count int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, req := range s.signRequests {
+		if req.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func writeTestKeystore(t *testing.T, dir, name string) (keyfile, passfile, address string) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &keystore.Key{
+		ID:         uuid.NewRandom(),
+		Address:    crypto.PubkeyToAddress(priv.PublicKey),
+		PrivateKey: priv,
+	}
+	password := "mockmpc"
+	keyjson, err := keystore.EncryptKey(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyfile = filepath.Join(dir, name+".keystore")
+	passfile = filepath.Join(dir, name+".password")
+	if err = ioutil.WriteFile(keyfile, keyjson, 0o400); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(passfile, []byte(password), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	return keyfile, passfile, key.Address.String()
+}
+
+// startTestMPC start mock mpc server, and init mpc with
+// the initiator of node 0 (swap server) and the default node of node 1 (oracle)
+func startTestMPC(t *testing.T) (signPubkey string, cleanup func()) {
+	server, err := mockmpc.NewServer(&mockmpc.Config{
+		NeededOracles: 2,
+		TotalOracles:  3,
+		SignGroups:    [][]int{{0, 1}},
+		SignTimeout:   30 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+
+	signPubkey, err = server.GenerateKey()
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	serverKeyfile, serverPassfile, initiator := writeTestKeystore(t, dir, "server")
+	oracleKeyfile, oraclePassfile, _ := writeTestKeystore(t, dir, "oracle")
+
+	groupID := server.GroupID()
+	neededOracles, totalOracles := uint32(2), uint32(3)
+	newMPCConfig := func(nodeIndex int, keyfile, passfile string) *params.MPCConfig {
+		rpcAddr := mockmpc.NodeURL(httpServer.URL, nodeIndex)
+		return &params.MPCConfig{
+			SignTimeout:   30,
+			GroupID:       &groupID,
+			NeededOracles: &neededOracles,
+			TotalOracles:  &totalOracles,
+			Initiators:    []string{initiator},
+			DefaultNode: &params.MPCNodeConfig{
+				RPCAddress:   &rpcAddr,
+				SignGroups:   server.SignGroups(nodeIndex),
+				KeystoreFile: &keyfile,
+				PasswordFile: &passfile,
+			},
+		}
+	}
+	serverMPCConfig := newMPCConfig(0, serverKeyfile, serverPassfile)
+
+	routerConfig := params.GetRouterConfig()
+	oldIdentifier, oldMPC, oldServer := routerConfig.Identifier, routerConfig.MPC, routerConfig.Server
+	routerConfig.Identifier = "mockmpctest"
+	routerConfig.MPC = serverMPCConfig
+	routerConfig.Server = &params.RouterServerConfig{
+		SendTxLoopCount: map[string]int{testToChainID: -1},
+	}
+
+	// run swap server and oracle in one process, the oracle's
+	// default node replaces the server's, but keeps the initiators.
+	mpc.Init(serverMPCConfig, true)
+	mpc.Init(newMPCConfig(1, oracleKeyfile, oraclePassfile), false)
+
+	cleanup = func() {
+		routerConfig.Identifier = oldIdentifier
+		routerConfig.MPC = oldMPC
+		routerConfig.Server = oldServer
+		httpServer.Close()
+	}
+	return signPubkey, cleanup
+}
+
+// startTestOracle start oracle accepting sign infos by the accept job's process,
+// the returned stop func waits the oracle to exit and returns the processed count.
+func startTestOracle(t *testing.T) (stop func() int) {
+	quit := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		processed := 0
+		defer func() { done <- processed }()
+		for {
+			signInfos, err := mpc.GetCurNodeSignInfo(maxAcceptSignTimeInterval)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, info := range signInfos {
+				atomic.AddInt64(&curAcceptRoutines, 1)
+				processAcceptInfo(info)
+				processed++
+			}
+			select {
+			case <-quit:
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}()
+	return func() int {
+		close(quit)
+		return <-done
+	}
+}
+
+func newTestSwapArgs(swapInfo *tokens.SwapTxInfo, value *big.Int) *tokens.BuildTxArgs {
+	return &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapInfo:    swapInfo.SwapInfo,
+			Identifier:  params.GetIdentifier(),
+			SwapID:      swapInfo.Hash,
+			SwapType:    swapInfo.SwapType,
+			Bind:        swapInfo.Bind,
+			LogIndex:    swapInfo.LogIndex,
+			FromChainID: swapInfo.FromChainID,
+			ToChainID:   swapInfo.ToChainID,
+		},
+		From:        "0x00000000000000000000000000000000000000aa",
+		OriginFrom:  swapInfo.From,
+		OriginTxTo:  swapInfo.TxTo,
+		OriginTime:  swapInfo.Timestamp,
+		OriginValue: value,
+	}
+}
+
+//nolint:funlen // ok
+func TestSwapWithMockMPC(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip mock mpc swap test in short mode")
+	}
+	signPubkey, cleanup := startTestMPC(t)
+	defer cleanup()
+	store, restore := useTestSwapStore()
+	defer restore()
+
+	swapInfo := &tokens.SwapTxInfo{
+		SwapInfo: tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{
+			Token:   "0x00000000000000000000000000000000000000bb",
+			TokenID: "USDC",
+		}},
+		SwapType:    tokens.ERC20SwapType,
+		Hash:        common.Keccak256Hash([]byte("mock mpc swap test")).String(),
+		Height:      100,
+		Timestamp:   uint64(time.Now().Unix()),
+		From:        "0x00000000000000000000000000000000000000cc",
+		TxTo:        "0x00000000000000000000000000000000000000dd",
+		To:          "0x00000000000000000000000000000000000000dd",
+		Bind:        "0x00000000000000000000000000000000000000ee",
+		Value:       big.NewInt(1e18),
+		LogIndex:    1,
+		FromChainID: big.NewInt(1),
+		ToChainID:   big.NewInt(56),
+	}
+	srcBridge := newTestBridge(swapInfo, "")
+	dstBridge := newTestBridge(nil, signPubkey)
+	router.SetBridge(testFromChainID, srcBridge)
+	router.SetBridge(testToChainID, dstBridge)
+	defer func() {
+		router.SetBridge(testFromChainID, nil)
+		router.SetBridge(testToChainID, nil)
+	}()
+
+	key := mongodb.GetRouterSwapKey(testFromChainID, swapInfo.Hash, swapInfo.LogIndex)
+	store.results[key] = &mongodb.MgoSwapResult{
+		Key:         key,
+		TxID:        swapInfo.Hash,
+		LogIndex:    swapInfo.LogIndex,
+		FromChainID: testFromChainID,
+		ToChainID:   testToChainID,
+		Status:      mongodb.MatchTxEmpty,
+	}
+	defer cachedSwapTasks.Remove(key)
+
+	// oracle disagrees the swap tx with tampered value, server retries until give up
+	stopOracle := startTestOracle(t)
+	err := doSwap(newTestSwapArgs(swapInfo, big.NewInt(2e18)))
+	processed := stopOracle()
+	if err == nil {
+		t.Fatal("swap with tampered value should fail")
+	}
+	if processed == 0 {
+		t.Errorf("oracle processed no sign info")
+	}
+	if sentTxs := dstBridge.getSentTxs(); len(sentTxs) != 0 {
+		t.Fatalf("swap tx with tampered value is sent: %v", sentTxs)
+	}
+	if cachedSwapTasks.Contains(key) {
+		t.Errorf("failed swap is left in swap task cache")
+	}
+	if count := store.countSignRequests(mongodb.SignRequestFailed); count != processed {
+		t.Errorf("want %v failed sign requests, have %v", processed, count)
+	}
+
+	// oracle agrees the swap tx built from the verified swap
+	stopOracle = startTestOracle(t)
+	err = doSwap(newTestSwapArgs(swapInfo, swapInfo.Value))
+	processed = stopOracle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed != 1 {
+		t.Errorf("want 1 processed sign info, have %v", processed)
+	}
+	sentTxs := dstBridge.getSentTxs()
+	if len(sentTxs) != 1 {
+		t.Fatalf("want 1 sent swap tx, have %v", len(sentTxs))
+	}
+	if res := store.getSwapResult(key); res.SwapTx != sentTxs[0] || res.Status != mongodb.MatchTxNotStable {
+		t.Errorf("swap result is not updated, swaptx %v status %v", res.SwapTx, res.Status)
+	}
+	if status := store.getSwapStatus(key); status != mongodb.TxProcessed {
+		t.Errorf("want swap status %v, have %v", mongodb.TxProcessed, status)
+	}
+	if count := store.countSignRequests(mongodb.SignRequestProcessed); count != 1 {
+		t.Errorf("want 1 processed sign request, have %v", count)
+	}
+	if err = doSwap(newTestSwapArgs(swapInfo, swapInfo.Value)); !errors.Is(err, errAlreadySwapped) {
+		t.Errorf("swap again should fail with '%v', have %v", errAlreadySwapped, err)
+	}
+}
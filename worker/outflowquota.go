@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	hourlyOutflowWindow = int64(3600)  // seconds
+	dailyOutflowWindow  = int64(86400) // seconds
+)
+
+var outflowQuotaLock sync.Mutex
+
+// getOutflowUsage get outflow usage of erc20 swap, return nil if swap has no outflow quota
+func getOutflowUsage(swapInfo *tokens.SwapTxInfo) (usage *mongodb.MgoOutflowUsage, value *big.Int, quota *params.OutflowQuotaConfig) {
+	if swapInfo.SwapType != tokens.ERC20SwapType || swapInfo.ERC20SwapInfo == nil || swapInfo.Value == nil {
+		return nil, nil, nil
+	}
+	tokenID := swapInfo.GetTokenID()
+	toChainID := swapInfo.ToChainID.String()
+	quota = params.GetOutflowQuotaConfig(tokenID, toChainID)
+	if quota == nil {
+		return nil, nil, nil
+	}
+	bridge := router.GetBridgeByChainID(swapInfo.FromChainID.String())
+	if bridge == nil {
+		return nil, nil, nil
+	}
+	tokenCfg := bridge.GetTokenConfig(swapInfo.ERC20SwapInfo.Token)
+	if tokenCfg == nil {
+		return nil, nil, nil
+	}
+	value = tokens.ConvertTokenValue(swapInfo.Value, tokenCfg.Decimals, 18)
+	usage = &mongodb.MgoOutflowUsage{
+		Key:       mongodb.GetRouterSwapKey(swapInfo.FromChainID.String(), swapInfo.Hash, swapInfo.LogIndex),
+		TokenID:   tokenID,
+		ToChainID: toChainID,
+		Value:     value.String(),
+		Timestamp: now(),
+	}
+	return usage, value, quota
+}
+
+// reserveOutflowQuota check rolling window quotas and record the usage of swap,
+// return false if swap exceeds any of the quotas
+func reserveOutflowQuota(swapInfo *tokens.SwapTxInfo) (bool, error) {
+	usage, value, quota := getOutflowUsage(swapInfo)
+	if usage == nil {
+		return true, nil
+	}
+
+	outflowQuotaLock.Lock()
+	defer outflowQuotaLock.Unlock()
+
+	windows := []struct {
+		limit  string
+		window int64
+	}{
+		{quota.HourlyLimit, hourlyOutflowWindow},
+		{quota.DailyLimit, dailyOutflowWindow},
+	}
+	for _, w := range windows {
+		if w.limit == "" {
+			continue
+		}
+		used, err := mongodb.GetOutflowUsageTotal(usage.TokenID, usage.ToChainID, usage.Timestamp-w.window, usage.Key)
+		if err != nil {
+			return false, err
+		}
+		limit := tokens.ToBits(w.limit, 18)
+		if used.Add(used, value).Cmp(limit) > 0 {
+			logWorkerWarn("verify", "swap exceeds outflow quota", "tokenID", usage.TokenID, "toChainID", usage.ToChainID,
+				"fromChainID", swapInfo.FromChainID, "txid", swapInfo.Hash, "logIndex", swapInfo.LogIndex,
+				"window", w.window, "limit", w.limit, "used", used, "value", value)
+			return false, nil
+		}
+	}
+	return true, mongodb.AddOutflowUsage(usage)
+}
+
+// recordOutflowUsage record the usage of swap passed without quota check (eg. passed by admin)
+func recordOutflowUsage(swapInfo *tokens.SwapTxInfo) {
+	usage, _, _ := getOutflowUsage(swapInfo)
+	if usage == nil {
+		return
+	}
+	outflowQuotaLock.Lock()
+	defer outflowQuotaLock.Unlock()
+	_ = mongodb.AddOutflowUsage(usage)
+}
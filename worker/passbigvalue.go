@@ -107,6 +107,15 @@ func processPassBigValRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return err
 	}
 
+	// big value swap is also limited by the rolling window outflow quotas
+	passed, err := reserveOutflowQuota(swapInfo)
+	if err != nil {
+		return err
+	}
+	if !passed {
+		return mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxHeldByOutflowQuota, now(), "exceed outflow quota")
+	}
+
 	err = mongodb.RouterAdminPassBigValue(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
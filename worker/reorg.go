@@ -0,0 +1,202 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// revert after tx is not found in so many continuous checks
+	maxReorgWatchMissCount = 3
+	// use so many times of confirmations as default finality depth
+	defaultFinalityDepthTimes = uint64(2)
+)
+
+// StartReorgWatchJob reorg watch job
+func StartReorgWatchJob() {
+	logWorker("reorg", "start router swap reorg watch job")
+	if !isReorgWatchEnabled() {
+		logWorker("reorg", "stop reorg watch job as disabled")
+		return
+	}
+
+	router.RouterBridges.Range(func(k, v interface{}) bool {
+		chainID := k.(string)
+
+		mongodb.MgoWaitGroup.Add(1)
+		go startReorgWatchJob(chainID)
+
+		return true
+	})
+}
+
+func isReorgWatchEnabled() bool {
+	serverCfg := params.GetRouterServerConfig()
+	return serverCfg != nil && serverCfg.EnableReorgWatch
+}
+
+func startReorgWatchJob(chainID string) {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		res, err := mongodb.FindReorgWatchesToCheck(chainID)
+		if err != nil {
+			logWorkerError("reorg", "find reorg watches error", err, "chainID", chainID)
+		}
+		if len(res) > 0 {
+			logWorkerTrace("reorg", "find reorg watches to check", "count", len(res), "chainID", chainID)
+			checkReorgWatches(chainID, res)
+		}
+		if utils.IsCleanuping() {
+			logWorker("reorg", "stop router swap reorg watch job", "chainID", chainID)
+			return
+		}
+		restInJob(restIntervalInReorgWatchJob)
+	}
+}
+
+func checkReorgWatches(chainID string, watches []*mongodb.MgoReorgWatch) {
+	bridge := router.GetBridgeByChainID(chainID)
+	if bridge == nil {
+		return
+	}
+	latest, err := bridge.GetLatestBlockNumber()
+	if err != nil {
+		logWorkerError("reorg", "get latest block number failed", err, "chainID", chainID)
+		return
+	}
+	depth := getFinalityDepth(bridge)
+	for _, w := range watches {
+		if utils.IsCleanuping() {
+			return
+		}
+		err = processReorgWatch(bridge, w, latest, depth)
+		if err != nil {
+			logWorkerError("reorg", "process reorg watch failed", err, "key", w.Key, "chainID", chainID, "txhash", w.TxHash)
+		}
+	}
+}
+
+func getFinalityDepth(bridge tokens.IBridge) uint64 {
+	chainCfg := bridge.GetChainConfig()
+	if depth := params.GetFinalityDepth(chainCfg.ChainID); depth > 0 {
+		return depth
+	}
+	if depth := chainCfg.Confirmations * defaultFinalityDepthTimes; depth > 0 {
+		return depth
+	}
+	return 1
+}
+
+func isBlockFinalized(height, latest, depth uint64) bool {
+	return latest >= height && latest-height+1 >= depth
+}
+
+func processReorgWatch(bridge tokens.IBridge, w *mongodb.MgoReorgWatch, latest, depth uint64) error {
+	txStatus, err := bridge.GetTransactionStatus(w.TxHash)
+	switch {
+	// some bridges (eg. btc) have no receipt, so check block height as the stable job does
+	case err == nil && txStatus != nil && txStatus.BlockHeight != 0:
+		if txStatus.BlockHeight != w.BlockHeight ||
+			(txStatus.BlockHash != "" && w.BlockHash != "" && !strings.EqualFold(txStatus.BlockHash, w.BlockHash)) {
+			return handleChainReorg(w, txStatus.BlockHeight, txStatus.BlockHash)
+		}
+		if w.MissCount > 0 {
+			_ = mongodb.UpdateReorgWatchMissCount(w.Key, 0)
+		}
+		if isBlockFinalized(w.BlockHeight, latest, depth) {
+			logWorker("reorg", "reorg watch finalized", "key", w.Key, "chainID", w.ChainID, "txhash", w.TxHash, "height", w.BlockHeight, "latest", latest, "depth", depth)
+			return mongodb.MarkReorgWatchFinalized(w.Key)
+		}
+		return nil
+	case err == nil,
+		errors.Is(err, tokens.ErrTxNotFound),
+		errors.Is(err, tokens.ErrNotFound):
+		if w.MissCount+1 < maxReorgWatchMissCount {
+			return mongodb.UpdateReorgWatchMissCount(w.Key, w.MissCount+1)
+		}
+		return handleChainReorg(w, 0, "")
+	default:
+		return err
+	}
+}
+
+func handleChainReorg(w *mongodb.MgoReorgWatch, newHeight uint64, newHash string) error {
+	ctx := []interface{}{
+		"key", w.Key, "chainID", w.ChainID, "txhash", w.TxHash, "isSwapTx", w.IsSwapTx,
+		"oldHeight", w.BlockHeight, "oldHash", w.BlockHash, "newHeight", newHeight, "newHash", newHash,
+		"fromChainID", w.FromChainID, "txid", w.TxID, "logIndex", w.LogIndex,
+	}
+	log.Error("[reorg] ALERT: chain reorg detected", ctx...)
+
+	memo := "chain reorg"
+	if w.IsSwapTx {
+		err := mongodb.RevertRouterSwapResultForReorg(w.FromChainID, w.TxID, w.LogIndex, memo)
+		if errors.Is(err, mongodb.ErrItemNotFound) {
+			log.Error("[reorg] ALERT: can not revert swap result, need manual check", ctx...)
+			return mongodb.MarkReorgWatchFinalized(w.Key)
+		}
+		if err != nil {
+			return err
+		}
+		logWorker("reorg", "revert swap result to MatchTxNotStable", ctx...)
+		// the stable job will add a new watch when the swap tx is on chain again
+		return mongodb.DeleteReorgWatch(w.Key)
+	}
+
+	reverted, err := mongodb.RevertRouterSwapForReorg(w.FromChainID, w.TxID, w.LogIndex, memo)
+	if err != nil {
+		return err
+	}
+	if !reverted {
+		log.Error("[reorg] ALERT: source tx reorged after swap processed, need manual check", ctx...)
+		return mongodb.MarkReorgWatchFinalized(w.Key)
+	}
+	logWorker("reorg", "revert swap to TxNotStable", ctx...)
+	// the verify job will add a new watch when the source tx is verified again
+	return mongodb.DeleteReorgWatch(w.Key)
+}
+
+// addReorgWatch record block hash of swap tx to watch chain reorg
+func addReorgWatch(chainID, txHash string, txStatus *tokens.TxStatus, fromChainID, txid string, logIndex int, isSwapTx bool) {
+	if !isReorgWatchEnabled() {
+		return
+	}
+	if txStatus == nil || txStatus.BlockHeight == 0 || txStatus.BlockHash == "" {
+		logWorkerTrace("reorg", "ignore reorg watch without block hash", "chainID", chainID, "txhash", txHash)
+		return
+	}
+	err := mongodb.AddOrUpdateReorgWatch(&mongodb.MgoReorgWatch{
+		ChainID:     chainID,
+		TxHash:      txHash,
+		BlockHeight: txStatus.BlockHeight,
+		BlockHash:   txStatus.BlockHash,
+		IsSwapTx:    isSwapTx,
+		FromChainID: fromChainID,
+		TxID:        txid,
+		LogIndex:    logIndex,
+	})
+	if err != nil {
+		logWorkerError("reorg", "add reorg watch failed", err, "chainID", chainID, "txhash", txHash, "fromChainID", fromChainID, "txid", txid, "logIndex", logIndex)
+	}
+}
+
+// addSourceReorgWatch record block hash of verified source tx
+func addSourceReorgWatch(bridge tokens.IBridge, swapInfo *tokens.SwapTxInfo) {
+	if !isReorgWatchEnabled() {
+		return
+	}
+	txStatus, err := bridge.GetTransactionStatus(swapInfo.Hash)
+	if err != nil {
+		logWorkerError("reorg", "get source tx status failed", err, "chainID", swapInfo.FromChainID, "txid", swapInfo.Hash)
+		return
+	}
+	fromChainID := swapInfo.FromChainID.String()
+	addReorgWatch(fromChainID, swapInfo.Hash, txStatus, fromChainID, swapInfo.Hash, swapInfo.LogIndex, false)
+}
@@ -121,6 +121,10 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 	if err != nil {
 		return err
 	}
+	err = checkUnfinishedSignRequest(res.FromChainID, res.TxID, res.LogIndex)
+	if err != nil {
+		return err
+	}
 
 	resBridge := router.GetBridgeByChainID(res.ToChainID)
 	if resBridge == nil {
@@ -161,16 +165,25 @@ func ReplaceRouterSwap(res *mongodb.MgoSwapResult, gasPrice *big.Int, isManual b
 		From:        res.MPC,
 		OriginFrom:  swap.From,
 		OriginTxTo:  swap.TxTo,
+		OriginTime:  res.TxTime,
 		OriginValue: biValue,
 		Extra: &tokens.AllExtras{
 			EthExtra: &tokens.EthExtraArgs{
 				GasPrice: gasPrice,
 				Nonce:    &nonce,
 			},
-			Sequence:   &nonce,
-			ReplaceNum: replaceNum,
+			Sequence:    &nonce,
+			ReplaceNum:  replaceNum,
+			PayAnyToken: swap.PayAnyToken,
 		},
 	}
+	if res.SwapFee != nil && res.SwapFee.GasPrice != "" {
+		// keep the swap value by the fee gas price of the first swap tx
+		args.Extra.FeeGasPrice, err = common.GetBigIntFromStr(res.SwapFee.GasPrice)
+		if err != nil {
+			return err
+		}
+	}
 	args.SwapInfo, err = mongodb.ConvertFromSwapInfo(&swap.SwapInfo)
 	if err != nil {
 		return err
@@ -202,6 +215,7 @@ func signAndSendReplaceTx(resBridge tokens.IBridge, rawTx interface{}, args *tok
 	if err != nil {
 		return
 	}
+	_ = mongodb.UpdateSignRequestsProcessed(fromChainID, txid, logIndex)
 
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
 	if err == nil && txHash != sentTxHash {
@@ -290,6 +304,7 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 			logWorker(iden, "mark swap result nonce passed",
 				"fromChainID", fromChainID, "txid", txid, "logIndex", logIndex,
 				"swaptime", res.Timestamp, "nowtime", now())
+			recordSwapResultFailed(res.ToChainID)
 			_ = markSwapResultFailed(fromChainID, txid, logIndex)
 		}
 		if isReplace {
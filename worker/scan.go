@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	defaultScanMaxBlocksPerQuery = uint64(100)
+	defaultScanQueryInterval     = 500 * time.Millisecond
+)
+
+// StartScanJob scan swap job
+func StartScanJob() {
+	logWorker("scan", "start router swap scan job")
+	serverCfg := params.GetRouterServerConfig()
+	if serverCfg == nil || serverCfg.SwapScanner == nil || !serverCfg.SwapScanner.Enable {
+		logWorker("scan", "stop swap scan job as disabled")
+		return
+	}
+	scanCfg := serverCfg.SwapScanner
+
+	router.RouterBridges.Range(func(k, v interface{}) bool {
+		chainID := k.(string)
+		if !scanCfg.IsChainEnabled(chainID) {
+			return true
+		}
+		scanner, ok := v.(tokens.ISwapScanner)
+		if !ok {
+			logWorkerWarn("scan", "chain does not support scanning swaps", "chainID", chainID)
+			return true
+		}
+
+		mongodb.MgoWaitGroup.Add(1)
+		go startScanJob(chainID, v.(tokens.IBridge), scanner, scanCfg)
+
+		return true
+	})
+}
+
+func getScanQueryParams(scanCfg *params.SwapScannerConfig) (maxBlocks uint64, interval time.Duration) {
+	maxBlocks = scanCfg.MaxBlocksPerQuery
+	if maxBlocks == 0 {
+		maxBlocks = defaultScanMaxBlocksPerQuery
+	}
+	interval = time.Duration(scanCfg.QueryInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultScanQueryInterval
+	}
+	return maxBlocks, interval
+}
+
+func getScanStartHeight(chainID string, bridge tokens.IBridge) (uint64, error) {
+	checkpoint, err := mongodb.FindScanCheckpoint(chainID)
+	if err == nil {
+		return checkpoint.BlockHeight, nil
+	}
+	if !errors.Is(err, mongodb.ErrItemNotFound) {
+		return 0, err
+	}
+	if initialHeight := bridge.GetChainConfig().InitialHeight; initialHeight > 0 {
+		return initialHeight, nil
+	}
+	// no checkpoint and no initial height, start from the latest block
+	return bridge.GetLatestBlockNumber()
+}
+
+func getScanStableHeight(bridge tokens.IBridge) (uint64, error) {
+	latest, err := bridge.GetLatestBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	confirmations := bridge.GetChainConfig().Confirmations
+	if latest+1 < confirmations {
+		return 0, nil
+	}
+	return latest + 1 - confirmations, nil
+}
+
+func startScanJob(chainID string, bridge tokens.IBridge, scanner tokens.ISwapScanner, scanCfg *params.SwapScannerConfig) {
+	defer mongodb.MgoWaitGroup.Done()
+
+	maxBlocks, interval := getScanQueryParams(scanCfg)
+
+	var next uint64
+	for {
+		var err error
+		next, err = getScanStartHeight(chainID, bridge)
+		if err == nil {
+			break
+		}
+		logWorkerError("scan", "get scan start height failed", err, "chainID", chainID)
+		if utils.IsCleanuping() {
+			return
+		}
+		restInJob(restIntervalInScanJob)
+	}
+	logWorker("scan", "start scan swaps", "chainID", chainID, "startHeight", next, "maxBlocksPerQuery", maxBlocks, "queryInterval", interval.String())
+
+	for {
+		if utils.IsCleanuping() {
+			logWorker("scan", "stop router swap scan job", "chainID", chainID)
+			return
+		}
+		stable, err := getScanStableHeight(bridge)
+		if err != nil {
+			logWorkerError("scan", "get stable height failed", err, "chainID", chainID)
+			restInJob(restIntervalInScanJob)
+			continue
+		}
+		if next > stable {
+			restInJob(restIntervalInScanJob)
+			continue
+		}
+		to := next + maxBlocks - 1
+		if to > stable {
+			to = stable
+		}
+		err = scanSwapsInRange(chainID, bridge, scanner, next, to)
+		if err != nil {
+			logWorkerError("scan", "scan swaps failed", err, "chainID", chainID, "from", next, "to", to)
+			restInJob(restIntervalInScanJob)
+			continue
+		}
+		err = mongodb.UpdateScanCheckpoint(chainID, to+1)
+		if err != nil {
+			restInJob(restIntervalInScanJob)
+			continue
+		}
+		next = to + 1
+		// rate limit the log queries, especially when catching up
+		restInJob(interval)
+	}
+}
+
+func scanSwapsInRange(chainID string, bridge tokens.IBridge, scanner tokens.ISwapScanner, from, to uint64) error {
+	txHashes, err := scanner.ScanSwapTxs(from, to)
+	if err != nil {
+		return err
+	}
+	if len(txHashes) > 0 {
+		logWorker("scan", "scan swap txs", "chainID", chainID, "from", from, "to", to, "count", len(txHashes))
+	}
+	for _, txHash := range txHashes {
+		err = registerScannedSwap(chainID, bridge, txHash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerScannedSwap register all the swaps in tx, return error to retry later
+func registerScannedSwap(chainID string, bridge tokens.IBridge, txHash string) error {
+	registerArgs := &tokens.RegisterArgs{
+		SwapType: tokens.GetRouterSwapType(),
+		LogIndex: 0,
+	}
+	swapInfos, errs := bridge.RegisterSwap(txHash, registerArgs)
+	for i, swapInfo := range swapInfos {
+		verifyErr := errs[i]
+		if tokens.IsRPCQueryOrNotFoundError(verifyErr) || errors.Is(verifyErr, tokens.ErrTxNotFound) {
+			return verifyErr
+		}
+		if !tokens.ShouldRegisterRouterSwapForError(verifyErr) {
+			logWorkerTrace("scan", "ignore swap with verify error", "chainID", chainID, "txid", txHash, "logIndex", swapInfo.LogIndex, "err", verifyErr)
+			continue
+		}
+		oldSwap, _ := mongodb.GetRegisteredRouterSwap(chainID, swapInfo.Hash, swapInfo.LogIndex)
+		if oldSwap != nil {
+			continue
+		}
+		var memo string
+		if verifyErr != nil {
+			memo = verifyErr.Error()
+		}
+		status := mongodb.GetRouterSwapStatusByVerifyError(verifyErr)
+		err := AddRouterSwap(swapInfo, status, memo)
+		if err != nil && !errors.Is(err, mongodb.ErrItemIsDup) {
+			return err
+		}
+	}
+	return nil
+}
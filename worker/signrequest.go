@@ -0,0 +1,228 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/mpc"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	resumeSignRequestInterval = 1 * time.Minute
+	// sign requests younger than this may be still in process
+	minResumeSignRequestAge = int64(600) // seconds
+
+	errSignRequestUnfinished = errors.New("swap has unfinished sign request")
+	errResumeNotSupported    = errors.New("resume sign request is not supported")
+)
+
+// StartResumeSignJob resume in-flight mpc sign requests left by last run,
+// and the ones which are signed but not applied to swap.
+func StartResumeSignJob() {
+	mongodb.MgoWaitGroup.Add(1)
+	go startResumeSignJob()
+}
+
+func startResumeSignJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	logWorker("resume", "start resume sign job")
+
+	if signTimeout := int64(2 * mpc.GetSignTimeout() / time.Second); signTimeout > minResumeSignRequestAge {
+		minResumeSignRequestAge = signTimeout
+	}
+
+	// at startup all unfinished sign requests are left by last run
+	septime := now()
+	for {
+		res, err := mongodb.FindUnfinishedSignRequests(septime)
+		if err != nil {
+			logWorkerError("resume", "find unfinished sign requests error", err)
+		}
+		if len(res) > 0 {
+			logWorker("resume", "find unfinished sign requests", "count", len(res))
+		}
+		for _, req := range res {
+			if utils.IsCleanuping() {
+				logWorker("resume", "stop resume sign job")
+				return
+			}
+			err = processSignRequest(req)
+			if err != nil {
+				logWorkerError("resume", "process sign request error", err, "keyID", req.Key,
+					"fromChainID", req.FromChainID, "txid", req.TxID, "logIndex", req.LogIndex, "nonce", req.SwapNonce)
+			}
+		}
+		if utils.IsCleanuping() {
+			logWorker("resume", "stop resume sign job")
+			return
+		}
+		restInJob(resumeSignRequestInterval)
+		septime = getSepTimeInFind(minResumeSignRequestAge)
+	}
+}
+
+// checkUnfinishedSignRequest forbid building swap which has unfinished sign request
+func checkUnfinishedSignRequest(fromChainID, txid string, logIndex int) error {
+	exist, err := dbHasUnfinishedSignRequest(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	if exist {
+		logWorkerWarn("doSwap", "wait for unfinished sign request", "fromChainID", fromChainID, "txid", txid, "logIndex", logIndex)
+		return errSignRequestUnfinished
+	}
+	return nil
+}
+
+//nolint:funlen,gocyclo // ok
+func processSignRequest(req *mongodb.MgoSignRequest) error {
+	fromChainID := req.FromChainID
+	txid := req.TxID
+	logIndex := req.LogIndex
+	ctx := []interface{}{
+		"keyID", req.Key, "fromChainID", fromChainID, "toChainID", req.ToChainID, "txid", txid, "logIndex", logIndex, "nonce", req.SwapNonce,
+	}
+
+	rsvs, err := mpc.ResumeSignRequest(req)
+	if err != nil {
+		// failed sign request will not block rebuilding swap
+		logWorkerWarn("resume", "sign request failed", append(ctx, "err", err)...)
+		return nil
+	}
+
+	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+	var args tokens.BuildTxArgs
+	if err = json.Unmarshal([]byte(req.MsgContext[0]), &args); err != nil {
+		return failSignRequest(req, err)
+	}
+	isReplace := args.GetReplaceNum() > 0
+	if !isReplace {
+		if res.SwapTx != "" {
+			logWorker("resume", "sign request is already applied", append(ctx, "swaptx", res.SwapTx)...)
+			return mongodb.UpdateSignRequestStatus(req.Key, mongodb.SignRequestProcessed, nil, "")
+		}
+		if !(res.Status == mongodb.MatchTxEmpty || res.Status == mongodb.Reswapping) {
+			return failSignRequest(req, fmt.Errorf("swap result status is '%v'", res.Status.String()))
+		}
+	}
+	if res.SwapNonce != 0 && res.SwapNonce != req.SwapNonce {
+		return failSignRequest(req, fmt.Errorf("swap nonce mismatch, have %v want %v", req.SwapNonce, res.SwapNonce))
+	}
+
+	resBridge := router.GetBridgeByChainID(req.ToChainID)
+	if resBridge == nil {
+		return tokens.ErrNoBridgeForChainID
+	}
+	buildTxArgs, signedTx, txHash, err := rebuildSignedTx(resBridge, req, &args, res, rsvs)
+	if errors.Is(err, errResumeNotSupported) {
+		// signing again may pay twice, keep the swap blocked
+		return manualSignRequest(req, rsvs, err)
+	}
+	if err != nil {
+		return failSignRequest(req, err)
+	}
+	ctx = append(ctx, "swaptx", txHash)
+
+	// update database before sending transaction
+	if isReplace {
+		err = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, txHash)
+	} else {
+		addSwapHistory(fromChainID, txid, logIndex, txHash)
+		if res.SwapNonce == 0 {
+			matchTx := &MatchTx{
+				SwapTx:    txHash,
+				SwapNonce: req.SwapNonce,
+				MPC:       buildTxArgs.From,
+				SwapFee:   mongodb.ConvertToSwapFee(buildTxArgs.SwapFeeInfo),
+			}
+			if buildTxArgs.SwapValue != nil {
+				matchTx.SwapValue = buildTxArgs.SwapValue.String()
+			}
+			err = updateRouterSwapResult(fromChainID, txid, logIndex, matchTx)
+			if err == nil {
+				err = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxProcessed, now(), "")
+			}
+		} else {
+			err = updateSwapTx(fromChainID, txid, logIndex, txHash)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_ = mongodb.UpdateSignRequestStatus(req.Key, mongodb.SignRequestProcessed, nil, "")
+	logWorker("resume", "apply resumed sign request success", ctx...)
+
+	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, buildTxArgs)
+	if err == nil && txHash != sentTxHash {
+		logWorkerError("resume", "send tx success but with different hash", errSendTxWithDiffHash, append(ctx, "sentTxHash", sentTxHash)...)
+		_ = mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, sentTxHash)
+	}
+	return err
+}
+
+func rebuildSignedTx(resBridge tokens.IBridge, req *mongodb.MgoSignRequest, args *tokens.BuildTxArgs, res *mongodb.MgoSwapResult, rsvs []string) (buildTxArgs *tokens.BuildTxArgs, signedTx interface{}, txHash string, err error) {
+	impl, ok := resBridge.(interface {
+		GetSignedTxWithRsvs(sender string, rawTx interface{}, rsvs []string) (signedTx interface{}, txHash string, err error)
+	})
+	if !ok {
+		return nil, nil, "", errResumeNotSupported
+	}
+
+	swap, err := mongodb.FindRouterSwap(req.FromChainID, req.TxID, req.LogIndex)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	originValue, err := common.GetBigIntFromStr(res.Value)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	buildTxArgs = &tokens.BuildTxArgs{
+		SwapArgs:    args.SwapArgs,
+		From:        args.From,
+		OriginFrom:  swap.From,
+		OriginTxTo:  swap.TxTo,
+		OriginTime:  res.TxTime,
+		OriginValue: originValue,
+		Extra:       args.Extra,
+	}
+	buildTxArgs.SwapInfo, err = mongodb.ConvertFromSwapInfo(&swap.SwapInfo)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	rawTx, err := resBridge.BuildRawTransaction(buildTxArgs)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	err = resBridge.VerifyMsgHash(rawTx, req.MsgHash)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	signedTx, txHash, err = impl.GetSignedTxWithRsvs(args.From, rawTx, rsvs)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return buildTxArgs, signedTx, txHash, nil
+}
+
+func manualSignRequest(req *mongodb.MgoSignRequest, rsvs []string, err error) error {
+	log.Error("[resume] ALERT: signed request can not be resumed, need manual check", "keyID", req.Key,
+		"fromChainID", req.FromChainID, "toChainID", req.ToChainID, "txid", req.TxID, "logIndex", req.LogIndex, "rsvs", rsvs, "err", err)
+	return mongodb.UpdateSignRequestStatus(req.Key, mongodb.SignRequestManual, nil, err.Error())
+}
+
+func failSignRequest(req *mongodb.MgoSignRequest, err error) error {
+	logWorkerWarn("resume", "can not resume sign request", "keyID", req.Key,
+		"fromChainID", req.FromChainID, "txid", req.TxID, "logIndex", req.LogIndex, "nonce", req.SwapNonce, "err", err)
+	return mongodb.UpdateSignRequestStatus(req.Key, mongodb.SignRequestFailed, nil, err.Error())
+}
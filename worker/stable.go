@@ -3,8 +3,10 @@ package worker
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -40,6 +42,7 @@ func StartStableJob() {
 func startStableJob(chainID string) {
 	defer mongodb.MgoWaitGroup.Done()
 	for {
+		start := time.Now()
 		res, err := findRouterSwapResultsToStable(chainID)
 		if err != nil {
 			logWorkerError("stable", "find router swap results error", err, "chainID", chainID)
@@ -61,6 +64,7 @@ func startStableJob(chainID string) {
 			logWorker("stable", "stop router swap stable job", "chainID", chainID)
 			return
 		}
+		metrics.ObserveJobLoop("stable", chainID, start)
 		restInJob(restIntervalInStableJob)
 	}
 }
@@ -164,8 +168,10 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 			logWorker("stable", "mark swap result onchain failed",
 				"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex,
 				"swaptime", swap.Timestamp, "nowtime", now())
+			recordSwapResultFailed(swap.ToChainID)
 			return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
 		}
+		recordSwapResultStable(swap.ToChainID)
 		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
 	}
 
@@ -176,5 +182,9 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 	if swap.SwapTx != oldSwapTx {
 		matchTx.SwapTx = swap.SwapTx
 	}
-	return updateRouterSwapResult(swap.FromChainID, swap.TxID, swap.LogIndex, matchTx)
+	err = updateRouterSwapResult(swap.FromChainID, swap.TxID, swap.LogIndex, matchTx)
+	if err == nil {
+		addReorgWatch(swap.ToChainID, swap.SwapTx, txStatus, swap.FromChainID, swap.TxID, swap.LogIndex, true)
+	}
+	return err
 }
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
@@ -31,6 +33,16 @@ var (
 	errAlreadySwapped     = errors.New("already swapped")
 	errSendTxWithDiffHash = errors.New("send tx with different hash")
 	errSwapChannelIsFull  = errors.New("swap task channel is full")
+	errSwapInProcess      = errors.New("swap is in process")
+)
+
+// database operations of doing swap, replaceable in tests
+var (
+	dbHasUnfinishedSignRequest    = mongodb.HasUnfinishedSignRequest
+	dbFindRouterSwapResult        = mongodb.FindRouterSwapResult
+	dbUpdateRouterSwapResult      = mongodb.UpdateRouterSwapResult
+	dbUpdateRouterSwapStatus      = mongodb.UpdateRouterSwapStatus
+	dbUpdateSignRequestsProcessed = mongodb.UpdateSignRequestsProcessed
 )
 
 // StartSwapJob swap job
@@ -55,6 +67,7 @@ func startRouterSwapJob(chainID string) {
 	defer mongodb.MgoWaitGroup.Done()
 	logWorker("swap", "start router swap job", "chainID", chainID)
 	for {
+		start := time.Now()
 		res, err := findRouterSwapToSwap(chainID)
 		if err != nil {
 			logWorkerError("swap", "find out router swap error", err)
@@ -71,7 +84,8 @@ func startRouterSwapJob(chainID string) {
 			switch {
 			case err == nil,
 				errors.Is(err, errAlreadySwapped),
-				errors.Is(err, errSwapChannelIsFull):
+				errors.Is(err, errSwapChannelIsFull),
+				errors.Is(err, errInsufficientLiquidity):
 			default:
 				logWorkerError("swap", "process router swap error", err, "chainID", chainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
 			}
@@ -80,6 +94,7 @@ func startRouterSwapJob(chainID string) {
 			logWorker("swap", "stop router swap job", "chainID", chainID)
 			return
 		}
+		metrics.ObserveJobLoop("swap", chainID, start)
 		restInJob(restIntervalInDoSwapJob)
 	}
 }
@@ -131,6 +146,11 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		return err
 	}
 
+	err = checkSwapLiquidity(swap)
+	if err != nil {
+		return err
+	}
+
 	biFromChainID, biToChainID, biValue, err := getFromToChainIDAndValue(fromChainID, toChainID, res.Value)
 	if err != nil {
 		return err
@@ -155,8 +175,12 @@ func processRouterSwap(swap *mongodb.MgoSwap) (err error) {
 		From:        routerMPC,
 		OriginFrom:  swap.From,
 		OriginTxTo:  swap.TxTo,
+		OriginTime:  res.TxTime,
 		OriginValue: biValue,
 	}
+	if swap.PayAnyToken {
+		args.Extra = &tokens.AllExtras{PayAnyToken: true}
+	}
 	args.SwapInfo, err = mongodb.ConvertFromSwapInfo(&swap.SwapInfo)
 	if err != nil {
 		return err
@@ -261,6 +285,7 @@ func processSwapTask(chainID string, swapChan <-chan *tokens.BuildTxArgs) {
 			switch {
 			case err == nil,
 				errors.Is(err, errAlreadySwapped),
+				errors.Is(err, errSignRequestUnfinished),
 				errors.Is(err, tokens.ErrNoBridgeForChainID):
 			default:
 				logWorkerError("doSwap", "process router swap failed", err, "args", args)
@@ -313,6 +338,11 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return tokens.ErrNoBridgeForChainID
 	}
 
+	err = checkUnfinishedSignRequest(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("doSwap", "build tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
@@ -329,7 +359,7 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	}
 
 	// recheck reswap before update db
-	res, err := mongodb.FindRouterSwapResult(fromChainID, txid, logIndex)
+	res, err := dbFindRouterSwapResult(fromChainID, txid, logIndex)
 	if err != nil {
 		return err
 	}
@@ -346,6 +376,7 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		SwapTx:    txHash,
 		SwapNonce: swapTxNonce,
 		MPC:       args.From,
+		SwapFee:   mongodb.ConvertToSwapFee(args.SwapFeeInfo),
 	}
 	if args.SwapValue != nil {
 		matchTx.SwapValue = args.SwapValue.String()
@@ -356,8 +387,9 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return err
 	}
 	isCachedSwapProcessed = true
+	_ = dbUpdateSignRequestsProcessed(fromChainID, txid, logIndex)
 
-	err = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxProcessed, now(), "")
+	err = dbUpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxProcessed, now(), "")
 	if err != nil {
 		logWorkerError("doSwap", "update router swap status failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
 		return err
@@ -401,6 +433,11 @@ func doSwapParallel(args *tokens.BuildTxArgs) (err error) {
 		return tokens.ErrNoBridgeForChainID
 	}
 
+	err = checkUnfinishedSignRequest(fromChainID, txid, logIndex)
+	if err != nil {
+		return err
+	}
+
 	rawTx, err := resBridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("doSwap", "build tx failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex)
@@ -433,7 +470,9 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 
 	// update database before sending transaction
 	addSwapHistory(fromChainID, txid, logIndex, txHash)
-	_ = updateSwapTx(fromChainID, txid, logIndex, txHash)
+	if updateSwapTx(fromChainID, txid, logIndex, txHash) == nil {
+		_ = mongodb.UpdateSignRequestsProcessed(fromChainID, txid, logIndex)
+	}
 
 	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
 	if err == nil && txHash != sentTxHash {
@@ -450,6 +489,7 @@ func reverifySwap(args *tokens.BuildTxArgs) {
 	toChainID := args.ToChainID.String()
 	txid := args.SwapID
 	logIndex := args.LogIndex
+	recordSignDisagree(toChainID)
 	verifyArgs := &tokens.VerifyArgs{
 		SwapType:      args.SwapType,
 		LogIndex:      logIndex,
@@ -472,6 +512,15 @@ func reverifySwap(args *tokens.BuildTxArgs) {
 	}
 }
 
+// CheckSwapNotInProcess check swap is not being built, signed or sent
+func CheckSwapNotInProcess(fromChainID, txid string, logIndex int) error {
+	cacheKey := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
+	if cachedSwapTasks.Contains(cacheKey) {
+		return errSwapInProcess
+	}
+	return checkUnfinishedSignRequest(fromChainID, txid, logIndex)
+}
+
 // DeleteCachedSwap delete cached swap
 func DeleteCachedSwap(fromChainID, txid string, logIndex int) {
 	cacheKey := mongodb.GetRouterSwapKey(fromChainID, txid, logIndex)
@@ -27,6 +27,18 @@ var (
 
 	maxCheckFailedSwapLifetime       = int64(2 * 24 * 3600)
 	restIntervalInCheckFailedSwapJob = 60 * time.Second
+
+	restIntervalInReorgWatchJob = 30 * time.Second
+
+	restIntervalInScanJob = 5 * time.Second
+
+	restIntervalInLiquidityJob = 30 * time.Second
+
+	restIntervalInCircuitBreakerJob = 30 * time.Second
+
+	restIntervalInMetricsJob = 60 * time.Second
+
+	restIntervalInWebhookJob = 5 * time.Second
 )
 
 func now() int64 {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
 	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/metrics"
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
@@ -35,6 +36,7 @@ func StartVerifyJob() {
 
 func startVerifyProducer() {
 	for {
+		start := time.Now()
 		septime := getSepTimeInFind(maxVerifyLifetime)
 		res, err := mongodb.FindRouterSwapsWithStatus(mongodb.TxNotStable, septime)
 		if err != nil {
@@ -55,6 +57,7 @@ func startVerifyProducer() {
 			logWorker("verify", "dispatch swap for verify", "fromChainID", swap.FromChainID, "toChainID", swap.ToChainID, "txid", swap.TxID, "logIndex", swap.LogIndex)
 			verifySwapCh <- swap // produce
 		}
+		metrics.ObserveJobLoop("verify", "", start)
 		restInJob(restIntervalInVerifyJob)
 	}
 }
@@ -146,11 +149,19 @@ func processRouterSwapVerify(swap *mongodb.MgoSwap) (err error) {
 	case err == nil:
 		if router.IsBigValueSwap(swapInfo) {
 			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxWithBigValue, now(), "big swap value")
+		} else if passed, errq := reserveOutflowQuota(swapInfo); errq != nil {
+			isProcessed = false
+			return errq
+		} else if !passed {
+			dbErr = mongodb.UpdateRouterSwapStatus(fromChainID, txid, logIndex, mongodb.TxHeldByOutflowQuota, now(), "exceed outflow quota")
 		} else {
 			dbErr = mongodb.PassRouterSwapVerify(fromChainID, txid, logIndex, now())
 			if dbErr == nil {
 				dbErr = AddInitialSwapResult(swapInfo, mongodb.MatchTxEmpty)
 			}
+			if dbErr == nil {
+				addSourceReorgWatch(bridge, swapInfo)
+			}
 		}
 	case errors.Is(err, tokens.ErrTxNotStable),
 		errors.Is(err, tokens.ErrRPCQueryError):
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/anyswap/CrossChain-Router/v3/cmd/utils"
+	"github.com/anyswap/CrossChain-Router/v3/mongodb"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+const (
+	webhookSignatureHeader = "X-Router-Signature"
+	webhookEventHeader     = "X-Router-Event"
+
+	defaultWebhookMaxRetries = 10
+	defaultWebhookRetryDelay = int64(10) // seconds
+	defaultWebhookTimeout    = 10        // seconds
+	maxWebhookRetryDelay     = int64(3600)
+
+	webhookDeliveriesPerLoop = int64(100)
+)
+
+// StartWebhookJob webhook delivery job
+func StartWebhookJob() {
+	logWorker("webhook", "start webhook delivery job")
+	cfg := params.GetWebhookConfig()
+	if cfg == nil {
+		logWorker("webhook", "stop webhook delivery job as disabled")
+		return
+	}
+
+	mongodb.MgoWaitGroup.Add(1)
+	go doWebhookJob(cfg)
+}
+
+func doWebhookJob(cfg *params.WebhookConfig) {
+	defer mongodb.MgoWaitGroup.Done()
+	for {
+		res, err := mongodb.FindWebhookDeliveriesToSend(webhookDeliveriesPerLoop)
+		if err != nil {
+			logWorkerError("webhook", "find webhook deliveries error", err)
+		}
+		for _, delivery := range res {
+			if utils.IsCleanuping() {
+				logWorker("webhook", "stop webhook delivery job")
+				return
+			}
+			processWebhookDelivery(delivery, cfg)
+		}
+		if utils.IsCleanuping() {
+			logWorker("webhook", "stop webhook delivery job")
+			return
+		}
+		if len(res) < int(webhookDeliveriesPerLoop) {
+			restInJob(restIntervalInWebhookJob)
+		}
+	}
+}
+
+func processWebhookDelivery(delivery *mongodb.MgoWebhookDelivery, cfg *params.WebhookConfig) {
+	attempts := delivery.Attempts + 1
+	err := postWebhook(delivery, cfg)
+	if err == nil {
+		logWorkerTrace("webhook", "webhook delivered", "key", delivery.Key, "attempts", attempts)
+		_ = mongodb.UpdateWebhookDelivery(delivery.Key, mongodb.WebhookDelivered, attempts, 0, "")
+		return
+	}
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+	if attempts > maxRetries {
+		logWorkerWarn("webhook", "webhook delivery failed", "key", delivery.Key, "attempts", attempts, "err", err)
+		_ = mongodb.UpdateWebhookDelivery(delivery.Key, mongodb.WebhookFailed, attempts, 0, err.Error())
+		return
+	}
+	nextTime := now() + getWebhookRetryDelay(cfg.RetryDelay, attempts)
+	logWorkerTrace("webhook", "webhook delivery will retry", "key", delivery.Key, "attempts", attempts, "nextTime", nextTime, "err", err)
+	_ = mongodb.UpdateWebhookDelivery(delivery.Key, mongodb.WebhookPending, attempts, nextTime, err.Error())
+}
+
+// getWebhookRetryDelay exponential backoff delay after attempts
+func getWebhookRetryDelay(retryDelay int64, attempts int) int64 {
+	if retryDelay <= 0 {
+		retryDelay = defaultWebhookRetryDelay
+	}
+	delay := retryDelay
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryDelay {
+		delay = maxWebhookRetryDelay
+	}
+	return delay
+}
+
+// signWebhookPayload hex encoded hmac-sha256 of payload
+func signWebhookPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(delivery *mongodb.MgoWebhookDelivery, cfg *params.WebhookConfig) error {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		webhookEventHeader:     delivery.Event,
+		webhookSignatureHeader: signWebhookPayload(cfg.Secret, delivery.Payload),
+	}
+	resp, err := client.HTTPRawPost(delivery.URL, delivery.Payload, nil, headers, timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+	}
+	return nil
+}
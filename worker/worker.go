@@ -25,6 +25,15 @@ func StartRouterSwapWork(isServer bool) {
 		return
 	}
 
+	StartCircuitBreakerJob()
+	time.Sleep(interval)
+
+	StartScanJob()
+	time.Sleep(interval)
+
+	StartResumeSignJob()
+	time.Sleep(interval)
+
 	StartSwapJob()
 	time.Sleep(interval)
 
@@ -41,4 +50,16 @@ func StartRouterSwapWork(isServer bool) {
 	time.Sleep(interval)
 
 	StartCheckFailedSwapJob()
+	time.Sleep(interval)
+
+	StartReorgWatchJob()
+	time.Sleep(interval)
+
+	StartLiquidityJob()
+	time.Sleep(interval)
+
+	StartMetricsJob()
+	time.Sleep(interval)
+
+	StartWebhookJob()
 }